@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+const rotateKeysUsage = "usage: pcrm rotate-keys"
+
+// runRotateKeys re-encrypts every sensitive field not sealed with the active
+// key and reports how many records changed.
+func runRotateKeys(ctx context.Context, encryption *services.EncryptionService, activeKeyID string, args []string) error {
+	if len(args) > 0 {
+		return errors.New(rotateKeysUsage)
+	}
+	changed, err := encryption.RotateKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("stopped after %d records: %w", changed, err)
+	}
+	if activeKeyID == "" {
+		fmt.Printf("decrypted %d records\n", changed)
+		return nil
+	}
+	fmt.Printf("re-encrypted %d records with key %s\n", changed, activeKeyID)
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/encryption"
+	"github.com/lincentpega/pcrm/internal/migrate"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+// rotateKeys runs the rotate-keys command with cfg and returns what it
+// printed.
+func rotateKeys(t *testing.T, db *sqlx.DB, cfg config.EncryptionConfig) string {
+	t.Helper()
+	keys, err := encryption.NewKeyring(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	err = runRotateKeys(context.Background(), services.NewEncryptionService(services.NewUnitOfWork(db, time.Second, keys)), keys.ActiveKeyID(), nil)
+	os.Stdout = stdout
+	writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
+
+func TestRotateKeys(t *testing.T) {
+	ctx := context.Background()
+	db, err := config.NewDatabase(config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "pcrm.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	statements := []string{
+		`INSERT INTO users (id, username, password_hash) VALUES (1, 'alice', '')`,
+		`INSERT INTO people (id, user_id, first_name) VALUES (1, 1, 'Bob')`,
+		`INSERT INTO contact_types (id, user_id, name) VALUES (100, 1, 'Signal')`,
+		`INSERT INTO connection_sources (person_id, meeting_story) VALUES (1, 'Met at a conference')`,
+		`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 250)
+		 INSERT INTO contacts (person_id, contact_type_id, content) SELECT 1, 100, 'contact ' || i FROM n`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key := func(id string, b byte) config.EncryptionKey {
+		return config.EncryptionKey{ID: id, Key: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, encryption.KeySize))}
+	}
+	k1, k2 := key("k1", 1), key("k2", 2)
+	steps := []struct {
+		cfg    config.EncryptionConfig
+		output string
+		keyID  string
+	}{
+		{cfg: config.EncryptionConfig{ActiveKey: "k1", Keys: []config.EncryptionKey{k1}}, output: "re-encrypted 251 records with key k1", keyID: "k1"},
+		{cfg: config.EncryptionConfig{ActiveKey: "k1", Keys: []config.EncryptionKey{k1}}, output: "re-encrypted 0 records with key k1", keyID: "k1"},
+		{cfg: config.EncryptionConfig{ActiveKey: "k2", Keys: []config.EncryptionKey{k1, k2}}, output: "re-encrypted 251 records with key k2", keyID: "k2"},
+		{cfg: config.EncryptionConfig{Keys: []config.EncryptionKey{k2}}, output: "decrypted 251 records"},
+	}
+	for _, step := range steps {
+		if output := rotateKeys(t, db, step.cfg); strings.TrimSpace(output) != step.output {
+			t.Errorf("output = %q, want %q", output, step.output)
+		}
+		var sealed, plaintext int
+		if err := db.Get(&sealed, `SELECT (SELECT COUNT(*) FROM contacts WHERE key_id = ?) + (SELECT COUNT(*) FROM connection_sources WHERE key_id = ?)`, step.keyID, step.keyID); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Get(&plaintext, `SELECT COUNT(*) FROM contacts WHERE content = 'contact 250'`); err != nil {
+			t.Fatal(err)
+		}
+		if step.keyID != "" && (sealed != 251 || plaintext != 0) {
+			t.Errorf("%d records sealed with %s, %d in plaintext, want all 251 sealed", sealed, step.keyID, plaintext)
+		}
+		if step.keyID == "" && plaintext != 1 {
+			t.Errorf("contact 250 is not stored in plaintext after decrypting")
+		}
+	}
+}
+
+func TestRotateKeysUsage(t *testing.T) {
+	if err := runRotateKeys(context.Background(), nil, "", []string{"now"}); err == nil || err.Error() != rotateKeysUsage {
+		t.Errorf("err = %v, want the usage", err)
+	}
+}
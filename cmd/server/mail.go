@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/importers"
+	"github.com/lincentpega/pcrm/internal/mappers"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+const importMailUsage = "usage: pcrm import-mail --user USERNAME [--dry-run] --mbox FILE | --maildir DIR | --imap imaps://LOGIN@HOST/MAILBOX"
+
+// runImportMail executes the import-mail subcommand described by args,
+// logging the emails of a mailbox as conversations of the user. The IMAP
+// password is read like the one of the user subcommand.
+func runImportMail(ctx context.Context, imports *services.ImportService, users *services.UserService, stdin *os.File, args []string) error {
+	flags := flag.NewFlagSet("import-mail", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	username := flags.String("user", "", "user the emails are logged for")
+	dryRun := flags.Bool("dry-run", false, "report what would be logged without storing it")
+	mbox := flags.String("mbox", "", "mbox file to read")
+	maildir := flags.String("maildir", "", "Maildir directory to read")
+	imapURL := flags.String("imap", "", "IMAP mailbox to read")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || *username == "" {
+		return errors.New(importMailUsage)
+	}
+	sources := 0
+	for _, source := range []string{*mbox, *maildir, *imapURL} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New(importMailUsage)
+	}
+	user, err := users.GetUserByUsername(ctx, *username)
+	if err != nil {
+		return err
+	}
+	emails, err := readMailbox(*mbox, *maildir, *imapURL, stdin)
+	if err != nil {
+		return err
+	}
+	results, err := imports.ImportEmails(auth.WithUserID(ctx, user.ID), mappers.EmailsToDomain(emails), *dryRun)
+	if err != nil {
+		return err
+	}
+	response := mappers.EmailImportsToResponse(emails, results, *dryRun)
+	for _, item := range response.Items {
+		detail := item.Subject
+		if len(item.Errors) > 0 {
+			detail = item.Errors[0].Message
+		}
+		fmt.Printf("%-9s %-12s %s\n", item.Status, item.Source, detail)
+	}
+	verb := "logged"
+	if *dryRun {
+		verb = "would log"
+	}
+	fmt.Printf("%s %d conversation(s) from %d of %d email(s); %d duplicate, %d unmatched, %d invalid\n",
+		verb, response.Conversations, response.Imported, response.Emails, response.Duplicates, response.Unmatched, response.Invalid)
+	return nil
+}
+
+func readMailbox(mbox, maildir, imapURL string, stdin *os.File) ([]importers.Email, error) {
+	switch {
+	case mbox != "":
+		file, err := os.Open(mbox)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mbox: %w", err)
+		}
+		defer file.Close()
+		return importers.ReadMbox(file)
+	case maildir != "":
+		return importers.ReadMaildir(maildir)
+	default:
+		mailbox, err := importers.ParseIMAPURL(imapURL)
+		if err != nil {
+			return nil, err
+		}
+		if mailbox.Password, err = readPassword(stdin); err != nil {
+			return nil, err
+		}
+		return importers.ReadIMAP(mailbox)
+	}
+}
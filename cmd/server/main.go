@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"errors"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,9 +17,14 @@ import (
 
 	_ "github.com/lincentpega/pcrm/docs"
 	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/encryption"
 	"github.com/lincentpega/pcrm/internal/handlers/api"
+	"github.com/lincentpega/pcrm/internal/logging"
+	"github.com/lincentpega/pcrm/internal/metrics"
 	"github.com/lincentpega/pcrm/internal/middleware"
-	"github.com/lincentpega/pcrm/internal/repository"
+	"github.com/lincentpega/pcrm/internal/migrate"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/tracing"
 )
 
 // @title Personal CRM API
@@ -35,70 +42,133 @@ import (
 // @host localhost:8080
 // @BasePath /
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Personal access token, sent as "Bearer <token>". Browsers may use the pcrm_session cookie set by /api/auth/login instead.
+
 func main() {
-	cfg, err := config.Load("config.yml")
+	configPath := flag.String("config", os.Getenv("PCRM_CONFIG"), "path to the YAML config file (default "+config.DefaultPath+" when present)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		exitWithError("Failed to load config", err)
+	}
+
+	logger, err := logging.New(cfg.Logging, os.Stderr)
+	if err != nil {
+		exitWithError("Failed to configure logging", err)
+	}
+	slog.SetDefault(logger)
+
+	keys, err := encryption.NewKeyring(cfg.Encryption)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		exitWithError("Failed to load encryption keys", err)
 	}
 
 	db, err := config.NewDatabase(cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		exitWithError("Failed to connect to database", err)
 	}
 	defer db.Close()
 
-	personRepo := repository.NewPersonRepository(db)
-	contactRepo := repository.NewContactRepository(db)
-	connectionSourceRepo := repository.NewConnectionSourceRepository(db)
-	birthDateInfoRepo := repository.NewBirthDateInfoRepository(db)
-	conversationRepo := repository.NewConversationRepository(db)
+	if args := flag.Args(); len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := runMigrate(context.Background(), db, args[1:]); err != nil {
+				exitWithError("Migration failed", err)
+			}
+		case "token":
+			uow := services.NewUnitOfWork(db, cfg.Database.QueryTimeout, keys)
+			if err := runToken(context.Background(), services.NewTokenService(uow), services.NewUserService(uow, cfg.Auth.SessionTTL), args[1:]); err != nil {
+				exitWithError("Token command failed", err)
+			}
+		case "user":
+			uow := services.NewUnitOfWork(db, cfg.Database.QueryTimeout, keys)
+			users := services.NewUserService(uow, cfg.Auth.SessionTTL)
+			if err := runUser(context.Background(), users, services.NewOIDCService(cfg.Auth.OIDC, uow, users), os.Stdin, args[1:]); err != nil {
+				exitWithError("User command failed", err)
+			}
+		case "import-mail":
+			uow := services.NewUnitOfWork(db, cfg.Database.QueryTimeout, keys)
+			if err := runImportMail(context.Background(), services.NewImportService(uow), services.NewUserService(uow, cfg.Auth.SessionTTL), os.Stdin, args[1:]); err != nil {
+				exitWithError("Mail import failed", err)
+			}
+		case "rotate-keys":
+			encryptionService := services.NewEncryptionService(services.NewUnitOfWork(db, cfg.Database.QueryTimeout, keys))
+			if err := runRotateKeys(context.Background(), encryptionService, keys.ActiveKeyID(), args[1:]); err != nil {
+				exitWithError("Key rotation failed", err)
+			}
+		default:
+			exitWithError("Unknown command", fmt.Errorf("%q is not a command", args[0]))
+		}
+		return
+	}
+
+	migrator, err := migrate.New(db)
+	if err != nil {
+		exitWithError("Failed to load migrations", err)
+	}
+
+	if cfg.Database.AutoMigrate {
+		if err := applyPendingMigrations(migrator); err != nil {
+			exitWithError("Failed to apply migrations", err)
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, os.Stdout)
+	if err != nil {
+		exitWithError("Failed to configure tracing", err)
+	}
 
-	personAPI := api.NewPersonAPI(personRepo, contactRepo)
-	contactAPI := api.NewContactAPI(contactRepo)
-	connectionSourceAPI := api.NewConnectionSourceAPI(connectionSourceRepo, personRepo)
-	birthDateInfoAPI := api.NewBirthDateInfoAPI(birthDateInfoRepo, personRepo)
-	conversationAPI := api.NewConversationAPI(conversationRepo, personRepo)
+	uow := services.NewUnitOfWork(db, cfg.Database.QueryTimeout, keys)
+	users := services.NewUserService(uow, cfg.Auth.SessionTTL)
+	health := services.NewHealthService(db, migrator, cfg.Database.QueryTimeout)
 
 	middlewareChain := alice.New(
-		middleware.LoggingMiddleware,
-		middleware.RecoveryMiddleware,
-		middleware.CORSMiddleware,
+		middleware.RequestIDMiddleware,
+		middleware.TracingMiddleware,
+		middleware.LoggingMiddleware(logger),
 	)
 
 	mux := http.NewServeMux()
 
+	if cfg.Metrics.Enabled {
+		registry := metrics.NewRegistry(db.DB, services.NewStatsService(uow))
+		middlewareChain = middlewareChain.Append(middleware.MetricsMiddleware(metrics.NewHTTPMetrics(registry)))
+		mux.Handle("GET /metrics", metrics.Handler(registry))
+	}
+
+	middlewareChain = middlewareChain.Append(
+		middleware.RecoveryMiddleware(logger),
+		middleware.CORSMiddleware(cfg.CORS),
+		middleware.BodyLimitMiddleware(cfg.Server.MaxBodyBytes, cfg.Server.MaxImportBytes),
+	)
+
+	if cfg.RateLimit.Enabled {
+		middlewareChain = middlewareChain.Append(middleware.AuthFailureLimitMiddleware(cfg.RateLimit))
+	}
+	if cfg.Auth.Enabled {
+		middlewareChain = middlewareChain.Append(middleware.AuthMiddleware(services.NewTokenService(uow), users))
+	} else {
+		middlewareChain = middlewareChain.Append(middleware.LocalUserMiddleware(users, cfg.Auth.LocalUser))
+	}
+	if cfg.RateLimit.Enabled {
+		middlewareChain = middlewareChain.Append(middleware.RateLimitMiddleware(cfg.RateLimit))
+	}
+	middlewareChain = middlewareChain.Append(middleware.RouteMiddleware)
+
 	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/people", http.StatusSeeOther)
 	})
 
-	// REST API routes
-	mux.HandleFunc("GET /api/people", personAPI.ListPeople)
-	mux.HandleFunc("POST /api/people", personAPI.CreatePerson)
-	mux.HandleFunc("GET /api/people/{id}", personAPI.GetPerson)
-	mux.HandleFunc("PUT /api/people/{id}", personAPI.UpdatePerson)
-	mux.HandleFunc("DELETE /api/people/{id}", personAPI.DeletePerson)
-
-	mux.HandleFunc("GET /api/people/{personId}/contacts", contactAPI.ListContactsByPerson)
-	mux.HandleFunc("POST /api/people/{personId}/contacts", contactAPI.CreateContact)
-	mux.HandleFunc("GET /api/contacts/{id}", contactAPI.GetContact)
-	mux.HandleFunc("PUT /api/contacts/{id}", contactAPI.UpdateContact)
-	mux.HandleFunc("DELETE /api/contacts/{id}", contactAPI.DeleteContact)
-	mux.HandleFunc("GET /api/contact-types", contactAPI.ListContactTypes)
-
-	mux.HandleFunc("GET /api/people/{personId}/conversations", conversationAPI.ListConversationsByPerson)
-	mux.HandleFunc("POST /api/people/{personId}/conversations", conversationAPI.CreateConversation)
-	mux.HandleFunc("GET /api/conversations/{id}", conversationAPI.GetConversation)
-	mux.HandleFunc("PUT /api/conversations/{id}", conversationAPI.UpdateConversation)
-	mux.HandleFunc("DELETE /api/conversations/{id}", conversationAPI.DeleteConversation)
-	mux.HandleFunc("GET /api/conversation-types", conversationAPI.ListConversationTypes)
-
-	mux.HandleFunc("GET /api/people/{personId}/connection-source", connectionSourceAPI.GetConnectionSource)
-	mux.HandleFunc("PUT /api/people/{personId}/connection-source", connectionSourceAPI.UpsertConnectionSource)
-	mux.HandleFunc("DELETE /api/people/{personId}/connection-source", connectionSourceAPI.DeleteConnectionSource)
-
-	mux.HandleFunc("GET /api/people/{personId}/birth-date-info", birthDateInfoAPI.GetBirthDateInfo)
-	mux.HandleFunc("PUT /api/people/{personId}/birth-date-info", birthDateInfoAPI.UpsertBirthDateInfo)
-	mux.HandleFunc("DELETE /api/people/{personId}/birth-date-info", birthDateInfoAPI.DeleteBirthDateInfo)
+	api.RegisterRoutes(mux, uow)
+	api.RegisterAuthRoutes(mux, users, cfg.Auth.AllowRegistration, cfg.Auth.SecureCookies)
+	if cfg.Auth.OIDC.Enabled {
+		api.RegisterOIDCRoutes(mux, services.NewOIDCService(cfg.Auth.OIDC, uow, users), cfg.Auth.SecureCookies, cfg.Auth.OIDC.PostLoginRedirect)
+	}
+	api.RegisterHealthRoutes(mux, health)
 
 	// Swagger documentation
 	mux.Handle("GET /swagger/", httpSwagger.WrapHandler)
@@ -114,9 +184,9 @@ func main() {
 	}
 
 	go func() {
-		log.Printf("Starting server on %s", cfg.Address())
+		slog.Info("Starting server", "address", cfg.Address())
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("Server failed to start: %v", err)
+			exitWithError("Server failed to start", err)
 		}
 	}()
 
@@ -124,14 +194,26 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	health.StartShutdown()
+	slog.Info("Draining server", "delay", cfg.Server.ShutdownDelay.String())
+	time.Sleep(cfg.Server.ShutdownDelay)
+	slog.Info("Shutting down server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		exitWithError("Server forced to shutdown", err)
 	}
 
-	log.Println("Server exited")
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+
+	slog.Info("Server exited")
+}
+
+func exitWithError(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/lincentpega/pcrm/internal/migrate"
+)
+
+const migrateUsage = "usage: pcrm migrate up | down [N] | status | force VERSION"
+
+// runMigrate executes the migrate subcommand described by args against db.
+func runMigrate(ctx context.Context, db *sqlx.DB, args []string) error {
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		fmt.Printf("applied %d migration(s)\n", applied)
+		return err
+	case "down":
+		steps, err := optionalCount(args[1:])
+		if err != nil {
+			return err
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		fmt.Printf("reverted %d migration(s)\n", reverted)
+		return err
+	case "status":
+		return printMigrationStatus(ctx, migrator)
+	case "force":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.Force(ctx, version)
+	}
+	return errors.New(migrateUsage)
+}
+
+func optionalCount(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count <= 0 || len(args) > 1 {
+		return 0, errors.New(migrateUsage)
+	}
+	return count, nil
+}
+
+func printMigrationStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	state, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version: %d, dirty: %t\n", state.Version, state.Dirty)
+	for _, migration := range migrator.Migrations() {
+		status := "pending"
+		if migration.Version <= state.Version {
+			status = "applied"
+		}
+		fmt.Printf("%06d %-40s %s\n", migration.Version, migration.Name, status)
+	}
+	return nil
+}
+
+func applyPendingMigrations(migrator *migrate.Migrator) error {
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		return err
+	}
+	slog.Info("Applied migrations", "count", applied)
+	return nil
+}
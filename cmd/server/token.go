@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/mappers"
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+const tokenUsage = "usage: pcrm token create --user USERNAME --name NAME [--scope read|write] [--expires-in DURATION]"
+
+// runToken executes the token subcommand described by args, printing the
+// secret of a created token to stdout.
+func runToken(ctx context.Context, tokens *services.TokenService, users *services.UserService, args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New(tokenUsage)
+	}
+	flags := flag.NewFlagSet("token create", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	username := flags.String("user", "", "user the token acts for")
+	name := flags.String("name", "", "token name")
+	scope := flags.String("scope", models.TokenScopeWrite, "token scope, read or write")
+	expiresIn := flags.Duration("expires-in", 0, "lifetime of the token, e.g. 720h; zero never expires")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 || *username == "" {
+		return errors.New(tokenUsage)
+	}
+	now := time.Now()
+	req := dto.APITokenRequest{Name: *name, Scope: *scope}
+	if *expiresIn != 0 {
+		expiresAt := now.Add(*expiresIn)
+		req.ExpiresAt = &expiresAt
+	}
+	if err := validators.ValidateAPITokenRequest(&req, now); err != nil {
+		return err
+	}
+	user, err := users.GetUserByUsername(ctx, *username)
+	if err != nil {
+		return err
+	}
+	token := mappers.APITokenRequestToDomain(&req)
+	secret, err := tokens.CreateToken(auth.WithUserID(ctx, user.ID), token)
+	if err != nil {
+		return err
+	}
+	fmt.Println(secret)
+	return nil
+}
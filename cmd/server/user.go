@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+const userUsage = "usage: pcrm user create|passwd --username NAME | link --username NAME --subject SUB"
+
+// runUser executes the user subcommand described by args. The password is
+// prompted for without echo on a terminal and otherwise read as the first
+// line of stdin.
+func runUser(ctx context.Context, users *services.UserService, oidc *services.OIDCService, stdin *os.File, args []string) error {
+	if len(args) == 0 || !slices.Contains([]string{"create", "passwd", "link"}, args[0]) {
+		return errors.New(userUsage)
+	}
+	flags := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	username := flags.String("username", "", "account name")
+	subject := flags.String("subject", "", "subject of the single sign-on account to link")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 || *username == "" || (args[0] == "link") != (*subject != "") {
+		return errors.New(userUsage)
+	}
+	if args[0] == "link" {
+		if err := oidc.Link(ctx, *username, *subject); err != nil {
+			return err
+		}
+		fmt.Printf("linked %s to subject %s\n", *username, *subject)
+		return nil
+	}
+	password, err := readPassword(stdin)
+	if err != nil {
+		return err
+	}
+	req := dto.CredentialsRequest{Username: *username, Password: password}
+	if err := validators.ValidateCredentialsRequest(&req); err != nil {
+		return err
+	}
+	if args[0] == "create" {
+		user, err := users.Register(ctx, req.Username, req.Password)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created user %s with id %d\n", user.Username, user.ID)
+		return nil
+	}
+	user, err := users.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		return err
+	}
+	if err := users.SetPassword(ctx, user.ID, req.Password); err != nil {
+		return err
+	}
+	fmt.Printf("changed the password of %s\n", user.Username)
+	return nil
+}
+
+func readPassword(stdin *os.File) (string, error) {
+	if term.IsTerminal(int(stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Password: ")
+		password, err := term.ReadPassword(int(stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return string(password), nil
+	}
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
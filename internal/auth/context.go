@@ -0,0 +1,22 @@
+// Package auth identifies the user a request acts for and hashes account
+// passwords.
+package auth
+
+import "context"
+
+// SessionCookieName is the cookie that carries a browser session.
+const SessionCookieName = "pcrm_session"
+
+type userIDKey struct{}
+
+// WithUserID returns a context acting for the user with the given ID. The
+// repositories only read and change data owned by that user.
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserID returns the user ctx acts for and whether there is one.
+func UserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int64)
+	return userID, ok
+}
@@ -0,0 +1,49 @@
+// Package buildinfo describes the running binary. Commit and BuildTime are
+// set at link time:
+//
+//	go build -ldflags "-X github.com/lincentpega/pcrm/internal/buildinfo.Commit=$(git rev-parse HEAD) -X github.com/lincentpega/pcrm/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them the VCS details recorded by the Go toolchain are used.
+package buildinfo
+
+import (
+	"cmp"
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Commit    string
+	BuildTime string
+)
+
+type Info struct {
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// Get returns the build details, reporting "unknown" for any that are
+// missing, e.g. under go run.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		var modified bool
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = cmp.Or(info.Commit, setting.Value)
+			case "vcs.time":
+				info.BuildTime = cmp.Or(info.BuildTime, setting.Value)
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if modified && Commit == "" && info.Commit != "" {
+			info.Commit += "-dirty"
+		}
+	}
+	info.Commit = cmp.Or(info.Commit, "unknown")
+	info.BuildTime = cmp.Or(info.BuildTime, "unknown")
+	return info
+}
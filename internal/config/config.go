@@ -1,30 +1,78 @@
 package config
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/netip"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// DefaultPath is the configuration file read when no path is given and the
+// file exists.
+const DefaultPath = "config.yml"
+
 type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Database DatabaseConfig `yaml:"database"`
 	Logging  LoggingConfig  `yaml:"logging"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+	Auth       AuthConfig       `yaml:"auth"`
+	CORS       CORSConfig       `yaml:"cors"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Encryption EncryptionConfig `yaml:"encryption"`
 }
 
 type ServerConfig struct {
 	Port int    `yaml:"port"`
 	Host string `yaml:"host"`
+	// MaxBodyBytes caps the size of request bodies; larger requests are
+	// rejected with 413.
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	// MaxImportBytes replaces MaxBodyBytes for the import endpoints under
+	// /api/import, whose archives are larger than other requests.
+	MaxImportBytes int64 `yaml:"max_import_bytes"`
+	// ShutdownDelay is how long /readyz reports not ready before the server
+	// stops accepting connections, so that load balancers stop routing to it.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
 }
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DatabaseConfig struct {
+	// Driver selects the storage backend: postgres (default) or sqlite.
+	Driver string `yaml:"driver"`
+	// URL configures the connection as a postgres:// or sqlite: URL and takes
+	// precedence over the individual connection settings.
+	URL string `yaml:"url"`
+	// Path is the SQLite database file; the PostgreSQL settings below are
+	// ignored for sqlite.
+	Path     string `yaml:"path"`
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
 	Name     string `yaml:"name"`
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
+	// PasswordFile names a file holding the password, e.g. a mounted secret.
+	PasswordFile string `yaml:"password_file"`
 	SSLMode  string `yaml:"sslmode"`
+	// AutoMigrate applies pending migrations when the server starts.
+	AutoMigrate bool `yaml:"auto_migrate"`
+	// QueryTimeout bounds every statement; zero disables the limit.
+	QueryTimeout time.Duration `yaml:"query_timeout"`
 }
 
 type LoggingConfig struct {
@@ -32,25 +80,432 @@ type LoggingConfig struct {
 	Format string `yaml:"format"`
 }
 
+type MetricsConfig struct {
+	// Enabled exposes Prometheus metrics on GET /metrics.
+	Enabled bool `yaml:"enabled"`
+}
+
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+)
+
+type TracingConfig struct {
+	// Enabled records OpenTelemetry spans for requests and SQL statements.
+	Enabled bool `yaml:"enabled"`
+	// Exporter sends spans to an OTLP/HTTP collector (otlp) or prints them to
+	// standard output (stdout) for local use.
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the OTLP/HTTP traces URL, e.g.
+	// http://localhost:4318/v1/traces. When empty the exporter reads the
+	// standard OTEL_EXPORTER_OTLP_* variables.
+	Endpoint    string `yaml:"endpoint"`
+	ServiceName string `yaml:"service_name"`
+	// SampleRatio is the fraction of new traces recorded; requests that join
+	// an incoming trace follow the caller's sampling decision.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+type CORSConfig struct {
+	// AllowedOrigins lists the origins browsers may call the API from, such
+	// as https://crm.example.com. A "*" matches any run of characters, e.g.
+	// https://*.example.com or http://localhost:*. When empty only same
+	// origin requests are possible.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+	AllowedHeaders []string `yaml:"allowed_headers"`
+	// ExposedHeaders are the response headers scripts may read.
+	ExposedHeaders []string `yaml:"exposed_headers"`
+	// AllowCredentials lets browsers send cookies with cross-origin
+	// requests. It cannot be combined with an allowed origin of "*".
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+type RateLimitConfig struct {
+	// Enabled throttles /api requests per API token, or per client IP for
+	// requests without one, answering 429 once a bucket is empty.
+	Enabled bool `yaml:"enabled"`
+	// TrustedProxies lists the addresses or CIDR ranges of reverse proxies
+	// whose X-Forwarded-For header is believed when finding the client IP.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// Read applies to GET and HEAD requests, Write to every other method
+	// and Auth to the login, registration and single sign-on endpoints and,
+	// per client IP, to requests rejected for bad credentials.
+	Read  RateLimitRule `yaml:"read"`
+	Write RateLimitRule `yaml:"write"`
+	Auth  RateLimitRule `yaml:"auth"`
+}
+
+// RateLimitRule is a token bucket refilled with Rate requests per second
+// and holding at most Burst requests. A zero Rate disables the limit.
+type RateLimitRule struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type EncryptionConfig struct {
+	// ActiveKey names the key that encrypts new and re-encrypted records.
+	// When empty records are stored in plaintext, and "pcrm rotate-keys"
+	// decrypts the ones already encrypted.
+	ActiveKey string `yaml:"active_key"`
+	// Keys lists every key still needed to decrypt stored records.
+	Keys []EncryptionKey `yaml:"keys"`
+}
+
+type EncryptionKey struct {
+	// ID is stored with every record encrypted under this key.
+	ID string `yaml:"id"`
+	// Key is a base64-encoded 32-byte AES-256 key, e.g. the output of
+	// "openssl rand -base64 32".
+	Key string `yaml:"key"`
+	// KeyFile names a file holding the base64-encoded key.
+	KeyFile string `yaml:"key_file"`
+}
+
+type AuthConfig struct {
+	// Enabled requires a personal access token or a session cookie on every
+	// /api request. When disabled every request acts as LocalUser.
+	Enabled bool `yaml:"enabled"`
+	// AllowRegistration lets anyone create an account through
+	// POST /api/auth/register; otherwise accounts are created with
+	// "pcrm user create".
+	AllowRegistration bool `yaml:"allow_registration"`
+	// SessionTTL is how long a login stays valid.
+	SessionTTL time.Duration `yaml:"session_ttl"`
+	// SecureCookies marks the session cookie Secure, so browsers only send
+	// it over HTTPS.
+	SecureCookies bool `yaml:"secure_cookies"`
+	// LocalUser owns all data while authentication is disabled and is
+	// created on first use when missing.
+	LocalUser string     `yaml:"local_user"`
+	OIDC      OIDCConfig `yaml:"oidc"`
+}
+
+type OIDCConfig struct {
+	// Enabled offers single sign-on through an OpenID Connect provider at
+	// GET /api/auth/oidc/login.
+	Enabled bool `yaml:"enabled"`
+	// Issuer is the provider URL its discovery document is served under,
+	// e.g. https://id.example.com/realms/home.
+	Issuer       string `yaml:"issuer"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// ClientSecretFile names a file holding the client secret.
+	ClientSecretFile string `yaml:"client_secret_file"`
+	// RedirectURL is this server's callback as registered with the
+	// provider, ending in /api/auth/oidc/callback.
+	RedirectURL string   `yaml:"redirect_url"`
+	Scopes      []string `yaml:"scopes"`
+	// UsernameClaim names the ID token claim that becomes the username of
+	// users created on their first login.
+	UsernameClaim string `yaml:"username_claim"`
+	// CreateUsers creates a user for every subject logging in for the first
+	// time; otherwise subjects must be linked with "pcrm user link".
+	CreateUsers bool `yaml:"create_users"`
+	// PostLoginRedirect is where the browser goes after a login.
+	PostLoginRedirect string `yaml:"post_login_redirect"`
+}
+
+// Default returns the configuration used for every setting that neither the
+// file nor the environment provides.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{Port: 8080, Host: "localhost", MaxBodyBytes: 1 << 20, MaxImportBytes: 32 << 20, ShutdownDelay: 5 * time.Second},
+		Database: DatabaseConfig{
+			Driver:       DriverPostgres,
+			Path:         "pcrm.db",
+			Host:         "localhost",
+			Port:         5432,
+			SSLMode:      "disable",
+			QueryTimeout: 5 * time.Second,
+		},
+		Logging: LoggingConfig{Level: "info", Format: "json"},
+		Metrics: MetricsConfig{Enabled: true},
+		Tracing: TracingConfig{
+			Exporter:    TracingExporterOTLP,
+			ServiceName: "pcrm",
+			SampleRatio: 1,
+		},
+		Auth: AuthConfig{
+			Enabled:       true,
+			SessionTTL:    30 * 24 * time.Hour,
+			SecureCookies: true,
+			LocalUser:     "owner",
+			OIDC: OIDCConfig{
+				Scopes:            []string{"openid", "profile", "email"},
+				UsernameClaim:     "preferred_username",
+				CreateUsers:       true,
+				PostLoginRedirect: "/",
+			},
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+			AllowedHeaders: []string{"Content-Type", "Authorization", "X-Request-ID"},
+			ExposedHeaders: []string{"X-Request-ID"},
+			MaxAge:         10 * time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Read:    RateLimitRule{Rate: 20, Burst: 40},
+			Write:   RateLimitRule{Rate: 5, Burst: 20},
+			Auth:    RateLimitRule{Rate: 0.2, Burst: 5},
+		},
+	}
+}
+
 func (c *Config) Address() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
 }
 
+// ConnectionString returns the URL, or else a key=value connection string
+// whose values are quoted, so a password read from a file may contain
+// spaces, quotes and backslashes.
 func (c *DatabaseConfig) ConnectionString() string {
+	if c.URL != "" {
+		return c.URL
+	}
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
+		quoteConnectionValue(c.Host), c.Port, quoteConnectionValue(c.User), quoteConnectionValue(c.Password),
+		quoteConnectionValue(c.Name), quoteConnectionValue(c.SSLMode))
 }
 
+func quoteConnectionValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// Load builds the configuration from the defaults, the YAML file at path,
+// DATABASE_URL and PCRM_* environment variables, in increasing precedence,
+// then resolves secret files and validates the result. An empty path reads
+// DefaultPath when it exists.
 func Load(path string) (*Config, error) {
+	config := Default()
+	if err := loadFile(&config, path); err != nil {
+		return nil, err
+	}
+	if databaseURL, ok := os.LookupEnv("DATABASE_URL"); ok {
+		config.Database.URL = databaseURL
+	}
+	if err := applyEnv(&config, os.LookupEnv); err != nil {
+		return nil, fmt.Errorf("invalid environment override: %w", err)
+	}
+	if err := config.Database.resolve(); err != nil {
+		return nil, err
+	}
+	if err := config.Auth.OIDC.resolve(); err != nil {
+		return nil, err
+	}
+	if err := config.Encryption.resolve(); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &config, nil
+}
+
+func loadFile(config *Config, path string) error {
+	optional := path == ""
+	if optional {
+		path = DefaultPath
+	}
 	data, err := os.ReadFile(path)
+	if optional && errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to unmarshal config %s: %w", path, err)
 	}
+	return nil
+}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+// resolve reads the password file and derives the driver from the URL.
+func (c *DatabaseConfig) resolve() error {
+	if c.PasswordFile != "" {
+		password, err := os.ReadFile(c.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("failed to read database password file: %w", err)
+		}
+		c.Password = strings.TrimRight(string(password), "\r\n")
 	}
+	if c.URL == "" {
+		return nil
+	}
+	return c.resolveURL()
+}
 
-	return &config, nil
+// resolve reads the client secret file.
+func (c *OIDCConfig) resolve() error {
+	if c.ClientSecretFile == "" {
+		return nil
+	}
+	secret, err := os.ReadFile(c.ClientSecretFile)
+	if err != nil {
+		return fmt.Errorf("failed to read oidc client secret file: %w", err)
+	}
+	c.ClientSecret = strings.TrimRight(string(secret), "\r\n")
+	return nil
+}
+
+// resolve reads the key files.
+func (c *EncryptionConfig) resolve() error {
+	for i, key := range c.Keys {
+		if key.KeyFile == "" {
+			continue
+		}
+		data, err := os.ReadFile(key.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read encryption key file for key %q: %w", key.ID, err)
+		}
+		c.Keys[i].Key = strings.TrimSpace(string(data))
+	}
+	return nil
+}
+
+func (c *DatabaseConfig) resolveURL() error {
+	parsed, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid database url: %w", err)
+	}
+	switch parsed.Scheme {
+	case "postgres", "postgresql":
+		c.Driver = DriverPostgres
+		if c.PasswordFile != "" && parsed.User != nil {
+			parsed.User = url.UserPassword(parsed.User.Username(), c.Password)
+			c.URL = parsed.String()
+		}
+	case "sqlite", "file":
+		c.Driver = DriverSQLite
+		c.Path = parsed.Opaque
+		if c.Path == "" {
+			c.Path = parsed.Host + parsed.Path
+		}
+		c.URL = ""
+	default:
+		return fmt.Errorf("unsupported database url scheme %q", parsed.Scheme)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		errs = append(errs, errors.New("server.max_body_bytes must be positive"))
+	}
+	if c.Server.MaxImportBytes <= 0 {
+		errs = append(errs, errors.New("server.max_import_bytes must be positive"))
+	}
+	if c.Server.ShutdownDelay < 0 {
+		errs = append(errs, errors.New("server.shutdown_delay must not be negative"))
+	}
+	switch c.Database.Driver {
+	case DriverPostgres:
+		if c.Database.URL == "" && (c.Database.Host == "" || c.Database.Name == "" || c.Database.User == "") {
+			errs = append(errs, errors.New("database.host, database.name and database.user are required for postgres unless database.url is set"))
+		}
+	case DriverSQLite:
+		if c.Database.Path == "" {
+			errs = append(errs, errors.New("database.path is required for sqlite"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("database.driver must be %s or %s, got %q", DriverPostgres, DriverSQLite, c.Database.Driver))
+	}
+	if c.Database.QueryTimeout < 0 {
+		errs = append(errs, errors.New("database.query_timeout must not be negative"))
+	}
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, c.Logging.Level) {
+		errs = append(errs, fmt.Errorf("logging.level must be debug, info, warn or error, got %q", c.Logging.Level))
+	}
+	if !slices.Contains([]string{"json", "text"}, c.Logging.Format) {
+		errs = append(errs, fmt.Errorf("logging.format must be json or text, got %q", c.Logging.Format))
+	}
+	if !slices.Contains([]string{TracingExporterOTLP, TracingExporterStdout}, c.Tracing.Exporter) {
+		errs = append(errs, fmt.Errorf("tracing.exporter must be %s or %s, got %q", TracingExporterOTLP, TracingExporterStdout, c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio))
+	}
+	if c.Auth.SessionTTL <= 0 {
+		errs = append(errs, errors.New("auth.session_ttl must be positive"))
+	}
+	if !c.Auth.Enabled && c.Auth.LocalUser == "" {
+		errs = append(errs, errors.New("auth.local_user is required when auth is disabled"))
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if strings.Count(origin, "*") > 1 {
+			errs = append(errs, fmt.Errorf("cors.allowed_origins may contain at most one * per origin, got %q", origin))
+		}
+		if origin == "*" && c.CORS.AllowCredentials {
+			errs = append(errs, errors.New("cors.allow_credentials cannot be combined with an allowed origin of *"))
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		errs = append(errs, errors.New("cors.max_age must not be negative"))
+	}
+	for _, proxy := range c.RateLimit.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				errs = append(errs, fmt.Errorf("rate_limit.trusted_proxies must be IP addresses or CIDR ranges, got %q", proxy))
+			}
+		}
+	}
+	rules := []struct {
+		name string
+		rule RateLimitRule
+	}{{"read", c.RateLimit.Read}, {"write", c.RateLimit.Write}, {"auth", c.RateLimit.Auth}}
+	for _, r := range rules {
+		if r.rule.Rate < 0 {
+			errs = append(errs, fmt.Errorf("rate_limit.%s.rate must not be negative", r.name))
+		}
+		if r.rule.Rate > 0 && r.rule.Burst < 1 {
+			errs = append(errs, fmt.Errorf("rate_limit.%s.burst must be at least 1", r.name))
+		}
+	}
+	errs = append(errs, c.Encryption.validate()...)
+	if c.Auth.OIDC.Enabled {
+		if !c.Auth.Enabled {
+			errs = append(errs, errors.New("auth.oidc requires auth.enabled"))
+		}
+		if c.Auth.OIDC.Issuer == "" || c.Auth.OIDC.ClientID == "" || c.Auth.OIDC.RedirectURL == "" {
+			errs = append(errs, errors.New("auth.oidc.issuer, auth.oidc.client_id and auth.oidc.redirect_url are required when oidc is enabled"))
+		}
+		if !slices.Contains(c.Auth.OIDC.Scopes, "openid") {
+			errs = append(errs, errors.New("auth.oidc.scopes must include openid"))
+		}
+		if c.Auth.OIDC.UsernameClaim == "" {
+			errs = append(errs, errors.New("auth.oidc.username_claim is required when oidc is enabled"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *EncryptionConfig) validate() []error {
+	var errs []error
+	ids := make(map[string]bool)
+	for _, key := range c.Keys {
+		switch {
+		case key.ID == "" || len(key.ID) > 64:
+			errs = append(errs, fmt.Errorf("encryption.keys ids must be between 1 and 64 characters, got %q", key.ID))
+		case ids[key.ID]:
+			errs = append(errs, fmt.Errorf("encryption.keys id %q is used more than once", key.ID))
+		}
+		ids[key.ID] = true
+		if decoded, err := base64.StdEncoding.DecodeString(key.Key); err != nil || len(decoded) != 32 {
+			errs = append(errs, fmt.Errorf("encryption key %q must be 32 bytes encoded as base64", key.ID))
+		}
+	}
+	if c.ActiveKey != "" && !ids[c.ActiveKey] {
+		errs = append(errs, fmt.Errorf("encryption.active_key %q is not in encryption.keys", c.ActiveKey))
+	}
+	return errs
 }
\ No newline at end of file
@@ -0,0 +1,247 @@
+package config_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/lincentpega/pcrm/internal/config"
+)
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// load reads a config file with the YAML, without DATABASE_URL or PCRM_*
+// variables of the environment running the tests.
+func load(t *testing.T, yaml string, env map[string]string) (*config.Config, error) {
+	t.Helper()
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		if name == "DATABASE_URL" || strings.HasPrefix(name, "PCRM_") {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+	return config.Load(writeFile(t, t.TempDir(), "config.yml", yaml))
+}
+
+const postgresConfig = `
+database:
+  name: pcrm
+  user: pcrm
+`
+
+func TestLoadEnvironmentOverrides(t *testing.T) {
+	cfg, err := load(t, postgresConfig+`
+server:
+  port: 9000
+logging:
+  level: debug
+`, map[string]string{
+		"PCRM_SERVER_PORT":                   "9100",
+		"PCRM_DATABASE_DRIVER":               "sqlite",
+		"PCRM_DATABASE_PATH":                 "data/pcrm.db",
+		"PCRM_AUTH_SESSION_TTL":              "2h",
+		"PCRM_AUTH_ENABLED":                  "false",
+		"PCRM_CORS_ALLOWED_ORIGINS":          " https://crm.example.com, ,http://localhost:* ",
+		"PCRM_RATE_LIMIT_READ_RATE":          "1.5",
+		"PCRM_RATE_LIMIT_TRUSTED_PROXIES":    "10.0.0.0/8",
+		"PCRM_AUTH_OIDC_POST_LOGIN_REDIRECT": "/people",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 9100 || cfg.Logging.Level != "debug" {
+		t.Errorf("server.port = %d, logging.level = %q, want the override and the file", cfg.Server.Port, cfg.Logging.Level)
+	}
+	if cfg.Database.Driver != config.DriverSQLite || cfg.Database.Path != "data/pcrm.db" {
+		t.Errorf("database = %+v, want sqlite at data/pcrm.db", cfg.Database)
+	}
+	if cfg.Auth.SessionTTL != 2*time.Hour || cfg.Auth.Enabled || cfg.Auth.OIDC.PostLoginRedirect != "/people" {
+		t.Errorf("auth = %+v", cfg.Auth)
+	}
+	if !slices.Equal(cfg.CORS.AllowedOrigins, []string{"https://crm.example.com", "http://localhost:*"}) {
+		t.Errorf("cors.allowed_origins = %q", cfg.CORS.AllowedOrigins)
+	}
+	if cfg.RateLimit.Read.Rate != 1.5 || cfg.RateLimit.Read.Burst != 40 || !slices.Equal(cfg.RateLimit.TrustedProxies, []string{"10.0.0.0/8"}) {
+		t.Errorf("rate_limit = %+v", cfg.RateLimit)
+	}
+}
+
+func TestLoadRejectsMalformedOverrides(t *testing.T) {
+	_, err := load(t, postgresConfig, map[string]string{
+		"PCRM_SERVER_PORT":      "eighty",
+		"PCRM_METRICS_ENABLED":  "maybe",
+		"PCRM_DATABASE_TIMEOUT": "ignored",
+	})
+	if err == nil {
+		t.Fatal("loaded malformed overrides")
+	}
+	for _, want := range []string{"PCRM_SERVER_PORT", "PCRM_METRICS_ENABLED"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to name %s", err, want)
+		}
+	}
+}
+
+func TestLoadDatabaseURLPrecedence(t *testing.T) {
+	file := postgresConfig + "  url: postgres://file@db.example.com/pcrm\n"
+	tests := []struct {
+		name   string
+		env    map[string]string
+		driver string
+		url    string
+		path   string
+	}{
+		{name: "file", driver: config.DriverPostgres, url: "postgres://file@db.example.com/pcrm"},
+		{name: "DATABASE_URL", env: map[string]string{"DATABASE_URL": "postgres://env@db.example.com/pcrm"}, driver: config.DriverPostgres, url: "postgres://env@db.example.com/pcrm"},
+		{
+			name:   "PCRM_DATABASE_URL",
+			env:    map[string]string{"DATABASE_URL": "postgres://env@db.example.com/pcrm", "PCRM_DATABASE_URL": "postgres://pcrm@db.example.com/pcrm"},
+			driver: config.DriverPostgres,
+			url:    "postgres://pcrm@db.example.com/pcrm",
+		},
+		{name: "sqlite URL", env: map[string]string{"DATABASE_URL": "sqlite:data/pcrm.db"}, driver: config.DriverSQLite, path: "data/pcrm.db"},
+		{name: "sqlite URL with slashes", env: map[string]string{"DATABASE_URL": "sqlite:///var/lib/pcrm.db"}, driver: config.DriverSQLite, path: "/var/lib/pcrm.db"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, file, tt.env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Database.Driver != tt.driver || cfg.Database.URL != tt.url || (tt.path != "" && cfg.Database.Path != tt.path) {
+				t.Errorf("database = %+v, want driver %s, url %q and path %q", cfg.Database, tt.driver, tt.url, tt.path)
+			}
+		})
+	}
+	if _, err := load(t, file, map[string]string{"DATABASE_URL": "mysql://db.example.com/pcrm"}); err == nil {
+		t.Error("loaded a mysql database url")
+	}
+}
+
+func TestLoadResolvesSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	password := writeFile(t, dir, "password", "s3cret pass'\\\n")
+	secret := writeFile(t, dir, "client_secret", "oidc-secret\r\n")
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	keyFile := writeFile(t, dir, "key", "\n"+key+"\n")
+	cfg, err := load(t, postgresConfig+`
+  url: postgres://alice@db.example.com/pcrm
+  password_file: `+password+`
+auth:
+  oidc:
+    client_secret_file: `+secret+`
+encryption:
+  active_key: k1
+  keys:
+    - id: k1
+      key_file: `+keyFile+`
+`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Password != "s3cret pass'\\" {
+		t.Errorf("database.password = %q", cfg.Database.Password)
+	}
+	parsed, err := url.Parse(cfg.Database.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := parsed.User.Password(); got != "s3cret pass'\\" || parsed.User.Username() != "alice" {
+		t.Errorf("database.url = %q, want the password of the file", cfg.Database.URL)
+	}
+	if cfg.Auth.OIDC.ClientSecret != "oidc-secret" {
+		t.Errorf("auth.oidc.client_secret = %q", cfg.Auth.OIDC.ClientSecret)
+	}
+	if cfg.Encryption.Keys[0].Key != key {
+		t.Errorf("encryption key = %q, want %q", cfg.Encryption.Keys[0].Key, key)
+	}
+	for _, missing := range []string{
+		postgresConfig + "  password_file: " + filepath.Join(dir, "missing") + "\n",
+		postgresConfig + "auth:\n  oidc:\n    client_secret_file: " + filepath.Join(dir, "missing") + "\n",
+		postgresConfig + "encryption:\n  keys:\n    - id: k1\n      key_file: " + filepath.Join(dir, "missing") + "\n",
+	} {
+		if _, err := load(t, missing, nil); err == nil || !strings.Contains(err.Error(), "file") {
+			t.Errorf("err = %v, want a missing file reported", err)
+		}
+	}
+}
+
+func TestConnectionStringQuotesValues(t *testing.T) {
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{password: "secret", want: `password='secret'`},
+		{password: "two words", want: `password='two words'`},
+		{password: `it's`, want: `password='it\'s'`},
+		{password: `back\slash`, want: `password='back\\slash'`},
+		{password: "x dbname=other", want: `password='x dbname=other'`},
+		{password: "", want: `password=''`},
+	}
+	for _, tt := range tests {
+		database := config.DatabaseConfig{Host: "db.example.com", Port: 5432, Name: "pcrm", User: "pcrm", Password: tt.password, SSLMode: "disable"}
+		dsn := database.ConnectionString()
+		want := "host='db.example.com' port=5432 user='pcrm' " + tt.want + " dbname='pcrm' sslmode='disable'"
+		if dsn != want {
+			t.Errorf("ConnectionString() = %q, want %q", dsn, want)
+		}
+		if _, err := pq.NewConnector(dsn); err != nil {
+			t.Errorf("lib/pq rejected %q: %v", dsn, err)
+		}
+	}
+	database := config.DatabaseConfig{URL: "postgres://pcrm@db.example.com/pcrm", Host: "ignored"}
+	if dsn := database.ConnectionString(); dsn != database.URL {
+		t.Errorf("ConnectionString() = %q, want the URL", dsn)
+	}
+}
+
+func TestValidateCollectsAllErrors(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.Port = 0
+	cfg.Database.Driver = "mysql"
+	cfg.Logging.Level = "verbose"
+	cfg.CORS.AllowedOrigins = []string{"*"}
+	cfg.CORS.AllowCredentials = true
+	cfg.RateLimit.Write = config.RateLimitRule{Rate: 1}
+	cfg.Encryption.ActiveKey = "k1"
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("validated an invalid config")
+	}
+	want := []string{
+		"server.port must be between 1 and 65535, got 0",
+		`database.driver must be postgres or sqlite, got "mysql"`,
+		`logging.level must be debug, info, warn or error, got "verbose"`,
+		"cors.allow_credentials cannot be combined with an allowed origin of *",
+		"rate_limit.write.burst must be at least 1",
+		`encryption.active_key "k1" is not in encryption.keys`,
+	}
+	if got := strings.Split(err.Error(), "\n"); !slices.Equal(got, want) {
+		t.Errorf("errors = %q, want %q", got, want)
+	}
+	valid := config.Default()
+	valid.Database.Driver = config.DriverSQLite
+	if err := valid.Validate(); err != nil {
+		t.Errorf("default sqlite config: %v", err)
+	}
+}
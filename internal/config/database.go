@@ -2,20 +2,56 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
+func init() {
+	sqlx.BindDriver(DriverSQLite, sqlx.QUESTION)
+}
+
 func NewDatabase(cfg DatabaseConfig) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", cfg.ConnectionString())
+	db, err := openDatabase(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	return db, nil
+}
+
+func openDatabase(cfg DatabaseConfig) (*sqlx.DB, error) {
+	switch cfg.Driver {
+	case "", DriverPostgres:
+		return sqlx.Open(DriverPostgres, cfg.ConnectionString())
+	case DriverSQLite:
+		return openSQLite(cfg.Path)
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+}
+
+// openSQLite enables foreign keys on every connection, takes the write lock
+// when a transaction begins and limits the pool to a single connection, since
+// SQLite allows only one writer at a time.
+func openSQLite(path string) (*sqlx.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("database path is required for the %s driver", DriverSQLite)
+	}
+	params := url.Values{
+		"_pragma": {"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"},
+		"_txlock": {"immediate"},
+	}
+	db, err := sqlx.Open(DriverSQLite, "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
 	return db, nil
 }
\ No newline at end of file
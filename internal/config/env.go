@@ -0,0 +1,88 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "PCRM"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overrides every configuration field from a variable named after
+// its YAML path, e.g. PCRM_DATABASE_QUERY_TIMEOUT for database.query_timeout.
+func applyEnv(config *Config, lookup func(key string) (string, bool)) error {
+	return applyEnvToStruct(reflect.ValueOf(config).Elem(), envPrefix, lookup)
+}
+
+func applyEnvToStruct(value reflect.Value, prefix string, lookup func(key string) (string, bool)) error {
+	var errs []error
+	for i := range value.NumField() {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + "_" + strings.ToUpper(name)
+		field := value.Field(i)
+		if field.Kind() == reflect.Struct {
+			errs = append(errs, applyEnvToStruct(field, key, lookup))
+			continue
+		}
+		raw, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		if err := setField(field, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setField(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		field.SetInt(int64(duration))
+		return err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		field.SetBool(parsed)
+		return err
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		field.SetInt(parsed)
+		return err
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		field.SetFloat(parsed)
+		return err
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", field.Type())
+		}
+		field.Set(reflect.ValueOf(splitList(raw)))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// splitList parses comma-separated list values, ignoring surrounding spaces
+// and empty items.
+func splitList(raw string) []string {
+	var items []string
+	for item := range strings.SplitSeq(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,85 @@
+package dto
+
+import "time"
+
+// ArchiveVersion is the version of the archive format written by exports;
+// imports only accept archives of this version.
+const ArchiveVersion = 1
+
+// Archive is the export of every record of a user. Records keep their ids
+// and refer to people and types by them; on import they are stored under
+// new ids.
+type Archive struct {
+	Version           int                       `json:"version"`
+	ExportedAt        time.Time                 `json:"exportedAt"`
+	ContactTypes      []ArchiveType             `json:"contactTypes"`
+	ConversationTypes []ArchiveType             `json:"conversationTypes"`
+	People            []ArchivePerson           `json:"people"`
+	Contacts          []ArchiveContact          `json:"contacts"`
+	ConnectionSources []ArchiveConnectionSource `json:"connectionSources"`
+	BirthDateInfo     []ArchiveBirthDateInfo    `json:"birthDateInfo"`
+	Conversations     []ArchiveConversation     `json:"conversations"`
+}
+
+// ArchiveType is a contact or conversation type. Types are matched by name
+// on import.
+type ArchiveType struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// ArchiveTimestamps restore the creation and update time of a record. When
+// they are omitted the time of the import is used.
+type ArchiveTimestamps struct {
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ArchivePerson struct {
+	ID int64 `json:"id"`
+	PersonUpsertRequest
+	Tags []string `json:"tags,omitempty"`
+	ArchiveTimestamps
+}
+
+type ArchiveContact struct {
+	ID       int64 `json:"id"`
+	PersonID int64 `json:"personId"`
+	ContactRequest
+	ArchiveTimestamps
+}
+
+type ArchiveConnectionSource struct {
+	PersonID int64 `json:"personId"`
+	ConnectionSourceRequest
+	ArchiveTimestamps
+}
+
+type ArchiveBirthDateInfo struct {
+	PersonID int64 `json:"personId"`
+	BirthDateInfoRequest
+	ApproximateAgeUpdatedAt *time.Time `json:"approximateAgeUpdatedAt,omitempty"`
+	ArchiveTimestamps
+}
+
+type ArchiveConversation struct {
+	ID       int64 `json:"id"`
+	PersonID int64 `json:"personId"`
+	ConversationRequest
+	MessageID *string `json:"messageId,omitempty"`
+	ArchiveTimestamps
+}
+
+// ImportResponse reports what an import created, or would create on a dry
+// run, and the id each archived person was stored under.
+type ImportResponse struct {
+	DryRun            bool            `json:"dryRun"`
+	ContactTypes      int             `json:"contactTypes"`
+	ConversationTypes int             `json:"conversationTypes"`
+	People            int             `json:"people"`
+	Contacts          int             `json:"contacts"`
+	ConnectionSources int             `json:"connectionSources"`
+	BirthDateInfo     int             `json:"birthDateInfo"`
+	Conversations     int             `json:"conversations"`
+	PersonIDs         map[int64]int64 `json:"personIds"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type CredentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type UserResponse struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SessionResponse struct {
+	User      UserResponse `json:"user"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// ChatImportResponse reports the conversations, one per day of messages,
+// read from a messenger chat export and, once imported, their ids or that
+// the day was already logged.
+type ChatImportResponse struct {
+	DryRun bool `json:"dryRun"`
+	// PersonName is how the export names the other person.
+	PersonName string                  `json:"personName"`
+	Messages   int                     `json:"messages"`
+	Imported   int                     `json:"imported"`
+	Skipped    int                     `json:"skipped"`
+	Days       []ChatImportDayResponse `json:"days"`
+}
+
+type ChatImportDayResponse struct {
+	// Date is the day of the messages as YYYY-MM-DD.
+	Date           string `json:"date"`
+	ConversationID int64  `json:"conversationId,omitempty"`
+	// Skipped marks a day that already has a conversation of the same type.
+	Skipped        bool      `json:"skipped,omitempty"`
+	Initiator      string    `json:"initiator" enums:"owner,person"`
+	Notes          string    `json:"notes"`
+	OwnerMessages  int       `json:"ownerMessages"`
+	PersonMessages int       `json:"personMessages"`
+	FirstMessageAt time.Time `json:"firstMessageAt"`
+	LastMessageAt  time.Time `json:"lastMessageAt"`
+}
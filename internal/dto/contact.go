@@ -0,0 +1,6 @@
+package dto
+
+type ContactRequest struct {
+	ContactTypeID int64  `json:"contactTypeId"`
+	Content       string `json:"content"`
+}
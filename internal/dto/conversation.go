@@ -13,6 +13,7 @@ type ConversationResponse struct {
     PersonID           int64                    `json:"personId"`
     Initiator          string                   `json:"initiator"`
     Notes              string                   `json:"notes"`
+    MessageID          *string                  `json:"messageId,omitempty"`
     CreatedAt          time.Time                `json:"createdAt"`
     UpdatedAt          time.Time                `json:"updatedAt"`
     ConversationType   ConversationTypeResponse `json:"conversationType"`
@@ -0,0 +1,57 @@
+package dto
+
+// The person fields a CSV column can be mapped to.
+const (
+	CSVFieldFirstName    = "firstName"
+	CSVFieldSecondName   = "secondName"
+	CSVFieldMiddleName   = "middleName"
+	CSVFieldCompany      = "company"
+	CSVFieldJobTitle     = "jobTitle"
+	CSVFieldContact      = "contact"
+	CSVFieldBirthDate    = "birthDate"
+	CSVFieldTag          = "tag"
+	CSVFieldMeetingStory = "meetingStory"
+)
+
+// CSVMapping describes how the columns of a CSV file, named by its header
+// row, become the fields of a person. Columns that are not mapped are
+// ignored.
+type CSVMapping struct {
+	// Delimiter separates the fields of a row; it defaults to a comma.
+	Delimiter string      `json:"delimiter,omitempty"`
+	Columns   []CSVColumn `json:"columns"`
+}
+
+type CSVColumn struct {
+	Column string `json:"column"`
+	Field  string `json:"field" enums:"firstName,secondName,middleName,company,jobTitle,contact,birthDate,tag,meetingStory"`
+	// ContactType names the type of the contacts read from a contact column.
+	ContactType string `json:"contactType,omitempty"`
+}
+
+// ImportRowsResponse reports every row of an import file: the person it was
+// read as, the problems found in it and, once imported, the id of the new
+// person or that the person already existed.
+type ImportRowsResponse struct {
+	DryRun   bool                `json:"dryRun"`
+	Imported int                 `json:"imported"`
+	Skipped  int                 `json:"skipped"`
+	Invalid  int                 `json:"invalid"`
+	Rows     []ImportRowResponse `json:"rows"`
+}
+
+type ImportRowResponse struct {
+	// Line is the line of the file the row starts on.
+	Line     int   `json:"line"`
+	PersonID int64 `json:"personId,omitempty"`
+	// Skipped marks a row whose person already exists.
+	Skipped bool                `json:"skipped,omitempty"`
+	Person  PersonCreateRequest `json:"person"`
+	Errors  []ImportRowError    `json:"errors,omitempty"`
+}
+
+type ImportRowError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
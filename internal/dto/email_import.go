@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// The outcomes of importing an email.
+const (
+	EmailImportImported  = "imported"
+	EmailImportDuplicate = "duplicate"
+	EmailImportInvalid   = "invalid"
+)
+
+// EmailImportResponse counts the emails of a mailbox by what importing them
+// did and lists those that concern someone or could not be read. Emails not
+// sent from or to any person are only counted as unmatched.
+type EmailImportResponse struct {
+	DryRun bool `json:"dryRun"`
+	Emails int  `json:"emails"`
+	// Imported counts the emails logged for at least one person, and
+	// Conversations the conversations logged for them.
+	Imported      int                       `json:"imported"`
+	Conversations int                       `json:"conversations"`
+	Duplicates    int                       `json:"duplicates"`
+	Unmatched     int                       `json:"unmatched"`
+	Invalid       int                       `json:"invalid"`
+	Items         []EmailImportItemResponse `json:"items"`
+}
+
+type EmailImportItemResponse struct {
+	// Source locates the email in the mailbox, e.g. "line 120".
+	Source    string     `json:"source"`
+	Status    string     `json:"status" enums:"imported,duplicate,invalid"`
+	MessageID string     `json:"messageId,omitempty"`
+	Date      *time.Time `json:"date,omitempty"`
+	From      string     `json:"from,omitempty"`
+	Subject   string     `json:"subject,omitempty"`
+	// PersonIDs lists the people the email was logged for, and
+	// DuplicatePersonIDs those who already had it.
+	PersonIDs          []int64          `json:"personIds,omitempty"`
+	ConversationIDs    []int64          `json:"conversationIds,omitempty"`
+	DuplicatePersonIDs []int64          `json:"duplicatePersonIds,omitempty"`
+	Errors             []ImportRowError `json:"errors,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+type ReadinessResponse struct {
+	Status string          `json:"status"`
+	Checks []CheckResponse `json:"checks"`
+}
+
+type CheckResponse struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type VersionResponse struct {
+	Commit        string `json:"commit"`
+	BuildTime     string `json:"buildTime"`
+	GoVersion     string `json:"goVersion"`
+	SchemaVersion int64  `json:"schemaVersion"`
+	SchemaDirty   bool   `json:"schemaDirty"`
+}
@@ -6,6 +6,19 @@ type PersonUpsertRequest struct {
 	FirstName  string  `json:"firstName"`
 	SecondName *string `json:"secondName,omitempty"`
 	MiddleName *string `json:"middleName,omitempty"`
+	Company    *string `json:"company,omitempty"`
+	JobTitle   *string `json:"jobTitle,omitempty"`
+}
+
+// PersonCreateRequest creates a person together with optional sub-resources
+// in a single request.
+type PersonCreateRequest struct {
+	PersonUpsertRequest
+	Contacts         []ContactRequest         `json:"contacts,omitempty"`
+	ConnectionSource *ConnectionSourceRequest `json:"connectionSource,omitempty"`
+	BirthDateInfo    *BirthDateInfoRequest    `json:"birthDateInfo,omitempty"`
+	Conversation     *ConversationRequest     `json:"conversation,omitempty"`
+	Tags             []string                 `json:"tags,omitempty"`
 }
 
 type PersonInfoResponse struct {
@@ -13,6 +26,8 @@ type PersonInfoResponse struct {
 	FirstName  string    `json:"firstName" binding:"required"`
 	SecondName *string   `json:"secondName,omitempty"`
 	MiddleName *string   `json:"middleName,omitempty"`
+	Company    *string   `json:"company,omitempty"`
+	JobTitle   *string   `json:"jobTitle,omitempty"`
 	CreatedAt  time.Time `json:"createdAt" binding:"required"`
 	UpdatedAt  time.Time `json:"updatedAt" binding:"required"`
 }
@@ -22,6 +37,15 @@ type PersonWithContactsResponse struct {
 	Contacts []ContactResponse `json:"contacts"`
 }
 
+type PersonProfileResponse struct {
+	PersonInfoResponse
+	Contacts         []ContactResponse         `json:"contacts"`
+	ConnectionSource *ConnectionSourceResponse `json:"connectionSource"`
+	BirthDateInfo    *BirthDateInfoResponse    `json:"birthDateInfo"`
+	Conversations    []ConversationResponse    `json:"conversations"`
+	Tags             []string                  `json:"tags"`
+}
+
 type ContactResponse struct {
 	ID          int64               `json:"id"`
 	PersonID    int64               `json:"personId"`
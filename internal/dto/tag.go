@@ -0,0 +1,9 @@
+package dto
+
+type TagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type TagsResponse struct {
+	Tags []string `json:"tags"`
+}
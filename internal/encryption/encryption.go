@@ -0,0 +1,157 @@
+// Package encryption protects sensitive fields with envelope encryption.
+// Every record is sealed with its own random AES-256-GCM data key, and the
+// data key is stored next to the record wrapped by a long-lived key
+// encryption key that is named by its key ID, so keys can be rotated by
+// re-encrypting records.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/lincentpega/pcrm/internal/config"
+)
+
+// KeySize is the length in bytes of key encryption keys and data keys.
+const KeySize = 32
+
+// Keyring holds the configured key encryption keys. A nil Keyring, or one
+// without an active key, stores new records in plaintext.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring decodes the keys described by cfg.
+func NewKeyring(cfg config.EncryptionConfig) (*Keyring, error) {
+	keyring := &Keyring{active: cfg.ActiveKey, keys: make(map[string]cipher.AEAD)}
+	for _, key := range cfg.Keys {
+		raw, err := base64.StdEncoding.DecodeString(key.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode encryption key %q: %w", key.ID, err)
+		}
+		if len(raw) != KeySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes, got %d", key.ID, KeySize, len(raw))
+		}
+		aead, err := newAEAD(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load encryption key %q: %w", key.ID, err)
+		}
+		keyring.keys[key.ID] = aead
+	}
+	if keyring.active != "" && keyring.keys[keyring.active] == nil {
+		return nil, fmt.Errorf("active encryption key %q is not configured", keyring.active)
+	}
+	return keyring, nil
+}
+
+// ActiveKeyID returns the ID of the key that seals new records, or "" when
+// they are stored in plaintext.
+func (k *Keyring) ActiveKeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.active
+}
+
+// NewDataKey generates a data key wrapped by the active key. It returns nil
+// when there is no active key.
+func (k *Keyring) NewDataKey() (*DataKey, error) {
+	if k.ActiveKeyID() == "" {
+		return nil, nil
+	}
+	raw := make([]byte, KeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := seal(k.keys[k.active], raw, wrapAD(k.active))
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return &DataKey{KeyID: k.active, WrappedKey: wrapped, aead: aead}, nil
+}
+
+// OpenDataKey unwraps a data key stored with a record.
+func (k *Keyring) OpenDataKey(keyID, wrappedKey string) (*DataKey, error) {
+	var kek cipher.AEAD
+	if k != nil {
+		kek = k.keys[keyID]
+	}
+	if kek == nil {
+		return nil, fmt.Errorf("encryption key %q is not configured", keyID)
+	}
+	raw, err := open(kek, wrappedKey, wrapAD(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with key %q: %w", keyID, err)
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{KeyID: keyID, WrappedKey: wrappedKey, aead: aead}, nil
+}
+
+// DataKey seals the fields of a single record. Each field is bound to its
+// name, so ciphertexts cannot be swapped between fields.
+type DataKey struct {
+	KeyID      string
+	WrappedKey string
+	aead       cipher.AEAD
+}
+
+func (d *DataKey) Encrypt(field, plaintext string) (string, error) {
+	ciphertext, err := seal(d.aead, []byte(plaintext), []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt %s: %w", field, err)
+	}
+	return ciphertext, nil
+}
+
+func (d *DataKey) Decrypt(field, ciphertext string) (string, error) {
+	plaintext, err := open(d.aead, ciphertext, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func wrapAD(keyID string) []byte {
+	return []byte("pcrm data key " + keyID)
+}
+
+// seal encrypts plaintext under a random nonce and returns the nonce and
+// ciphertext encoded as base64.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, additionalData)), nil
+}
+
+func open(aead cipher.AEAD, encoded string, additionalData []byte) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], additionalData)
+}
@@ -0,0 +1,154 @@
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/encryption"
+)
+
+// testKey returns a base64 key filled with b.
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, encryption.KeySize))
+}
+
+func newKeyring(t *testing.T, active string) *encryption.Keyring {
+	t.Helper()
+	keys, err := encryption.NewKeyring(config.EncryptionConfig{
+		ActiveKey: active,
+		Keys:      []config.EncryptionKey{{ID: "k1", Key: testKey(1)}, {ID: "k2", Key: testKey(2)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+// flip changes the last byte of the base64 encoded value.
+func flip(t *testing.T, encoded string) string {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 1
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestRoundTrip(t *testing.T) {
+	keys := newKeyring(t, "k1")
+	dataKey, err := keys.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataKey.KeyID != "k1" || dataKey.WrappedKey == "" {
+		t.Fatalf("data key = %+v, want one wrapped by k1", dataKey)
+	}
+	ciphertext, err := dataKey.Encrypt("contacts.content", "bob@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := dataKey.Encrypt("contacts.content", "bob@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(ciphertext, "bob") || ciphertext == again {
+		t.Errorf("ciphertexts %q and %q, want them opaque and distinct", ciphertext, again)
+	}
+	opened, err := keys.OpenDataKey(dataKey.KeyID, dataKey.WrappedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := opened.Decrypt("contacts.content", ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != "bob@example.com" {
+		t.Errorf("plaintext = %q, want bob@example.com", plaintext)
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	keys := newKeyring(t, "k1")
+	dataKey, err := keys.NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := dataKey.Encrypt("contacts.content", "bob@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		keyID      string
+		wrappedKey string
+		field      string
+		ciphertext string
+	}{
+		{name: "ciphertext", keyID: "k1", wrappedKey: dataKey.WrappedKey, field: "contacts.content", ciphertext: flip(t, ciphertext)},
+		{name: "truncated ciphertext", keyID: "k1", wrappedKey: dataKey.WrappedKey, field: "contacts.content", ciphertext: base64.StdEncoding.EncodeToString([]byte("short"))},
+		{name: "wrapped key", keyID: "k1", wrappedKey: flip(t, dataKey.WrappedKey), field: "contacts.content", ciphertext: ciphertext},
+		{name: "key ID", keyID: "k2", wrappedKey: dataKey.WrappedKey, field: "contacts.content", ciphertext: ciphertext},
+		{name: "field", keyID: "k1", wrappedKey: dataKey.WrappedKey, field: "conversations.notes", ciphertext: ciphertext},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, err := keys.OpenDataKey(tt.keyID, tt.wrappedKey)
+			if err != nil {
+				return
+			}
+			if plaintext, err := opened.Decrypt(tt.field, tt.ciphertext); err == nil {
+				t.Errorf("decrypted %q from a tampered record", plaintext)
+			}
+		})
+	}
+}
+
+func TestOpenDataKeyWithUnknownKey(t *testing.T) {
+	dataKey, err := newKeyring(t, "k1").NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := encryption.NewKeyring(config.EncryptionConfig{Keys: []config.EncryptionKey{{ID: "k2", Key: testKey(2)}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.OpenDataKey(dataKey.KeyID, dataKey.WrappedKey); err == nil || !strings.Contains(err.Error(), `"k1" is not configured`) {
+		t.Errorf("err = %v, want k1 reported as not configured", err)
+	}
+	var plaintext *encryption.Keyring
+	if _, err := plaintext.OpenDataKey(dataKey.KeyID, dataKey.WrappedKey); err == nil {
+		t.Error("a nil keyring opened a data key")
+	}
+}
+
+func TestKeyringWithoutActiveKey(t *testing.T) {
+	for _, keys := range []*encryption.Keyring{nil, newKeyring(t, "")} {
+		dataKey, err := keys.NewDataKey()
+		if err != nil || dataKey != nil || keys.ActiveKeyID() != "" {
+			t.Errorf("NewDataKey = %+v, %v, want no data key", dataKey, err)
+		}
+	}
+}
+
+func TestNewKeyringErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.EncryptionConfig
+		want string
+	}{
+		{name: "malformed key", cfg: config.EncryptionConfig{Keys: []config.EncryptionKey{{ID: "k1", Key: "not base64!"}}}, want: "failed to decode"},
+		{name: "short key", cfg: config.EncryptionConfig{Keys: []config.EncryptionKey{{ID: "k1", Key: base64.StdEncoding.EncodeToString([]byte("short"))}}}, want: "must be 32 bytes"},
+		{name: "missing active key", cfg: config.EncryptionConfig{ActiveKey: "k2", Keys: []config.EncryptionKey{{ID: "k1", Key: testKey(1)}}}, want: `"k2" is not configured`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := encryption.NewKeyring(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to say %q", err, tt.want)
+			}
+		})
+	}
+}
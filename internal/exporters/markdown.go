@@ -0,0 +1,291 @@
+// Package exporters writes people out in formats meant for other tools, such
+// as Markdown notes for Obsidian.
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// noteNameReplacer drops the characters Obsidian does not allow in note
+// names, as they would break links.
+var noteNameReplacer = strings.NewReplacer(`*`, "", `"`, "", `\`, "", `/`, "", `<`, "", `>`, "", `:`, "", `|`, "", `?`, "", `#`, "", `^`, "", `[`, "", `]`, "")
+
+// dossierFrontMatter is the YAML front matter of a dossier, which Obsidian
+// shows as the properties of the note.
+type dossierFrontMatter struct {
+	ID             int64                `yaml:"id"`
+	Name           string               `yaml:"name"`
+	Company        string               `yaml:"company,omitempty"`
+	JobTitle       string               `yaml:"jobTitle,omitempty"`
+	Tags           []string             `yaml:"tags,omitempty"`
+	Birthday       string               `yaml:"birthday,omitempty"`
+	ApproximateAge *int                 `yaml:"approximateAge,omitempty"`
+	Contacts       []frontMatterContact `yaml:"contacts,omitempty"`
+	IntroducerID   *int64               `yaml:"introducerId,omitempty"`
+	Created        time.Time            `yaml:"created"`
+	Updated        time.Time            `yaml:"updated"`
+}
+
+type frontMatterContact struct {
+	ID    int64  `yaml:"id"`
+	Type  string `yaml:"type"`
+	Value string `yaml:"value"`
+}
+
+// DisplayName joins the first, middle and second name of the person.
+func DisplayName(person *models.Person) string {
+	parts := []string{person.FirstName}
+	for _, part := range []*string{person.MiddleName, person.SecondName} {
+		if part != nil && strings.TrimSpace(*part) != "" {
+			parts = append(parts, strings.TrimSpace(*part))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// NoteNames names the note of every person after their display name, which
+// is what other dossiers link to. People whose names would clash, ignoring
+// case, get their id appended, as in "Bob Smith (12)". A name made up this
+// way never takes one a person already has, so the note of someone literally
+// called "Bob Smith (12)" keeps its name and the made-up one is extended, as
+// in "Bob Smith (12-2)".
+func NoteNames(people []models.Person) map[int64]string {
+	bases := make(map[int64]string, len(people))
+	counts := make(map[string]int)
+	for i := range people {
+		base := strings.TrimLeft(strings.TrimSpace(noteNameReplacer.Replace(DisplayName(&people[i]))), ".")
+		if base == "" {
+			base = fmt.Sprintf("Person %d", people[i].ID)
+		}
+		bases[people[i].ID] = base
+		counts[strings.ToLower(base)]++
+	}
+	names := make(map[int64]string, len(people))
+	used := make(map[string]bool, len(people))
+	for id, base := range bases {
+		if counts[strings.ToLower(base)] == 1 {
+			names[id] = base
+			used[strings.ToLower(base)] = true
+		}
+	}
+	ids := slices.Sorted(maps.Keys(bases))
+	for _, id := range ids {
+		if _, ok := names[id]; ok {
+			continue
+		}
+		name := fmt.Sprintf("%s (%d)", bases[id], id)
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s (%d-%d)", bases[id], id, n)
+		}
+		names[id] = name
+		used[strings.ToLower(name)] = true
+	}
+	return names
+}
+
+// WriteDossier writes the dossier as a Markdown note: YAML front matter with
+// the ids, tags, birthday and contacts, followed by how the user met the
+// person, the open follow-ups, the related people and the conversations,
+// most recent first. People are linked with wiki-links to the notes names
+// gives them.
+func WriteDossier(w io.Writer, dossier *models.Dossier, names map[int64]string) error {
+	var note bytes.Buffer
+	profile := &dossier.Profile
+	person := &profile.Person
+	frontMatter, err := yaml.Marshal(dossierFrontMatterOf(dossier))
+	if err != nil {
+		return fmt.Errorf("failed to write front matter: %w", err)
+	}
+	note.WriteString("---\n")
+	note.Write(frontMatter)
+	note.WriteString("---\n\n")
+	fmt.Fprintf(&note, "# %s\n", DisplayName(person))
+	if role := roleOf(person); role != "" {
+		fmt.Fprintf(&note, "\n%s\n", role)
+	}
+	if howWeMet := howWeMetOf(profile.ConnectionSource, dossier.Introducer, names); howWeMet != "" {
+		fmt.Fprintf(&note, "\n## How we met\n\n%s\n", howWeMet)
+	}
+	note.WriteString("\n## Open follow-ups\n\n")
+	if len(dossier.FollowUps) == 0 {
+		note.WriteString("Nothing to follow up on.\n")
+	}
+	for _, conversation := range dossier.FollowUps {
+		fmt.Fprintf(&note, "- [ ] **%s** %s: %s\n", conversation.CreatedAt.UTC().Format(time.DateOnly), conversation.ConversationType.Name, indentContinuation(conversation.Notes))
+	}
+	if related := relatedPeopleOf(dossier, names); len(related) > 0 {
+		note.WriteString("\n## Related people\n\n")
+		for _, line := range related {
+			fmt.Fprintf(&note, "- %s\n", line)
+		}
+	}
+	note.WriteString("\n## Conversations\n\n")
+	if len(profile.Conversations) == 0 {
+		note.WriteString("No conversations yet.\n")
+	}
+	conversations := slices.Clone(profile.Conversations)
+	slices.SortStableFunc(conversations, func(a, b models.Conversation) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	for _, conversation := range conversations {
+		startedBy := "they reached out"
+		if conversation.Initiator == "owner" {
+			startedBy = "I reached out"
+		}
+		fmt.Fprintf(&note, "- **%s** %s, %s: %s\n", conversation.CreatedAt.UTC().Format(time.DateOnly), conversation.ConversationType.Name, startedBy, indentContinuation(conversation.Notes))
+	}
+	_, err = w.Write(note.Bytes())
+	return err
+}
+
+// WriteDossierZip writes a zip archive with the note of every dossier,
+// named as names says.
+func WriteDossierZip(w io.Writer, dossiers []models.Dossier, names map[int64]string) error {
+	archive := zip.NewWriter(w)
+	for i := range dossiers {
+		person := &dossiers[i].Profile.Person
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     names[person.ID] + ".md",
+			Method:   zip.Deflate,
+			Modified: person.UpdatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add note of person %d: %w", person.ID, err)
+		}
+		if err := WriteDossier(file, &dossiers[i], names); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func dossierFrontMatterOf(dossier *models.Dossier) dossierFrontMatter {
+	profile := &dossier.Profile
+	person := &profile.Person
+	frontMatter := dossierFrontMatter{
+		ID:       person.ID,
+		Name:     DisplayName(person),
+		Company:  valueOf(person.Company),
+		JobTitle: valueOf(person.JobTitle),
+		Created:  person.CreatedAt.UTC(),
+		Updated:  person.UpdatedAt.UTC(),
+	}
+	for _, tag := range profile.Tags {
+		frontMatter.Tags = append(frontMatter.Tags, strings.Join(strings.Fields(tag), "-"))
+	}
+	if info := profile.BirthDateInfo; info != nil {
+		frontMatter.Birthday = birthdayOf(info)
+		frontMatter.ApproximateAge = info.ApproximateAge
+	}
+	for _, contact := range profile.Contacts {
+		frontMatter.Contacts = append(frontMatter.Contacts, frontMatterContact{ID: contact.ID, Type: contact.ContactType.Name, Value: contact.Content})
+	}
+	if dossier.Introducer != nil {
+		frontMatter.IntroducerID = &dossier.Introducer.ID
+	}
+	return frontMatter
+}
+
+// birthdayOf writes a full birth date as YYYY-MM-DD and one without a year
+// as --MM-DD, the form used by vCard.
+func birthdayOf(info *models.BirthDateInfo) string {
+	switch {
+	case info.BirthMonth == nil || info.BirthDay == nil:
+		return ""
+	case info.BirthYear == nil:
+		return fmt.Sprintf("--%02d-%02d", *info.BirthMonth, *info.BirthDay)
+	default:
+		return fmt.Sprintf("%04d-%02d-%02d", *info.BirthYear, *info.BirthMonth, *info.BirthDay)
+	}
+}
+
+func roleOf(person *models.Person) string {
+	jobTitle, company := valueOf(person.JobTitle), valueOf(person.Company)
+	switch {
+	case jobTitle != "" && company != "":
+		return jobTitle + " at " + company
+	case jobTitle != "":
+		return jobTitle
+	default:
+		return company
+	}
+}
+
+// howWeMetOf tells when and through whom the user met the person, followed
+// by the meeting story, or nothing when the connection source is empty.
+func howWeMetOf(source *models.ConnectionSource, introducer *models.Person, names map[int64]string) string {
+	if source == nil {
+		return ""
+	}
+	var facts []string
+	if source.MeetingTimestamp != nil {
+		facts = append(facts, fmt.Sprintf("Met on %s.", source.MeetingTimestamp.UTC().Format(time.DateOnly)))
+	}
+	switch {
+	case introducer != nil:
+		facts = append(facts, fmt.Sprintf("Introduced by %s.", wikiLink(introducer, names)))
+	case source.IntroducerName != nil:
+		facts = append(facts, fmt.Sprintf("Introduced by %s.", *source.IntroducerName))
+	case source.WasIntroduced != nil && *source.WasIntroduced:
+		facts = append(facts, "Introduced by someone.")
+	}
+	paragraphs := []string{strings.Join(facts, "\n")}
+	if story := valueOf(source.MeetingStory); story != "" {
+		paragraphs = append(paragraphs, story)
+	}
+	return strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+}
+
+// relatedPeopleOf lists the people linked to the person, each with how they
+// are related: the introducer, the people the person introduced, the others
+// the introducer introduced and the colleagues at the same company.
+func relatedPeopleOf(dossier *models.Dossier, names map[int64]string) []string {
+	var related []string
+	if dossier.Introducer != nil {
+		related = append(related, wikiLink(dossier.Introducer, names)+", who introduced us")
+	}
+	for i := range dossier.Introduced {
+		related = append(related, wikiLink(&dossier.Introduced[i], names)+", whom they introduced")
+	}
+	for i := range dossier.IntroducedAlongside {
+		related = append(related, fmt.Sprintf("%s, also introduced by %s", wikiLink(&dossier.IntroducedAlongside[i], names), wikiLink(dossier.Introducer, names)))
+	}
+	for i := range dossier.Colleagues {
+		related = append(related, fmt.Sprintf("%s, also at %s", wikiLink(&dossier.Colleagues[i], names), valueOf(dossier.Profile.Person.Company)))
+	}
+	return related
+}
+
+// wikiLink links to the note of the person, showing their display name when
+// the note name differs from it.
+func wikiLink(person *models.Person, names map[int64]string) string {
+	name := names[person.ID]
+	if display := DisplayName(person); display != name {
+		return fmt.Sprintf("[[%s|%s]]", name, noteNameReplacer.Replace(display))
+	}
+	return fmt.Sprintf("[[%s]]", name)
+}
+
+// indentContinuation indents every line after the first, so multi-line
+// notes stay inside their list item.
+func indentContinuation(text string) string {
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n  ")
+}
+
+func valueOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
@@ -0,0 +1,46 @@
+package exporters_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/exporters"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+func TestNoteNames(t *testing.T) {
+	smith := "Smith"
+	tests := []struct {
+		name   string
+		people []models.Person
+		want   map[int64]string
+	}{
+		{
+			name:   "unique names",
+			people: []models.Person{{ID: 1, FirstName: "Ann"}, {ID: 2, FirstName: "Bob", SecondName: &smith}},
+			want:   map[int64]string{1: "Ann", 2: "Bob Smith"},
+		},
+		{
+			name:   "clash ignoring case",
+			people: []models.Person{{ID: 1, FirstName: "Ann"}, {ID: 2, FirstName: "ann"}},
+			want:   map[int64]string{1: "Ann (1)", 2: "ann (2)"},
+		},
+		{
+			name:   "literal name of a disambiguated one",
+			people: []models.Person{{ID: 12, FirstName: "Ann"}, {ID: 13, FirstName: "Ann"}, {ID: 14, FirstName: "ann (12)"}},
+			want:   map[int64]string{12: "Ann (12-2)", 13: "Ann (13)", 14: "ann (12)"},
+		},
+		{
+			name:   "characters links cannot hold",
+			people: []models.Person{{ID: 1, FirstName: "A/B: #1"}, {ID: 2, FirstName: "..."}, {ID: 3, FirstName: "Person 2"}},
+			want:   map[int64]string{1: "AB 1", 2: "Person 2 (2)", 3: "Person 2 (3)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exporters.NoteNames(tt.people); !maps.Equal(got, tt.want) {
+				t.Errorf("NoteNames = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
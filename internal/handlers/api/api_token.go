@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/mappers"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+type APITokenAPI struct {
+	service *services.TokenService
+}
+
+func NewAPITokenAPI(service *services.TokenService) *APITokenAPI {
+	return &APITokenAPI{service: service}
+}
+
+// ListTokens godoc
+// @Summary List API tokens
+// @Description Get every personal access token without its secret
+// @Tags tokens
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.APITokenResponse
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Router /api/tokens [get]
+func (api *APITokenAPI) ListTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := api.service.ListTokens(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	response := make([]dto.APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		response[i] = mappers.APITokenDomainToResponse(&token)
+	}
+	WriteSuccess(w, response)
+}
+
+// CreateToken godoc
+// @Summary Create an API token
+// @Description Create a personal access token with a read or write scope and an optional expiry. The secret is only returned in this response.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token body dto.APITokenRequest true "Token data"
+// @Success 201 {object} dto.CreatedAPITokenResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Router /api/tokens [post]
+func (api *APITokenAPI) CreateToken(w http.ResponseWriter, r *http.Request) {
+	var req dto.APITokenRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	if err := validators.ValidateAPITokenRequest(&req, time.Now()); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	token := mappers.APITokenRequestToDomain(&req)
+	secret, err := api.service.CreateToken(r.Context(), token)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	WriteCreated(w, dto.CreatedAPITokenResponse{APITokenResponse: mappers.APITokenDomainToResponse(token), Token: secret})
+}
+
+// DeleteToken godoc
+// @Summary Revoke an API token
+// @Description Delete a personal access token so it can no longer be used
+// @Tags tokens
+// @Security BearerAuth
+// @Param id path int true "Token ID"
+// @Success 204
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Router /api/tokens/{id} [delete]
+func (api *APITokenAPI) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	id, err := PathID(r, "id")
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	if err := api.service.RevokeToken(r.Context(), id); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
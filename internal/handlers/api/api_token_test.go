@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// createToken creates a token with the scope and returns it.
+func (c *client) createToken(name, scope string) dto.CreatedAPITokenResponse {
+	c.t.Helper()
+	var token dto.CreatedAPITokenResponse
+	c.mustDo(http.MethodPost, "/api/tokens", dto.APITokenRequest{Name: name, Scope: scope}, http.StatusCreated, &token)
+	return token
+}
+
+// withToken returns a client that authenticates with the bearer token only.
+func (s *testServer) withToken(t *testing.T, secret string) *client {
+	t.Helper()
+	bearer := s.anonymous(t)
+	bearer.bearer = secret
+	return bearer
+}
+
+func TestTokenLifecycle(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	token := alice.createToken("laptop", models.TokenScopeWrite)
+	if token.Token == "" || token.Scope != models.TokenScopeWrite {
+		t.Fatalf("created token = %+v", token)
+	}
+	bearer := server.withToken(t, token.Token)
+	bearer.createPerson("Bob", "")
+	var tokens []dto.APITokenResponse
+	alice.mustDo(http.MethodGet, "/api/tokens", nil, http.StatusOK, &tokens)
+	if len(tokens) != 1 || tokens[0].ID != token.ID || tokens[0].LastUsedAt == nil {
+		t.Errorf("tokens = %+v, want the used token", tokens)
+	}
+	alice.mustDo(http.MethodDelete, fmt.Sprintf("/api/tokens/%d", token.ID), nil, http.StatusNoContent, nil)
+	bearer.do(http.MethodGet, "/api/people", nil).problem(t, http.StatusUnauthorized)
+}
+
+func TestReadTokenCannotWrite(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	bearer := server.withToken(t, alice.createToken("dashboard", models.TokenScopeRead).Token)
+	bearer.mustDo(http.MethodGet, "/api/people", nil, http.StatusOK, nil)
+	bearer.do(http.MethodPost, "/api/people", dto.PersonCreateRequest{PersonUpsertRequest: dto.PersonUpsertRequest{FirstName: "Bob"}}).problem(t, http.StatusForbidden)
+	bearer.do(http.MethodGet, "/api/tokens", nil).problem(t, http.StatusForbidden)
+}
+
+func TestTokenErrors(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	problem := alice.do(http.MethodPost, "/api/tokens", dto.APITokenRequest{Scope: "admin"}).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "name", "required") || !hasFieldProblem(problem, "scope", "invalid") {
+		t.Errorf("errors = %+v, want name required and scope invalid", problem.Errors)
+	}
+	token := alice.createToken("laptop", models.TokenScopeWrite)
+	server.signUp(t, "mallory").do(http.MethodDelete, fmt.Sprintf("/api/tokens/%d", token.ID), nil).problem(t, http.StatusNotFound)
+	server.withToken(t, "pcrm_not-a-token").do(http.MethodGet, "/api/people", nil).problem(t, http.StatusUnauthorized)
+}
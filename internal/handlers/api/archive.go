@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/mappers"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+type ArchiveAPI struct {
+	service *services.ArchiveService
+}
+
+func NewArchiveAPI(service *services.ArchiveService) *ArchiveAPI {
+	return &ArchiveAPI{service: service}
+}
+
+// Export godoc
+// @Summary Export all records
+// @Description Download every person, contact, connection source, birth date info and conversation together with
+// @Description the contact and conversation types as a versioned JSON archive. Records keep their ids and refer to
+// @Description each other by them.
+// @Tags archive
+// @Produce json
+// @Success 200 {object} dto.Archive
+// @Failure 401 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/export [get]
+func (api *ArchiveAPI) Export(w http.ResponseWriter, r *http.Request) {
+	archive, err := api.service.Export(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := mappers.ArchiveDomainToResponse(archive)
+	response.ExportedAt = time.Now().UTC()
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="pcrm-%s.json"`, response.ExportedAt.Format("20060102-150405")))
+	WriteSuccess(w, response)
+}
+
+// Import godoc
+// @Summary Import an archive
+// @Description Restore an archive produced by the export next to the existing records, in a single transaction.
+// @Description Every record gets a new id and references between records are remapped; contact and conversation
+// @Description types are matched by name and created when missing. With dryRun the import is checked and rolled back.
+// @Tags archive
+// @Accept json
+// @Produce json
+// @Param dryRun query bool false "Check the archive without storing it"
+// @Param archive body dto.Archive true "Archive produced by GET /api/export"
+// @Success 200 {object} dto.ImportResponse "Dry run result"
+// @Success 201 {object} dto.ImportResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 413 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/import [post]
+func (api *ArchiveAPI) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := validators.ParseBoolParam("dryRun", r.URL.Query().Get("dryRun"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	var req dto.Archive
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	if err := validators.ValidateArchive(&req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	result, err := api.service.Import(r.Context(), mappers.ArchiveRequestToDomain(&req), dryRun)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := mappers.ImportResultDomainToResponse(result)
+	if dryRun {
+		WriteSuccess(w, response)
+		return
+	}
+	WriteCreated(w, response)
+}
@@ -0,0 +1,90 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/api"
+)
+
+func TestExportAndImportArchive(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	introducerID := alice.createPerson("Alice", "Smith")
+	bobID := alice.createPerson("Bob", "Lee")
+	emailType := alice.typeID("/api/contact-types", "Email")
+	callType := alice.typeID("/api/conversation-types", "Phone Call")
+	alice.mustDo(http.MethodPost, fmt.Sprintf("/api/people/%d/contacts", bobID), dto.ContactRequest{ContactTypeID: emailType, Content: "bob@example.com"}, http.StatusCreated, nil)
+	alice.mustDo(http.MethodPost, fmt.Sprintf("/api/people/%d/conversations", bobID), dto.ConversationRequest{ConversationTypeID: callType, Initiator: "owner", Notes: "Catch-up"}, http.StatusCreated, nil)
+	alice.mustDo(http.MethodPut, fmt.Sprintf("/api/people/%d/connection-source", bobID), dto.ConnectionSourceRequest{IntroducerPersonID: &introducerID}, http.StatusCreated, nil)
+	alice.mustDo(http.MethodPut, fmt.Sprintf("/api/people/%d/tags", bobID), dto.TagsRequest{Tags: []string{"friend"}}, http.StatusOK, nil)
+	var archive dto.Archive
+	alice.mustDo(http.MethodGet, "/api/export", nil, http.StatusOK, &archive)
+	if archive.Version != 1 || len(archive.People) != 2 || len(archive.Contacts) != 1 || len(archive.Conversations) != 1 || len(archive.ConnectionSources) != 1 {
+		t.Fatalf("archive = %+v", archive)
+	}
+	mallory := server.signUp(t, "mallory")
+	var dryRun dto.ImportResponse
+	mallory.mustDo(http.MethodPost, "/api/import?dryRun=true", archive, http.StatusOK, &dryRun)
+	if !dryRun.DryRun || dryRun.People != 2 || dryRun.Contacts != 1 {
+		t.Errorf("dry run = %+v", dryRun)
+	}
+	var page api.PaginatedResponse[dto.PersonInfoResponse]
+	mallory.mustDo(http.MethodGet, "/api/people", nil, http.StatusOK, &page)
+	if page.TotalCount != 0 {
+		t.Fatalf("dry run stored %d people", page.TotalCount)
+	}
+	var imported dto.ImportResponse
+	mallory.mustDo(http.MethodPost, "/api/import", archive, http.StatusCreated, &imported)
+	newBobID, ok := imported.PersonIDs[bobID]
+	if !ok || imported.People != 2 || imported.Conversations != 1 {
+		t.Fatalf("import = %+v", imported)
+	}
+	var source dto.ConnectionSourceResponse
+	mallory.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d/connection-source", newBobID), nil, http.StatusOK, &source)
+	if source.IntroducerPersonID == nil || *source.IntroducerPersonID != imported.PersonIDs[introducerID] {
+		t.Errorf("imported introducer = %v, want %d", source.IntroducerPersonID, imported.PersonIDs[introducerID])
+	}
+	var tags dto.TagsResponse
+	mallory.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d/tags", newBobID), nil, http.StatusOK, &tags)
+	if len(tags.Tags) != 1 || tags.Tags[0] != "friend" {
+		t.Errorf("imported tags = %v", tags.Tags)
+	}
+}
+
+func TestImportArchiveRejectsDanglingReferences(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	archive := dto.Archive{
+		Version: 1,
+		Contacts: []dto.ArchiveContact{{
+			ID:             1,
+			PersonID:       42,
+			ContactRequest: dto.ContactRequest{ContactTypeID: 1, Content: "bob@example.com"},
+		}},
+	}
+	problem := alice.do(http.MethodPost, "/api/import", archive).problem(t, http.StatusBadRequest)
+	if len(problem.Errors) == 0 {
+		t.Errorf("problem = %+v, want field errors", problem)
+	}
+}
+
+func TestImportArchiveTypeNamesArePerUser(t *testing.T) {
+	server := newTestServer(t)
+	archive := dto.Archive{
+		Version:           1,
+		ContactTypes:      []dto.ArchiveType{{ID: 1, Name: "Signal"}},
+		ConversationTypes: []dto.ArchiveType{{ID: 1, Name: "Hike"}},
+	}
+	for _, username := range []string{"alice", "mallory"} {
+		user := server.signUp(t, username)
+		var imported dto.ImportResponse
+		user.mustDo(http.MethodPost, "/api/import", archive, http.StatusCreated, &imported)
+		if imported.ContactTypes != 1 || imported.ConversationTypes != 1 {
+			t.Errorf("%s import = %+v, want both types created", username, imported)
+		}
+		user.typeID("/api/contact-types", "Signal")
+		user.typeID("/api/conversation-types", "Hike")
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/mappers"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+type AuthAPI struct {
+	service           *services.UserService
+	allowRegistration bool
+	secureCookies     bool
+}
+
+func NewAuthAPI(service *services.UserService, allowRegistration, secureCookies bool) *AuthAPI {
+	return &AuthAPI{service: service, allowRegistration: allowRegistration, secureCookies: secureCookies}
+}
+
+// Register godoc
+// @Summary Register an account
+// @Description Create an account with its own, empty address book. Only available when registration is enabled.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body dto.CredentialsRequest true "Username and password"
+// @Success 201 {object} dto.UserResponse
+// @Failure 400 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Router /api/auth/register [post]
+func (api *AuthAPI) Register(w http.ResponseWriter, r *http.Request) {
+	if !api.allowRegistration {
+		problem.WriteError(w, r, &services.PermissionError{Message: "registration is disabled"})
+		return
+	}
+	var req dto.CredentialsRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	if err := validators.ValidateCredentialsRequest(&req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	user, err := api.service.Register(r.Context(), req.Username, req.Password)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	WriteCreated(w, mappers.UserDomainToResponse(user))
+}
+
+// Login godoc
+// @Summary Log in
+// @Description Check the credentials and start a session, set as an HttpOnly cookie
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body dto.CredentialsRequest true "Username and password"
+// @Success 200 {object} dto.SessionResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Router /api/auth/login [post]
+func (api *AuthAPI) Login(w http.ResponseWriter, r *http.Request) {
+	var req dto.CredentialsRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	if err := validators.ValidateLoginRequest(&req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	session, secret, err := api.service.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	user, err := api.service.GetUser(r.Context(), session.UserID)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	http.SetCookie(w, sessionCookie(secret, session.ExpiresAt, api.secureCookies))
+	WriteSuccess(w, mappers.SessionDomainToResponse(user, session))
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description End the session in the cookie, if there is one, and clear the cookie. No authentication is needed,
+// @Description so a browser holding an expired session can still clear it.
+// @Tags auth
+// @Success 204
+// @Failure 500 {object} problem.Details
+// @Router /api/auth/logout [post]
+func (api *AuthAPI) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(auth.SessionCookieName); err == nil {
+		if err := api.service.Logout(r.Context(), cookie.Value); err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+	}
+	cookie := sessionCookie("", time.Unix(0, 0), api.secureCookies)
+	cookie.MaxAge = -1
+	http.SetCookie(w, cookie)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me godoc
+// @Summary Get the current user
+// @Description Get the account the request is authenticated as
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.UserResponse
+// @Failure 401 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Router /api/auth/me [get]
+func (api *AuthAPI) Me(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserID(r.Context())
+	if !ok {
+		problem.WriteError(w, r, &services.AuthenticationError{Message: "not logged in"})
+		return
+	}
+	user, err := api.service.GetUser(r.Context(), userID)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	WriteSuccess(w, mappers.UserDomainToResponse(user))
+}
+
+func sessionCookie(value string, expires time.Time, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     auth.SessionCookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
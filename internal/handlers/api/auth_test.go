@@ -0,0 +1,97 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/api"
+	"github.com/lincentpega/pcrm/internal/repository/memory"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+const testPassword = "correct horse battery"
+
+func TestRegisterLoginAndLogout(t *testing.T) {
+	server := newTestServer(t)
+	c := server.anonymous(t)
+	credentials := dto.CredentialsRequest{Username: "alice", Password: testPassword}
+	var user dto.UserResponse
+	c.mustDo(http.MethodPost, "/api/auth/register", credentials, http.StatusCreated, &user)
+	if user.ID == 0 || user.Username != "alice" {
+		t.Fatalf("registered user = %+v", user)
+	}
+	c.do(http.MethodPost, "/api/auth/register", credentials).problem(t, http.StatusConflict)
+	c.do(http.MethodGet, "/api/auth/me", nil).problem(t, http.StatusUnauthorized)
+	c.do(http.MethodPost, "/api/auth/login", dto.CredentialsRequest{Username: "alice", Password: "wrong password"}).problem(t, http.StatusUnauthorized)
+	var session dto.SessionResponse
+	res := c.mustDo(http.MethodPost, "/api/auth/login", credentials, http.StatusOK, &session)
+	if session.User.ID != user.ID || !session.ExpiresAt.After(time.Now()) {
+		t.Errorf("session = %+v", session)
+	}
+	if cookie := res.header.Get("Set-Cookie"); !strings.Contains(cookie, auth.SessionCookieName+"=") || !strings.Contains(cookie, "HttpOnly") {
+		t.Errorf("Set-Cookie = %q, want an HttpOnly session cookie", cookie)
+	}
+	var me dto.UserResponse
+	c.mustDo(http.MethodGet, "/api/auth/me", nil, http.StatusOK, &me)
+	if me.ID != user.ID {
+		t.Errorf("me = %+v, want user %d", me, user.ID)
+	}
+	c.mustDo(http.MethodPost, "/api/auth/logout", nil, http.StatusNoContent, nil)
+	c.do(http.MethodGet, "/api/auth/me", nil).problem(t, http.StatusUnauthorized)
+}
+
+func TestLogoutWithoutValidSession(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	user, err := server.users.EnsureUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, secret, err := server.users.StartSession(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{secret, secret, "pcrm_s_unknown", ""} {
+		c := server.anonymous(t)
+		if value != "" {
+			c.http.Jar.SetCookies(serverURL, []*http.Cookie{{Name: auth.SessionCookieName, Value: value}})
+		}
+		res := c.mustDo(http.MethodPost, "/api/auth/logout", nil, http.StatusNoContent, nil)
+		if cookie := res.header.Get("Set-Cookie"); !strings.HasPrefix(cookie, auth.SessionCookieName+"=;") || !strings.Contains(cookie, "Max-Age=0") {
+			t.Errorf("Set-Cookie = %q, want the session cookie cleared", cookie)
+		}
+	}
+	if _, err := server.users.AuthenticateSession(ctx, secret); err == nil {
+		t.Error("the session outlived logging out")
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	c := newTestServer(t).anonymous(t)
+	problem := c.do(http.MethodPost, "/api/auth/register", dto.CredentialsRequest{Username: "alice", Password: "short"}).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "password", "invalid") {
+		t.Errorf("errors = %+v, want password invalid", problem.Errors)
+	}
+}
+
+func TestRegisterDisabled(t *testing.T) {
+	store := memory.NewStore()
+	mux := http.NewServeMux()
+	api.RegisterAuthRoutes(mux, services.NewUserService(store, time.Hour), false, false)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(`{"username":"alice","password":"`+testPassword+`"}`)))
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", recorder.Code)
+	}
+}
@@ -1,24 +1,22 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
 	"github.com/lincentpega/pcrm/internal/mappers"
-	"github.com/lincentpega/pcrm/internal/repository"
+	"github.com/lincentpega/pcrm/internal/services"
 	"github.com/lincentpega/pcrm/internal/validators"
 )
 
 type BirthDateInfoAPI struct {
-	repo       *repository.BirthDateInfoRepository
-	personRepo *repository.PersonRepository
+	service *services.BirthDateInfoService
 }
 
-func NewBirthDateInfoAPI(repo *repository.BirthDateInfoRepository, personRepo *repository.PersonRepository) *BirthDateInfoAPI {
+func NewBirthDateInfoAPI(service *services.BirthDateInfoService) *BirthDateInfoAPI {
 	return &BirthDateInfoAPI{
-		repo:       repo,
-		personRepo: personRepo,
+		service: service,
 	}
 }
 
@@ -36,30 +34,22 @@ func NewBirthDateInfoAPI(repo *repository.BirthDateInfoRepository, personRepo *r
 // @Param personId path int true "Person ID"
 // @Success 200 {object} dto.BirthDateInfoResponse "Person exists and has birth date info"
 // @Success 200 {object} nil "Person exists but no birth date info"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse "Person not found"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details "Person not found"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/birth-date-info [get]
 func (api *BirthDateInfoAPI) GetBirthDateInfo(w http.ResponseWriter, r *http.Request) {
-	personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	// Check if person exists
-	person, err := api.personRepo.GetByID(personID)
+	birthDateInfo, err := api.service.GetBirthDateInfo(r.Context(), personID)
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch person")
-		return
-	}
-	if person == nil {
-		WriteNotFound(w, "Person not found")
-		return
-	}
-
-	birthDateInfo, err := api.repo.GetByPersonID(personID)
-	if err != nil {
-		WriteInternalError(w, "Failed to fetch birth date info")
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -82,56 +72,45 @@ func (api *BirthDateInfoAPI) GetBirthDateInfo(w http.ResponseWriter, r *http.Req
 // @Param birthDateInfo body dto.BirthDateInfoRequest true "Birth date info data"
 // @Success 200 {object} dto.BirthDateInfoResponse "Updated"
 // @Success 201 {object} dto.BirthDateInfoResponse "Created"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/birth-date-info [put]
 func (api *BirthDateInfoAPI) UpsertBirthDateInfo(w http.ResponseWriter, r *http.Request) {
-	personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
-		return
-	}
-
-	// Check if person exists
-	person, err := api.personRepo.GetByID(personID)
-	if err != nil {
-		WriteInternalError(w, "Failed to fetch person")
-		return
-	}
-	if person == nil {
-		WriteNotFound(w, "Person not found")
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	var req dto.BirthDateInfoRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteBadRequest(w, "Invalid JSON format")
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	if err := validators.ValidateBirthDateInfoRequest(&req); err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	birthDateInfo := mappers.BirthDateInfoRequestToDomain(personID, &req)
 
-	// Check if birth date info already exists to determine status code
-	existingBirthDateInfo, err := api.repo.GetByPersonID(personID)
-	isUpdate := err == nil && existingBirthDateInfo != nil
-
-	if err := api.repo.Upsert(birthDateInfo); err != nil {
-		WriteInternalError(w, "Failed to save birth date info")
+	created, err := api.service.UpsertBirthDateInfo(r.Context(), birthDateInfo)
+	if err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	response := mappers.BirthDateInfoDomainToResponse(birthDateInfo)
 
-	if isUpdate {
-		WriteSuccess(w, response)
-	} else {
+	if created {
 		WriteCreated(w, response)
+	} else {
+		WriteSuccess(w, response)
 	}
 }
 
@@ -143,30 +122,22 @@ func (api *BirthDateInfoAPI) UpsertBirthDateInfo(w http.ResponseWriter, r *http.
 // @Produce json
 // @Param personId path int true "Person ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/birth-date-info [delete]
 func (api *BirthDateInfoAPI) DeleteBirthDateInfo(w http.ResponseWriter, r *http.Request) {
-	personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
-		return
-	}
-
-	// Check if person exists
-	person, err := api.personRepo.GetByID(personID)
-	if err != nil {
-		WriteInternalError(w, "Failed to fetch person")
-		return
-	}
-	if person == nil {
-		WriteNotFound(w, "Person not found")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if err := api.repo.Delete(personID); err != nil {
-		WriteInternalError(w, "Failed to delete birth date info")
+	if err := api.service.DeleteBirthDateInfo(r.Context(), personID); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,46 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func TestBirthDateInfoUpsertAndDelete(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	path := fmt.Sprintf("/api/people/%d/birth-date-info", personID)
+	if res := alice.mustDo(http.MethodGet, path, nil, http.StatusOK, nil); strings.TrimSpace(string(res.body)) != "null" {
+		t.Errorf("birth date info of a new person = %s, want null", res.body)
+	}
+	year, month, day := 1990, 3, 7
+	var info dto.BirthDateInfoResponse
+	alice.mustDo(http.MethodPut, path, dto.BirthDateInfoRequest{BirthYear: &year, BirthMonth: &month, BirthDay: &day}, http.StatusCreated, &info)
+	if info.PersonID != personID || *info.BirthYear != 1990 || *info.BirthMonth != 3 || *info.BirthDay != 7 {
+		t.Fatalf("birth date info = %+v", info)
+	}
+	age := 40
+	var replaced dto.BirthDateInfoResponse
+	alice.mustDo(http.MethodPut, path, dto.BirthDateInfoRequest{ApproximateAge: &age}, http.StatusOK, &replaced)
+	if replaced.BirthYear != nil || replaced.ApproximateAge == nil || *replaced.ApproximateAge != 40 || replaced.ApproximateAgeUpdatedAt == nil {
+		t.Errorf("birth date info = %+v, want only the approximate age", replaced)
+	}
+	alice.mustDo(http.MethodDelete, path, nil, http.StatusNoContent, nil)
+	if res := alice.mustDo(http.MethodGet, path, nil, http.StatusOK, nil); strings.TrimSpace(string(res.body)) != "null" {
+		t.Errorf("after delete got %s, want null", res.body)
+	}
+}
+
+func TestBirthDateInfoValidation(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	month, day := 2, 30
+	problem := alice.do(http.MethodPut, fmt.Sprintf("/api/people/%d/birth-date-info", personID), dto.BirthDateInfoRequest{BirthMonth: &month, BirthDay: &day}).problem(t, http.StatusBadRequest)
+	if len(problem.Errors) == 0 {
+		t.Errorf("problem = %+v, want field errors for February 30", problem)
+	}
+	alice.do(http.MethodPut, "/api/people/999/birth-date-info", dto.BirthDateInfoRequest{}).problem(t, http.StatusNotFound)
+}
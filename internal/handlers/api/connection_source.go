@@ -1,24 +1,22 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
 	"github.com/lincentpega/pcrm/internal/mappers"
-	"github.com/lincentpega/pcrm/internal/repository"
+	"github.com/lincentpega/pcrm/internal/services"
 	"github.com/lincentpega/pcrm/internal/validators"
 )
 
 type ConnectionSourceAPI struct {
-	repo     *repository.ConnectionSourceRepository
-	personRepo *repository.PersonRepository
+	service *services.ConnectionSourceService
 }
 
-func NewConnectionSourceAPI(repo *repository.ConnectionSourceRepository, personRepo *repository.PersonRepository) *ConnectionSourceAPI {
+func NewConnectionSourceAPI(service *services.ConnectionSourceService) *ConnectionSourceAPI {
 	return &ConnectionSourceAPI{
-		repo:     repo,
-		personRepo: personRepo,
+		service: service,
 	}
 }
 
@@ -36,30 +34,22 @@ func NewConnectionSourceAPI(repo *repository.ConnectionSourceRepository, personR
 // @Param personId path int true "Person ID"
 // @Success 200 {object} dto.ConnectionSourceResponse "Person exists and has connection source info"
 // @Success 200 {object} nil "Person exists but no connection source info"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse "Person not found"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details "Person not found"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/connection-source [get]
 func (api *ConnectionSourceAPI) GetConnectionSource(w http.ResponseWriter, r *http.Request) {
-	personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	// Check if person exists
-	person, err := api.personRepo.GetByID(personID)
+	connectionSource, err := api.service.GetConnectionSource(r.Context(), personID)
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch person")
-		return
-	}
-	if person == nil {
-		WriteNotFound(w, "Person not found")
-		return
-	}
-
-	connectionSource, err := api.repo.GetByPersonID(personID)
-	if err != nil {
-		WriteInternalError(w, "Failed to fetch connection source")
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -82,56 +72,46 @@ func (api *ConnectionSourceAPI) GetConnectionSource(w http.ResponseWriter, r *ht
 // @Param connectionSource body dto.ConnectionSourceRequest true "Connection source data"
 // @Success 200 {object} dto.ConnectionSourceResponse "Updated"
 // @Success 201 {object} dto.ConnectionSourceResponse "Created"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 422 {object} problem.Details "Unknown introducer person"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/connection-source [put]
 func (api *ConnectionSourceAPI) UpsertConnectionSource(w http.ResponseWriter, r *http.Request) {
-	personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
-		return
-	}
-
-	// Check if person exists
-	person, err := api.personRepo.GetByID(personID)
-	if err != nil {
-		WriteInternalError(w, "Failed to fetch person")
-		return
-	}
-	if person == nil {
-		WriteNotFound(w, "Person not found")
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	var req dto.ConnectionSourceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteBadRequest(w, "Invalid JSON format")
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	if err := validators.ValidateConnectionSourceRequest(&req); err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	connectionSource := mappers.ConnectionSourceRequestToDomain(personID, &req)
 
-	// Check if connection source already exists to determine status code
-	existingConnectionSource, err := api.repo.GetByPersonID(personID)
-	isUpdate := err == nil && existingConnectionSource != nil
-
-	if err := api.repo.Upsert(connectionSource); err != nil {
-		WriteInternalError(w, "Failed to save connection source")
+	created, err := api.service.UpsertConnectionSource(r.Context(), connectionSource)
+	if err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	response := mappers.ConnectionSourceDomainToResponse(connectionSource)
 	
-	if isUpdate {
-		WriteSuccess(w, response)
-	} else {
+	if created {
 		WriteCreated(w, response)
+	} else {
+		WriteSuccess(w, response)
 	}
 }
 
@@ -143,30 +123,22 @@ func (api *ConnectionSourceAPI) UpsertConnectionSource(w http.ResponseWriter, r
 // @Produce json
 // @Param personId path int true "Person ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/connection-source [delete]
 func (api *ConnectionSourceAPI) DeleteConnectionSource(w http.ResponseWriter, r *http.Request) {
-	personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
-		return
-	}
-
-	// Check if person exists
-	person, err := api.personRepo.GetByID(personID)
-	if err != nil {
-		WriteInternalError(w, "Failed to fetch person")
-		return
-	}
-	if person == nil {
-		WriteNotFound(w, "Person not found")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if err := api.repo.Delete(personID); err != nil {
-		WriteInternalError(w, "Failed to delete connection source")
+	if err := api.service.DeleteConnectionSource(r.Context(), personID); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,58 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func TestConnectionSourceUpsertAndDelete(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	introducerID := alice.createPerson("Alice", "Smith")
+	personID := alice.createPerson("Bob", "")
+	path := fmt.Sprintf("/api/people/%d/connection-source", personID)
+	if res := alice.mustDo(http.MethodGet, path, nil, http.StatusOK, nil); strings.TrimSpace(string(res.body)) != "null" {
+		t.Errorf("connection source of a new person = %s, want null", res.body)
+	}
+	story := "Met at a conference"
+	met := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	introduced := true
+	var source dto.ConnectionSourceResponse
+	alice.mustDo(http.MethodPut, path, dto.ConnectionSourceRequest{
+		MeetingStory:       &story,
+		MeetingTimestamp:   &met,
+		WasIntroduced:      &introduced,
+		IntroducerPersonID: &introducerID,
+	}, http.StatusCreated, &source)
+	if source.PersonID != personID || source.IntroducerPersonID == nil || *source.IntroducerPersonID != introducerID || !source.MeetingTimestamp.Equal(met) {
+		t.Fatalf("connection source = %+v", source)
+	}
+	story = "Met at a meetup"
+	alice.mustDo(http.MethodPut, path, dto.ConnectionSourceRequest{MeetingStory: &story}, http.StatusOK, nil)
+	var replaced dto.ConnectionSourceResponse
+	alice.mustDo(http.MethodGet, path, nil, http.StatusOK, &replaced)
+	if *replaced.MeetingStory != "Met at a meetup" || replaced.IntroducerPersonID != nil || replaced.MeetingTimestamp != nil {
+		t.Errorf("replaced connection source = %+v", replaced)
+	}
+	alice.mustDo(http.MethodDelete, path, nil, http.StatusNoContent, nil)
+	if res := alice.mustDo(http.MethodGet, path, nil, http.StatusOK, nil); strings.TrimSpace(string(res.body)) != "null" {
+		t.Errorf("after delete got %s, want null", res.body)
+	}
+}
+
+func TestConnectionSourceRejectsForeignIntroducer(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	mallory := server.signUp(t, "mallory")
+	personID := alice.createPerson("Bob", "")
+	strangerID := mallory.createPerson("Eve", "")
+	introduced := true
+	res := alice.do(http.MethodPut, fmt.Sprintf("/api/people/%d/connection-source", personID), dto.ConnectionSourceRequest{WasIntroduced: &introduced, IntroducerPersonID: &strangerID})
+	if res.status < 400 || res.status >= 500 {
+		t.Fatalf("status = %d, want a client error; body: %s", res.status, res.body)
+	}
+}
@@ -1,73 +1,23 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
-	"strconv"
-	"time"
 
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/mappers"
 	"github.com/lincentpega/pcrm/internal/models"
-	"github.com/lincentpega/pcrm/internal/repository"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
 )
 
 type ContactAPI struct {
-	contactRepo *repository.ContactRepository
+	service *services.ContactService
 }
 
-func NewContactAPI(contactRepo *repository.ContactRepository) *ContactAPI {
+func NewContactAPI(service *services.ContactService) *ContactAPI {
 	return &ContactAPI{
-		contactRepo: contactRepo,
-	}
-}
-
-type ContactRequest struct {
-	ContactTypeID int64  `json:"contactTypeId" validate:"required"`
-	Content       string `json:"content" validate:"required"`
-}
-
-type ContactResponse struct {
-    ID            int64                `json:"id"`
-    PersonID      int64                `json:"personId"`
-    Content       string               `json:"content"`
-    CreatedAt     time.Time            `json:"createdAt"`
-    UpdatedAt     time.Time            `json:"updatedAt"`
-    ContactType   ContactTypeResponse  `json:"contactType"`
-}
-
-type ContactTypeResponse struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"createdAt"`
-}
-
-func (req *ContactRequest) ToContact(personID int64) *models.Contact {
-	return &models.Contact{
-		PersonID:      personID,
-		ContactTypeID: req.ContactTypeID,
-		Content:       req.Content,
-	}
-}
-
-func ContactToResponse(contact *models.Contact) ContactResponse {
-    return ContactResponse{
-        ID:            contact.ID,
-        PersonID:      contact.PersonID,
-        Content:       contact.Content,
-        CreatedAt:     contact.CreatedAt,
-        UpdatedAt:     contact.UpdatedAt,
-        ContactType: ContactTypeResponse{
-            ID:        contact.ContactType.ID,
-			Name:      contact.ContactType.Name,
-			CreatedAt: contact.ContactType.CreatedAt,
-		},
-	}
-}
-
-func ContactTypeToResponse(contactType *models.ContactType) ContactTypeResponse {
-	return ContactTypeResponse{
-		ID:        contactType.ID,
-		Name:      contactType.Name,
-		CreatedAt: contactType.CreatedAt,
+		service: service,
 	}
 }
 
@@ -78,27 +28,29 @@ func ContactTypeToResponse(contactType *models.ContactType) ContactTypeResponse
 // @Accept json
 // @Produce json
 // @Param personId path int true "Person ID"
-// @Success 200 {array} ContactResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 200 {array} dto.ContactResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/contacts [get]
 func (api *ContactAPI) ListContactsByPerson(w http.ResponseWriter, r *http.Request) {
-	personIDStr := r.PathValue("personId")
-	personID, err := strconv.ParseInt(personIDStr, 10, 64)
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, "Invalid person ID")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	contacts, err := api.contactRepo.GetByPersonID(personID)
+	contacts, err := api.service.ListPersonContacts(r.Context(), personID)
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch contacts")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	response := make([]ContactResponse, len(contacts))
+	response := make([]dto.ContactResponse, len(contacts))
 	for i, contact := range contacts {
-		response[i] = ContactToResponse(&contact)
+		response[i] = mappers.ContactDomainToResponse(&contact)
 	}
 
 	WriteSuccess(w, response)
@@ -111,29 +63,27 @@ func (api *ContactAPI) ListContactsByPerson(w http.ResponseWriter, r *http.Reque
 // @Accept json
 // @Produce json
 // @Param id path int true "Contact ID"
-// @Success 200 {object} ContactResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Success 200 {object} dto.ContactResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/contacts/{id} [get]
 func (api *ContactAPI) GetContact(w http.ResponseWriter, r *http.Request) {
-    idStr := r.PathValue("id")
-    id, err := strconv.ParseInt(idStr, 10, 64)
+    id, err := PathID(r, "id")
     if err != nil {
-        WriteBadRequest(w, "Invalid contact ID")
+        problem.WriteError(w, r, err)
         return
     }
 
-    contact, err := api.contactRepo.GetByID(id)
+    contact, err := api.service.GetContact(r.Context(), id)
     if err != nil {
-        WriteInternalError(w, "Failed to fetch contact")
-        return
-    }
-    if contact == nil {
-        WriteNotFound(w, "Contact not found")
+        problem.WriteError(w, r, err)
         return
     }
 
-    response := ContactToResponse(contact)
+    response := mappers.ContactDomainToResponse(contact)
     WriteSuccess(w, response)
 }
 
@@ -144,48 +94,42 @@ func (api *ContactAPI) GetContact(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param personId path int true "Person ID"
-// @Param contact body ContactRequest true "Contact data"
-// @Success 201 {object} ContactResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Param contact body dto.ContactRequest true "Contact data"
+// @Success 201 {object} dto.ContactResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 422 {object} problem.Details "Unknown contact type"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/contacts [post]
 func (api *ContactAPI) CreateContact(w http.ResponseWriter, r *http.Request) {
-	personIDStr := r.PathValue("personId")
-	personID, err := strconv.ParseInt(personIDStr, 10, 64)
+	personID, err := PathID(r, "personId")
 	if err != nil {
-		WriteBadRequest(w, "Invalid person ID")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	var req ContactRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteBadRequest(w, "Invalid JSON format")
+	var req dto.ContactRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if req.ContactTypeID == 0 {
-		WriteBadRequest(w, "Contact type ID is required")
+	if err := validators.ValidateContactRequest(&req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if req.Content == "" {
-		WriteBadRequest(w, "Content is required")
-		return
-	}
-
-	contact := req.ToContact(personID)
-	if err := api.contactRepo.Create(contact); err != nil {
-		WriteInternalError(w, "Failed to create contact")
-		return
-	}
-
-	createdContact, err := api.contactRepo.GetByID(contact.ID)
+	contact := mappers.ContactRequestToDomain(personID, &req)
+	createdContact, err := api.service.CreateContact(r.Context(), contact)
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch created contact")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	response := ContactToResponse(createdContact)
+	response := mappers.ContactDomainToResponse(createdContact)
 	WriteCreated(w, response)
 }
 
@@ -196,65 +140,47 @@ func (api *ContactAPI) CreateContact(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Contact ID"
-// @Param contact body ContactRequest true "Updated contact data"
-// @Success 200 {object} ContactResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Param contact body dto.ContactRequest true "Updated contact data"
+// @Success 200 {object} dto.ContactResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 422 {object} problem.Details "Unknown contact type"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/contacts/{id} [put]
 func (api *ContactAPI) UpdateContact(w http.ResponseWriter, r *http.Request) {
-	idStr := r.PathValue("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, err := PathID(r, "id")
 	if err != nil {
-		WriteBadRequest(w, "Invalid contact ID")
-		return
-	}
-
-	var req ContactRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteBadRequest(w, "Invalid JSON format")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if req.ContactTypeID == 0 {
-		WriteBadRequest(w, "Contact type ID is required")
+	var req dto.ContactRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if req.Content == "" {
-		WriteBadRequest(w, "Content is required")
-		return
-	}
-
-    existingContact, err := api.contactRepo.GetByID(id)
-    if err != nil {
-        WriteInternalError(w, "Failed to fetch contact")
-        return
-    }
-    if existingContact == nil {
-        WriteNotFound(w, "Contact not found")
+	if err := validators.ValidateContactRequest(&req); err != nil {
+		problem.WriteError(w, r, err)
         return
     }
 
 	contact := &models.Contact{
 		ID:            id,
-		PersonID:      existingContact.PersonID,
 		ContactTypeID: req.ContactTypeID,
 		Content:       req.Content,
 	}
 
-	if err := api.contactRepo.Update(contact); err != nil {
-		WriteInternalError(w, "Failed to update contact")
-		return
-	}
-
-	updatedContact, err := api.contactRepo.GetByID(id)
+	updatedContact, err := api.service.UpdateContact(r.Context(), contact)
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch updated contact")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	response := ContactToResponse(updatedContact)
+	response := mappers.ContactDomainToResponse(updatedContact)
 	WriteSuccess(w, response)
 }
 
@@ -266,19 +192,22 @@ func (api *ContactAPI) UpdateContact(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Contact ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/contacts/{id} [delete]
 func (api *ContactAPI) DeleteContact(w http.ResponseWriter, r *http.Request) {
-	idStr := r.PathValue("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, err := PathID(r, "id")
 	if err != nil {
-		WriteBadRequest(w, "Invalid contact ID")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if err := api.contactRepo.Delete(id); err != nil {
-		WriteInternalError(w, "Failed to delete contact")
+	if err := api.service.DeleteContact(r.Context(), id); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -291,19 +220,21 @@ func (api *ContactAPI) DeleteContact(w http.ResponseWriter, r *http.Request) {
 // @Tags contact-types
 // @Accept json
 // @Produce json
-// @Success 200 {array} ContactTypeResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 200 {array} dto.ContactTypeResponse
+// @Failure 401 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/contact-types [get]
 func (api *ContactAPI) ListContactTypes(w http.ResponseWriter, r *http.Request) {
-	contactTypes, err := api.contactRepo.GetContactTypes()
+	contactTypes, err := api.service.ListContactTypes(r.Context())
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch contact types")
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	response := make([]ContactTypeResponse, len(contactTypes))
+	response := make([]dto.ContactTypeResponse, len(contactTypes))
 	for i, contactType := range contactTypes {
-		response[i] = ContactTypeToResponse(&contactType)
+		response[i] = mappers.ContactTypeDomainToResponse(&contactType)
 	}
 
 	WriteSuccess(w, response)
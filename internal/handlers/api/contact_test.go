@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func TestContactCRUD(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	emailType := alice.typeID("/api/contact-types", "Email")
+	phoneType := alice.typeID("/api/contact-types", "Phone")
+	contactsPath := fmt.Sprintf("/api/people/%d/contacts", personID)
+	var created dto.ContactResponse
+	alice.mustDo(http.MethodPost, contactsPath, dto.ContactRequest{ContactTypeID: emailType, Content: "bob@example.com"}, http.StatusCreated, &created)
+	if created.PersonID != personID || created.Content != "bob@example.com" || created.ContactType.Name != "Email" {
+		t.Fatalf("created contact = %+v", created)
+	}
+	path := fmt.Sprintf("/api/contacts/%d", created.ID)
+	var updated dto.ContactResponse
+	alice.mustDo(http.MethodPut, path, dto.ContactRequest{ContactTypeID: phoneType, Content: "+1 555 0100"}, http.StatusOK, &updated)
+	if updated.Content != "+1 555 0100" || updated.ContactType.Name != "Phone" {
+		t.Errorf("updated contact = %+v", updated)
+	}
+	var listed []dto.ContactResponse
+	alice.mustDo(http.MethodGet, contactsPath, nil, http.StatusOK, &listed)
+	if len(listed) != 1 || listed[0].ID != created.ID {
+		t.Errorf("contacts = %+v", listed)
+	}
+	alice.mustDo(http.MethodDelete, path, nil, http.StatusNoContent, nil)
+	alice.do(http.MethodGet, path, nil).problem(t, http.StatusNotFound)
+}
+
+func TestCreateContactErrors(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	contactsPath := fmt.Sprintf("/api/people/%d/contacts", personID)
+	problem := alice.do(http.MethodPost, contactsPath, dto.ContactRequest{}).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "contactTypeId", "required") || !hasFieldProblem(problem, "content", "required") {
+		t.Errorf("errors = %+v, want contactTypeId and content required", problem.Errors)
+	}
+	emailType := alice.typeID("/api/contact-types", "Email")
+	alice.do(http.MethodPost, "/api/people/999/contacts", dto.ContactRequest{ContactTypeID: emailType, Content: "x"}).problem(t, http.StatusNotFound)
+	alice.do(http.MethodPost, "/api/people/abc/contacts", dto.ContactRequest{ContactTypeID: emailType, Content: "x"}).problem(t, http.StatusBadRequest)
+	mallory := server.signUp(t, "mallory")
+	mallory.do(http.MethodPost, contactsPath, dto.ContactRequest{ContactTypeID: emailType, Content: "x"}).problem(t, http.StatusNotFound)
+	mallory.do(http.MethodGet, contactsPath, nil).problem(t, http.StatusNotFound)
+}
+
+func TestListContactTypes(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	var types []dto.ContactTypeResponse
+	alice.mustDo(http.MethodGet, "/api/contact-types", nil, http.StatusOK, &types)
+	if len(types) != 5 {
+		t.Errorf("contact types = %+v, want the 5 seeded ones", types)
+	}
+}
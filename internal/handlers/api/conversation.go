@@ -1,23 +1,22 @@
 package api
 
 import (
-    "encoding/json"
     "net/http"
 
     "github.com/lincentpega/pcrm/internal/dto"
+    "github.com/lincentpega/pcrm/internal/handlers/problem"
     "github.com/lincentpega/pcrm/internal/mappers"
     "github.com/lincentpega/pcrm/internal/models"
-    "github.com/lincentpega/pcrm/internal/repository"
+    "github.com/lincentpega/pcrm/internal/services"
     "github.com/lincentpega/pcrm/internal/validators"
 )
 
 type ConversationAPI struct {
-    repo       *repository.ConversationRepository
-    personRepo *repository.PersonRepository
+    service *services.ConversationService
 }
 
-func NewConversationAPI(repo *repository.ConversationRepository, personRepo *repository.PersonRepository) *ConversationAPI {
-    return &ConversationAPI{repo: repo, personRepo: personRepo}
+func NewConversationAPI(service *services.ConversationService) *ConversationAPI {
+    return &ConversationAPI{service: service}
 }
 
 // ListConversationsByPerson godoc
@@ -28,18 +27,21 @@ func NewConversationAPI(repo *repository.ConversationRepository, personRepo *rep
 // @Produce json
 // @Param personId path int true "Person ID"
 // @Success 200 {array} dto.ConversationResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/conversations [get]
 func (api *ConversationAPI) ListConversationsByPerson(w http.ResponseWriter, r *http.Request) {
-    personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+    personID, err := PathID(r, "personId")
     if err != nil {
-        WriteBadRequest(w, err.Error())
+        problem.WriteError(w, r, err)
         return
     }
-    conversations, err := api.repo.GetByPersonID(personID)
+    conversations, err := api.service.ListPersonConversations(r.Context(), personID)
     if err != nil {
-        WriteInternalError(w, "Failed to fetch conversations")
+        problem.WriteError(w, r, err)
         return
     }
     response := make([]dto.ConversationResponse, len(conversations))
@@ -57,18 +59,21 @@ func (api *ConversationAPI) ListConversationsByPerson(w http.ResponseWriter, r *
 // @Produce json
 // @Param id path int true "Conversation ID"
 // @Success 200 {object} dto.ConversationResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/conversations/{id} [get]
 func (api *ConversationAPI) GetConversation(w http.ResponseWriter, r *http.Request) {
-    id, err := validators.ValidateConversationID(r.PathValue("id"))
+    id, err := PathID(r, "id")
     if err != nil {
-        WriteBadRequest(w, err.Error())
+        problem.WriteError(w, r, err)
         return
     }
-    conversation, err := api.repo.GetByID(id)
+    conversation, err := api.service.GetConversation(r.Context(), id)
     if err != nil {
-        WriteNotFound(w, "Conversation not found")
+        problem.WriteError(w, r, err)
         return
     }
     response := mappers.ConversationDomainToResponse(conversation)
@@ -84,42 +89,33 @@ func (api *ConversationAPI) GetConversation(w http.ResponseWriter, r *http.Reque
 // @Param personId path int true "Person ID"
 // @Param conversation body dto.ConversationRequest true "Conversation data"
 // @Success 201 {object} dto.ConversationResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 422 {object} problem.Details "Unknown conversation type"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{personId}/conversations [post]
 func (api *ConversationAPI) CreateConversation(w http.ResponseWriter, r *http.Request) {
-    personID, err := validators.ValidatePersonID(r.PathValue("personId"))
+    personID, err := PathID(r, "personId")
     if err != nil {
-        WriteBadRequest(w, err.Error())
-        return
-    }
-    person, err := api.personRepo.GetByID(personID)
-    if err != nil {
-        WriteInternalError(w, "Failed to fetch person")
-        return
-    }
-    if person == nil {
-        WriteNotFound(w, "Person not found")
+        problem.WriteError(w, r, err)
         return
     }
     var req dto.ConversationRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        WriteBadRequest(w, "Invalid JSON format")
+    if err := DecodeJSON(r, &req); err != nil {
+        problem.WriteError(w, r, err)
         return
     }
     if err := validators.ValidateConversationRequest(&req); err != nil {
-        WriteBadRequest(w, err.Error())
+        problem.WriteError(w, r, err)
         return
     }
     conversation := mappers.ConversationRequestToDomain(personID, &req)
-    if err := api.repo.Create(conversation); err != nil {
-        WriteInternalError(w, "Failed to create conversation")
-        return
-    }
-    created, err := api.repo.GetByID(conversation.ID)
-    if err != nil || created == nil {
-        WriteInternalError(w, "Failed to fetch created conversation")
+    created, err := api.service.CreateConversation(r.Context(), conversation)
+    if err != nil {
+        problem.WriteError(w, r, err)
         return
     }
     response := mappers.ConversationDomainToResponse(created)
@@ -135,38 +131,33 @@ func (api *ConversationAPI) CreateConversation(w http.ResponseWriter, r *http.Re
 // @Param id path int true "Conversation ID"
 // @Param conversation body dto.ConversationRequest true "Updated conversation data"
 // @Success 200 {object} dto.ConversationResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 422 {object} problem.Details "Unknown conversation type"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/conversations/{id} [put]
 func (api *ConversationAPI) UpdateConversation(w http.ResponseWriter, r *http.Request) {
-    id, err := validators.ValidateConversationID(r.PathValue("id"))
+    id, err := PathID(r, "id")
     if err != nil {
-        WriteBadRequest(w, err.Error())
+        problem.WriteError(w, r, err)
         return
     }
     var req dto.ConversationRequest
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        WriteBadRequest(w, "Invalid JSON format")
+    if err := DecodeJSON(r, &req); err != nil {
+        problem.WriteError(w, r, err)
         return
     }
     if err := validators.ValidateConversationRequest(&req); err != nil {
-        WriteBadRequest(w, err.Error())
-        return
-    }
-    existing, err := api.repo.GetByID(id)
-    if err != nil || existing == nil {
-        WriteNotFound(w, "Conversation not found")
+        problem.WriteError(w, r, err)
         return
     }
-    conversation := &models.Conversation{ID: id, PersonID: existing.PersonID, ConversationTypeID: req.ConversationTypeID, Initiator: req.Initiator, Notes: req.Notes}
-    if err := api.repo.Update(conversation); err != nil {
-        WriteInternalError(w, "Failed to update conversation")
-        return
-    }
-    updated, err := api.repo.GetByID(id)
-    if err != nil || updated == nil {
-        WriteInternalError(w, "Failed to fetch updated conversation")
+    conversation := &models.Conversation{ID: id, ConversationTypeID: req.ConversationTypeID, Initiator: req.Initiator, Notes: req.Notes}
+    updated, err := api.service.UpdateConversation(r.Context(), conversation)
+    if err != nil {
+        problem.WriteError(w, r, err)
         return
     }
     response := mappers.ConversationDomainToResponse(updated)
@@ -181,17 +172,21 @@ func (api *ConversationAPI) UpdateConversation(w http.ResponseWriter, r *http.Re
 // @Produce json
 // @Param id path int true "Conversation ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/conversations/{id} [delete]
 func (api *ConversationAPI) DeleteConversation(w http.ResponseWriter, r *http.Request) {
-    id, err := validators.ValidateConversationID(r.PathValue("id"))
+    id, err := PathID(r, "id")
     if err != nil {
-        WriteBadRequest(w, err.Error())
+        problem.WriteError(w, r, err)
         return
     }
-    if err := api.repo.Delete(id); err != nil {
-        WriteInternalError(w, "Failed to delete conversation")
+    if err := api.service.DeleteConversation(r.Context(), id); err != nil {
+        problem.WriteError(w, r, err)
         return
     }
     w.WriteHeader(http.StatusNoContent)
@@ -204,12 +199,14 @@ func (api *ConversationAPI) DeleteConversation(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Success 200 {array} dto.ConversationTypeResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 401 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/conversation-types [get]
 func (api *ConversationAPI) ListConversationTypes(w http.ResponseWriter, r *http.Request) {
-    types, err := api.repo.GetConversationTypes()
+    types, err := api.service.ListConversationTypes(r.Context())
     if err != nil {
-        WriteInternalError(w, "Failed to fetch conversation types")
+        problem.WriteError(w, r, err)
         return
     }
     response := make([]dto.ConversationTypeResponse, len(types))
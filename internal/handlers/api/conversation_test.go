@@ -0,0 +1,62 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func TestConversationCRUD(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	callType := alice.typeID("/api/conversation-types", "Phone Call")
+	meetingType := alice.typeID("/api/conversation-types", "In-Person Meeting")
+	conversationsPath := fmt.Sprintf("/api/people/%d/conversations", personID)
+	var created dto.ConversationResponse
+	alice.mustDo(http.MethodPost, conversationsPath, dto.ConversationRequest{ConversationTypeID: callType, Initiator: "person", Notes: "Asked about the job"}, http.StatusCreated, &created)
+	if created.PersonID != personID || created.Initiator != "person" || created.ConversationType.Name != "Phone Call" {
+		t.Fatalf("created conversation = %+v", created)
+	}
+	path := fmt.Sprintf("/api/conversations/%d", created.ID)
+	var updated dto.ConversationResponse
+	alice.mustDo(http.MethodPut, path, dto.ConversationRequest{ConversationTypeID: meetingType, Initiator: "owner", Notes: "Lunch"}, http.StatusOK, &updated)
+	if updated.Notes != "Lunch" || updated.Initiator != "owner" || updated.ConversationType.Name != "In-Person Meeting" {
+		t.Errorf("updated conversation = %+v", updated)
+	}
+	var listed []dto.ConversationResponse
+	alice.mustDo(http.MethodGet, conversationsPath, nil, http.StatusOK, &listed)
+	if len(listed) != 1 || listed[0].Notes != "Lunch" {
+		t.Errorf("conversations = %+v", listed)
+	}
+	alice.mustDo(http.MethodDelete, path, nil, http.StatusNoContent, nil)
+	alice.do(http.MethodGet, path, nil).problem(t, http.StatusNotFound)
+}
+
+func TestCreateConversationErrors(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	conversationsPath := fmt.Sprintf("/api/people/%d/conversations", personID)
+	callType := alice.typeID("/api/conversation-types", "Phone Call")
+	problem := alice.do(http.MethodPost, conversationsPath, dto.ConversationRequest{ConversationTypeID: callType, Initiator: "someone"}).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "initiator", "invalid") {
+		t.Errorf("errors = %+v, want initiator invalid", problem.Errors)
+	}
+	alice.do(http.MethodPost, conversationsPath, dto.ConversationRequest{ConversationTypeID: 999, Initiator: "owner", Notes: "Hi"}).problem(t, http.StatusUnprocessableEntity)
+	var created dto.ConversationResponse
+	alice.mustDo(http.MethodPost, conversationsPath, dto.ConversationRequest{ConversationTypeID: callType, Initiator: "owner", Notes: "Hi"}, http.StatusCreated, &created)
+	mallory := server.signUp(t, "mallory")
+	mallory.do(http.MethodGet, fmt.Sprintf("/api/conversations/%d", created.ID), nil).problem(t, http.StatusNotFound)
+	mallory.do(http.MethodDelete, fmt.Sprintf("/api/conversations/%d", created.ID), nil).problem(t, http.StatusNotFound)
+}
+
+func TestListConversationTypes(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	var types []dto.ConversationTypeResponse
+	alice.mustDo(http.MethodGet, "/api/conversation-types", nil, http.StatusOK, &types)
+	if len(types) != 6 {
+		t.Errorf("conversation types = %+v, want the 6 seeded ones", types)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/exporters"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+type DossierAPI struct {
+	service *services.DossierService
+}
+
+func NewDossierAPI(service *services.DossierService) *DossierAPI {
+	return &DossierAPI{service: service}
+}
+
+// ExportDossier godoc
+// @Summary Export the dossier of a person as Markdown
+// @Description Download a Markdown note about the person for a notes app such as Obsidian. YAML front matter holds
+// @Description the ids, name, company, job title, tags, birthday and contacts. It is followed by how you met, the
+// @Description open follow-ups, the related people and the conversations, most recent first. A conversation the
+// @Description person started is open until you reach out after it. Related people are the introducer, the people
+// @Description the person introduced you to, the others the introducer introduced you to and colleagues at the same
+// @Description company. Other people are wiki-linked by the names their notes get in the bulk export.
+// @Tags export
+// @Produce text/markdown
+// @Param id path int true "Person ID"
+// @Success 200 {string} string "Markdown note"
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/people/{id}/export.md [get]
+func (api *DossierAPI) ExportDossier(w http.ResponseWriter, r *http.Request) {
+	id, err := validators.ValidateID("id", r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	dossier, people, err := api.service.GetDossier(r.Context(), id)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	names := exporters.NoteNames(people)
+	var note bytes.Buffer
+	if err := exporters.WriteDossier(&note, dossier, names); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeAttachment(w, "text/markdown; charset=utf-8", names[id]+".md", note.Bytes())
+}
+
+// ExportDossiers godoc
+// @Summary Export the dossiers of all people as Markdown
+// @Description Download a zip archive with the Markdown note of every person, as GET /api/people/{id}/export.md
+// @Description writes it. Notes are named after the people, with the id appended when names clash, so the
+// @Description wiki-links between them resolve once the archive is unpacked into a vault.
+// @Tags export
+// @Produce application/zip
+// @Success 200 {file} file "Zip archive of Markdown notes"
+// @Failure 401 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/export/markdown [get]
+func (api *DossierAPI) ExportDossiers(w http.ResponseWriter, r *http.Request) {
+	dossiers, people, err := api.service.ListDossiers(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	var archive bytes.Buffer
+	if err := exporters.WriteDossierZip(&archive, dossiers, exporters.NoteNames(people)); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeAttachment(w, "application/zip", fmt.Sprintf("pcrm-%s.zip", time.Now().UTC().Format("20060102-150405")), archive.Bytes())
+}
+
+// writeAttachment sends body as a download named filename.
+func writeAttachment(w http.ResponseWriter, contentType, filename string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,93 @@
+package api_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func TestExportDossier(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	introducerID := alice.createPerson("Alice", "Smith")
+	bobID := alice.createPerson("Bob", "Lee")
+	story := "Met at a conference"
+	alice.mustDo(http.MethodPut, fmt.Sprintf("/api/people/%d/connection-source", bobID), dto.ConnectionSourceRequest{MeetingStory: &story, IntroducerPersonID: &introducerID}, http.StatusCreated, nil)
+	alice.mustDo(http.MethodPut, fmt.Sprintf("/api/people/%d/tags", bobID), dto.TagsRequest{Tags: []string{"close friend"}}, http.StatusOK, nil)
+	carolID := alice.createPerson("Carol", "")
+	alice.mustDo(http.MethodPut, fmt.Sprintf("/api/people/%d/connection-source", carolID), dto.ConnectionSourceRequest{IntroducerPersonID: &introducerID}, http.StatusCreated, nil)
+	lee, company, sameCompany := "Lee", "Acme", "acme"
+	alice.mustDo(http.MethodPut, fmt.Sprintf("/api/people/%d", bobID), dto.PersonUpsertRequest{FirstName: "Bob", SecondName: &lee, Company: &company}, http.StatusOK, nil)
+	alice.mustDo(http.MethodPost, "/api/people", dto.PersonCreateRequest{PersonUpsertRequest: dto.PersonUpsertRequest{FirstName: "Dan", Company: &sameCompany}}, http.StatusCreated, nil)
+	callType := alice.typeID("/api/conversation-types", "Phone Call")
+	alice.mustDo(http.MethodPost, fmt.Sprintf("/api/people/%d/conversations", bobID), dto.ConversationRequest{ConversationTypeID: callType, Initiator: "person", Notes: "Asked for an intro to Dan"}, http.StatusCreated, nil)
+	res := alice.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d/export.md", bobID), nil, http.StatusOK, nil)
+	if got := res.header.Get("Content-Type"); got != "text/markdown; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := res.header.Get("Content-Disposition"); got != `attachment; filename="Bob Lee.md"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	note := string(res.body)
+	for _, want := range []string{
+		fmt.Sprintf("---\nid: %d\nname: Bob Lee\n", bobID),
+		"tags:\n    - close-friend\n",
+		fmt.Sprintf("introducerId: %d\n", introducerID),
+		"# Bob Lee\n",
+		"## How we met\n\nIntroduced by [[Alice Smith]].\n\nMet at a conference\n",
+		"## Open follow-ups\n\n- [ ] **",
+		" Phone Call: Asked for an intro to Dan\n",
+		"## Related people\n\n- [[Alice Smith]], who introduced us\n- [[Carol]], also introduced by [[Alice Smith]]\n- [[Dan]], also at Acme\n",
+	} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note lacks %q:\n%s", want, note)
+		}
+	}
+	introducerNote := string(alice.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d/export.md", introducerID), nil, http.StatusOK, nil).body)
+	if !strings.Contains(introducerNote, "## Open follow-ups\n\nNothing to follow up on.\n") || !strings.Contains(introducerNote, "## Related people\n\n- [[Bob Lee]], whom they introduced\n- [[Carol]], whom they introduced\n") {
+		t.Errorf("introducer note lacks the introduced person:\n%s", introducerNote)
+	}
+	alice.do(http.MethodGet, "/api/people/999/export.md", nil).problem(t, http.StatusNotFound)
+}
+
+func TestExportDossiersZip(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	alice.createPerson("Bob", "Lee")
+	clashID := alice.createPerson("Bob", "Lee")
+	alice.createPerson("Carol", "")
+	res := alice.mustDo(http.MethodGet, "/api/export/markdown", nil, http.StatusOK, nil)
+	if got := res.header.Get("Content-Type"); got != "application/zip" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(res.body), int64(len(res.body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	slices.Sort(names)
+	want := []string{"Bob Lee (1).md", fmt.Sprintf("Bob Lee (%d).md", clashID), "Carol.md"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("zip entries = %v, want %v", names, want)
+	}
+	file, err := archive.Open("Carol.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	note, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(note), "# Carol\n") {
+		t.Errorf("Carol.md = %s", note)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/lincentpega/pcrm/internal/buildinfo"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/mappers"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+type HealthAPI struct {
+	service *services.HealthService
+}
+
+func NewHealthAPI(service *services.HealthService) *HealthAPI {
+	return &HealthAPI{service: service}
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Report that the process is running; no dependencies are checked
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Router /healthz [get]
+func (api *HealthAPI) Liveness(w http.ResponseWriter, r *http.Request) {
+	WriteSuccess(w, dto.HealthResponse{Status: "ok"})
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Check that the database answers, its schema is at the expected migration version and the server is not shutting down
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.ReadinessResponse
+// @Failure 503 {object} dto.ReadinessResponse
+// @Router /readyz [get]
+func (api *HealthAPI) Readiness(w http.ResponseWriter, r *http.Request) {
+	response := mappers.HealthChecksToReadinessResponse(api.service.Readiness(r.Context()))
+	status := http.StatusOK
+	if response.Status != "ready" {
+		status = http.StatusServiceUnavailable
+	}
+	WriteJSON(w, status, response)
+}
+
+// Version godoc
+// @Summary Build information
+// @Description Get the commit and build time of the running binary and the database schema version
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.VersionResponse
+// @Failure 500 {object} problem.Details
+// @Failure 503 {object} problem.Details
+// @Router /version [get]
+func (api *HealthAPI) Version(w http.ResponseWriter, r *http.Request) {
+	schema, err := api.service.SchemaVersion(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	WriteSuccess(w, mappers.BuildInfoToVersionResponse(buildinfo.Get(), schema))
+}
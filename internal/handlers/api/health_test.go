@@ -0,0 +1,91 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/api"
+	"github.com/lincentpega/pcrm/internal/migrate"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+// newHealthServer serves the health routes for a fresh SQLite database,
+// since the checks are about the database itself.
+func newHealthServer(t *testing.T) (*services.HealthService, *migrate.Migrator, http.Handler) {
+	t.Helper()
+	db, err := config.NewDatabase(config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "pcrm.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	health := services.NewHealthService(db, migrator, time.Second)
+	mux := http.NewServeMux()
+	api.RegisterHealthRoutes(mux, health)
+	return health, migrator, mux
+}
+
+func serveJSON(t *testing.T, handler http.Handler, path string, status int, out any) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if recorder.Code != status {
+		t.Fatalf("GET %s status = %d, want %d; body: %s", path, recorder.Code, status, recorder.Body)
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	_, _, handler := newHealthServer(t)
+	var response dto.HealthResponse
+	serveJSON(t, handler, "/healthz", http.StatusOK, &response)
+	if response.Status != "ok" {
+		t.Errorf("status = %q, want ok", response.Status)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	health, migrator, handler := newHealthServer(t)
+	var response dto.ReadinessResponse
+	serveJSON(t, handler, "/readyz", http.StatusServiceUnavailable, &response)
+	if len(response.Checks) != 2 || response.Checks[1].Name != "migrations" || response.Checks[1].Error == "" {
+		t.Errorf("readiness before migrating = %+v, want the migrations check failing", response)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	serveJSON(t, handler, "/readyz", http.StatusOK, &response)
+	if response.Status != "ready" {
+		t.Errorf("readiness after migrating = %+v", response)
+	}
+	health.StartShutdown()
+	var shuttingDown dto.ReadinessResponse
+	serveJSON(t, handler, "/readyz", http.StatusServiceUnavailable, &shuttingDown)
+	if len(shuttingDown.Checks) != 1 || shuttingDown.Checks[0].Name != "shutdown" {
+		t.Errorf("readiness during shutdown = %+v", shuttingDown)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	_, migrator, handler := newHealthServer(t)
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var response dto.VersionResponse
+	serveJSON(t, handler, "/version", http.StatusOK, &response)
+	if response.SchemaVersion != migrator.Latest() || response.SchemaDirty || response.GoVersion == "" {
+		t.Errorf("version = %+v", response)
+	}
+}
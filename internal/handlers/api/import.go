@@ -0,0 +1,429 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/importers"
+	"github.com/lincentpega/pcrm/internal/mappers"
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// maxUploadMemory is how much of an uploaded file is kept in memory; the rest
+// is buffered on disk. Uploads are capped by server.max_import_bytes.
+const maxUploadMemory = 8 << 20
+
+type ImportAPI struct {
+	service *services.ImportService
+}
+
+func NewImportAPI(service *services.ImportService) *ImportAPI {
+	return &ImportAPI{service: service}
+}
+
+// ImportCSV godoc
+// @Summary Import people from a CSV file
+// @Description Read people from a CSV file whose first row names the columns. The mapping is a JSON dto.CSVMapping
+// @Description that maps columns to firstName, secondName, middleName, company, jobTitle, contact (with a contactType name), birthDate
+// @Description (YYYY-MM-DD or MM-DD), tag (several separated by commas or semicolons) and meetingStory.
+// @Description With dryRun every row is reported with the person it was read as and its validation errors, and
+// @Description nothing is stored. Otherwise all rows are imported in one transaction, and the import is rejected
+// @Description when any row is invalid.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param dryRun query bool false "Preview the rows without storing them"
+// @Param file formData file true "CSV file"
+// @Param mapping formData string true "Column mapping as JSON"
+// @Success 200 {object} dto.ImportRowsResponse "Preview"
+// @Success 201 {object} dto.ImportRowsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 413 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/import/csv [post]
+func (api *ImportAPI) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := validators.ParseBoolParam("dryRun", r.URL.Query().Get("dryRun"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	file, err := formFile(r, "file")
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	var mapping dto.CSVMapping
+	if err := formJSON(r, "mapping", &mapping); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	var result validators.Result
+	result.Merge("mapping", validators.ValidateCSVMapping(&mapping))
+	if err := result.Err(); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	contactTypes, err := api.service.ContactTypeIDs(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	rows, err := importers.ReadCSV(file, mapping, contactTypes)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	api.importRows(w, r, rows, api.service.ImportProfiles, dryRun)
+}
+
+// ImportGoogleContacts godoc
+// @Summary Import people from Google Contacts
+// @Description Read people from the "Google CSV" export of Google Contacts. Emails, phones, websites and addresses
+// @Description become contacts, the organization becomes the company and job title, and labels become tags.
+// @Description Entries sharing an email or website with a stored person, or with an earlier entry, are skipped.
+// @Description With dryRun every row is reported and nothing is stored. Otherwise all rows are imported in one
+// @Description transaction, and the import is rejected when any row is invalid.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param dryRun query bool false "Preview the rows without storing them"
+// @Param file formData file true "Google Contacts CSV export"
+// @Success 200 {object} dto.ImportRowsResponse "Preview"
+// @Success 201 {object} dto.ImportRowsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 413 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/import/google [post]
+func (api *ImportAPI) ImportGoogleContacts(w http.ResponseWriter, r *http.Request) {
+	api.importAddressBook(w, r, importers.ReadGoogleContacts)
+}
+
+// ImportLinkedInConnections godoc
+// @Summary Import people from LinkedIn connections
+// @Description Read people from the Connections.csv file of a LinkedIn data export. The profile URL and email
+// @Description become contacts, the company and position are kept on the person, and "Connected On" becomes the
+// @Description meeting time of the connection source. Connections sharing an email or profile URL with a stored
+// @Description person, or with an earlier row, are skipped. With dryRun every row is reported and nothing is
+// @Description stored. Otherwise all rows are imported in one transaction, and the import is rejected when any row
+// @Description is invalid.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param dryRun query bool false "Preview the rows without storing them"
+// @Param file formData file true "LinkedIn Connections.csv"
+// @Success 200 {object} dto.ImportRowsResponse "Preview"
+// @Success 201 {object} dto.ImportRowsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 413 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/import/linkedin [post]
+func (api *ImportAPI) ImportLinkedInConnections(w http.ResponseWriter, r *http.Request) {
+	api.importAddressBook(w, r, importers.ReadLinkedInConnections)
+}
+
+// ImportTelegramChat godoc
+// @Summary Import a Telegram chat as conversations
+// @Description Read a personal chat from the result.json of a Telegram Desktop export in JSON format and log one
+// @Description "Text Message" conversation with the person for every day with messages. The initiator is whoever
+// @Description sent the first message of the day, and the notes count the messages from each side. From an export
+// @Description of all data the personal chat named by chat is read. Days that already have a text message
+// @Description conversation are skipped. With dryRun the days are reported and nothing is stored.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param personId query int true "Person the chat is with"
+// @Param chat query string false "Name of the chat in an export of all data"
+// @Param timezone query string false "IANA timezone the days are counted in, UTC by default"
+// @Param dryRun query bool false "Preview the days without storing them"
+// @Param file formData file true "Telegram result.json"
+// @Success 200 {object} dto.ChatImportResponse "Preview"
+// @Success 201 {object} dto.ChatImportResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 413 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/import/telegram [post]
+func (api *ImportAPI) ImportTelegramChat(w http.ResponseWriter, r *http.Request) {
+	chatName := r.URL.Query().Get("chat")
+	api.importChat(w, r, func(file io.Reader, loc *time.Location) (*importers.Chat, error) {
+		return importers.ReadTelegramChat(file, chatName, loc)
+	})
+}
+
+// ImportWhatsAppChat godoc
+// @Summary Import a WhatsApp chat as conversations
+// @Description Read a chat with one person from the .txt file of WhatsApp's "Export chat" and log one "Text Message"
+// @Description conversation with the person for every day with messages. The initiator is whoever sent the first
+// @Description message of the day, and the notes count the messages from each side. As the export does not tell
+// @Description whose phone it comes from, owner is the name the user's own messages are signed with. Days that
+// @Description already have a text message conversation are skipped. With dryRun the days are reported and nothing
+// @Description is stored.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param personId query int true "Person the chat is with"
+// @Param owner query string true "Sender name of the user's own messages"
+// @Param timezone query string false "IANA timezone the messages were sent in, UTC by default"
+// @Param dryRun query bool false "Preview the days without storing them"
+// @Param file formData file true "WhatsApp chat .txt export"
+// @Success 200 {object} dto.ChatImportResponse "Preview"
+// @Success 201 {object} dto.ChatImportResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 413 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/import/whatsapp [post]
+func (api *ImportAPI) ImportWhatsAppChat(w http.ResponseWriter, r *http.Request) {
+	owner := r.URL.Query().Get("owner")
+	if strings.TrimSpace(owner) == "" {
+		var result validators.Result
+		result.Add("owner", validators.CodeRequired, "owner is required")
+		problem.WriteError(w, r, result.Err())
+		return
+	}
+	api.importChat(w, r, func(file io.Reader, loc *time.Location) (*importers.Chat, error) {
+		return importers.ReadWhatsAppChat(file, owner, loc)
+	})
+}
+
+// importChat logs a conversation with the person for every day of the chat
+// read from the uploaded file, skipping days already logged.
+func (api *ImportAPI) importChat(w http.ResponseWriter, r *http.Request, read func(io.Reader, *time.Location) (*importers.Chat, error)) {
+	query := r.URL.Query()
+	personID, err := validators.ValidateID("personId", query.Get("personId"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	loc, err := validators.ParseTimezoneParam("timezone", query.Get("timezone"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	dryRun, err := validators.ParseBoolParam("dryRun", query.Get("dryRun"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	file, err := formFile(r, "file")
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	chat, err := read(file, loc)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	summary, err := api.service.ImportConversations(r.Context(), personID, mappers.ChatToDomain(chat), dryRun)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	response := mappers.ChatImportToResponse(chat, summary, dryRun)
+	if dryRun {
+		WriteSuccess(w, response)
+		return
+	}
+	WriteCreated(w, response)
+}
+
+// ImportMbox godoc
+// @Summary Log conversations from an mbox file
+// @Description Read the emails of an mbox file and log each as an "Email" conversation with every person who has
+// @Description its From, To or Cc address as an Email contact. The subject becomes the notes and the date the time
+// @Description of the conversation. The person initiated it when they sent it, and the user otherwise. Emails are
+// @Description logged once per person by their Message-ID, so importing a mailbox again adds only new emails.
+// @Description Emails without a Message-ID, Date or From header are reported as invalid and left out. The response
+// @Description lists the emails that concern someone or are invalid and only counts the others. With dryRun
+// @Description nothing is stored.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param dryRun query bool false "Preview the import without storing it"
+// @Param file formData file true "mbox file"
+// @Success 200 {object} dto.EmailImportResponse "Preview"
+// @Success 201 {object} dto.EmailImportResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 413 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/import/mbox [post]
+func (api *ImportAPI) ImportMbox(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := validators.ParseBoolParam("dryRun", r.URL.Query().Get("dryRun"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	file, err := formFile(r, "file")
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	emails, err := importers.ReadMbox(file)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	imports, err := api.service.ImportEmails(r.Context(), mappers.EmailsToDomain(emails), dryRun)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	response := mappers.EmailImportsToResponse(emails, imports, dryRun)
+	if dryRun {
+		WriteSuccess(w, response)
+		return
+	}
+	WriteCreated(w, response)
+}
+
+// importAddressBook imports the uploaded file with read, skipping people
+// whose email or profile URL is already known.
+func (api *ImportAPI) importAddressBook(w http.ResponseWriter, r *http.Request, read func(io.Reader, map[string]int64) ([]importers.Row, error)) {
+	dryRun, err := validators.ParseBoolParam("dryRun", r.URL.Query().Get("dryRun"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	file, err := formFile(r, "file")
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	contactTypes, err := api.service.ContactTypeIDs(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	rows, err := read(file, contactTypes)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	api.importRows(w, r, rows, api.service.ImportAddressBook, dryRun)
+}
+
+// importRows stores the valid rows with store, or checks all rows on a dry
+// run, and reports every row. Outside a dry run an invalid row rejects the
+// import.
+func (api *ImportAPI) importRows(w http.ResponseWriter, r *http.Request, rows []importers.Row, store func(context.Context, []*models.PersonProfile, bool) (*models.ProfileImport, error), dryRun bool) {
+	var result validators.Result
+	for i := range rows {
+		result.Merge(fmt.Sprintf("rows[%d]", i), rows[i].Errors)
+	}
+	if err := result.Err(); err != nil && !dryRun {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	summary, err := store(r.Context(), mappers.ImportRowsToDomain(rows), dryRun)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	response := mappers.ProfileImportToResponse(rows, summary, dryRun)
+	if dryRun {
+		WriteSuccess(w, response)
+		return
+	}
+	WriteCreated(w, response)
+}
+
+// uploadedFile is a file of a multipart upload. Closing it also removes the
+// temporary files the form spilled to disk beyond maxUploadMemory.
+type uploadedFile struct {
+	multipart.File
+	form *multipart.Form
+}
+
+func (f uploadedFile) Close() error {
+	return errors.Join(f.File.Close(), f.form.RemoveAll())
+}
+
+// formFile returns the named file of a multipart upload. The caller must
+// close it.
+func formFile(r *http.Request, name string) (multipart.File, error) {
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, err
+		}
+		var result validators.Result
+		result.Add("", validators.CodeMalformed, "request body must be multipart/form-data")
+		return nil, result.Err()
+	}
+	file, _, err := r.FormFile(name)
+	if err != nil {
+		r.MultipartForm.RemoveAll()
+		var result validators.Result
+		result.Add(name, validators.CodeRequired, "file is required")
+		return nil, result.Err()
+	}
+	return uploadedFile{File: file, form: r.MultipartForm}, nil
+}
+
+// formJSON decodes the named form value, reporting problems under its name.
+func formJSON(r *http.Request, name string, dst any) error {
+	value := r.FormValue(name)
+	if value == "" {
+		var result validators.Result
+		result.Add(name, validators.CodeRequired, name+" is required")
+		return result.Err()
+	}
+	if err := decodeJSON(strings.NewReader(value), dst); err != nil {
+		var result validators.Result
+		result.Merge(name, err)
+		return result.Err()
+	}
+	return nil
+}
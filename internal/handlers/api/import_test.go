@@ -0,0 +1,219 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/api"
+)
+
+// upload posts file as the multipart "file" field along with the form
+// values.
+func (c *client) upload(path string, file string, values map[string]string) result {
+	c.t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range values {
+		if err := form.WriteField(name, value); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	part, err := form.CreateFormFile("file", "upload")
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	part.Write([]byte(file))
+	if err := form.Close(); err != nil {
+		c.t.Fatal(err)
+	}
+	return c.send(http.MethodPost, path, form.FormDataContentType(), &body)
+}
+
+func (c *client) peopleCount() int {
+	c.t.Helper()
+	var page api.PaginatedResponse[dto.PersonInfoResponse]
+	c.mustDo(http.MethodGet, "/api/people", nil, http.StatusOK, &page)
+	return page.TotalCount
+}
+
+func csvMapping(t *testing.T) map[string]string {
+	t.Helper()
+	mapping, err := json.Marshal(dto.CSVMapping{Columns: []dto.CSVColumn{
+		{Column: "Name", Field: dto.CSVFieldFirstName},
+		{Column: "Mail", Field: dto.CSVFieldContact, ContactType: "Email"},
+		{Column: "Labels", Field: dto.CSVFieldTag},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]string{"mapping": string(mapping)}
+}
+
+func TestImportCSV(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	file := "Name,Mail,Labels\nBob,bob@example.com,friend;work\nCarol,carol@example.com,\n"
+	var preview dto.ImportRowsResponse
+	alice.upload("/api/import/csv?dryRun=true", file, csvMapping(t)).expect(t, http.StatusOK, &preview)
+	if !preview.DryRun || len(preview.Rows) != 2 || preview.Rows[0].Person.FirstName != "Bob" || alice.peopleCount() != 0 {
+		t.Fatalf("preview = %+v", preview)
+	}
+	var imported dto.ImportRowsResponse
+	alice.upload("/api/import/csv", file, csvMapping(t)).expect(t, http.StatusCreated, &imported)
+	if imported.Imported != 2 || imported.Rows[0].PersonID == 0 || alice.peopleCount() != 2 {
+		t.Fatalf("import = %+v", imported)
+	}
+	var tags dto.TagsResponse
+	alice.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d/tags", imported.Rows[0].PersonID), nil, http.StatusOK, &tags)
+	if strings.Join(tags.Tags, ",") != "friend,work" {
+		t.Errorf("tags = %v", tags.Tags)
+	}
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	problem := alice.upload("/api/import/csv", "Name,Mail,Labels\n,bob@example.com,\n", csvMapping(t)).problem(t, http.StatusBadRequest)
+	if len(problem.Errors) == 0 || !strings.HasPrefix(problem.Errors[0].Field, "rows[0]") || alice.peopleCount() != 0 {
+		t.Errorf("problem = %+v, want errors of rows[0] and nothing stored", problem)
+	}
+	problem = alice.upload("/api/import/csv", "Name\nBob\n", nil).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "mapping", "required") {
+		t.Errorf("errors = %+v, want mapping required", problem.Errors)
+	}
+	alice.do(http.MethodPost, "/api/import/csv", dto.CSVMapping{}).problem(t, http.StatusBadRequest)
+}
+
+func TestImportGoogleContactsSkipsKnownPeople(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	file := "First Name,Last Name,E-mail 1 - Value,Labels\nBob,Lee,bob@example.com,Friends ::: * myContacts\n"
+	var first dto.ImportRowsResponse
+	alice.upload("/api/import/google", file, nil).expect(t, http.StatusCreated, &first)
+	if first.Imported != 1 {
+		t.Fatalf("first import = %+v", first)
+	}
+	var second dto.ImportRowsResponse
+	alice.upload("/api/import/google", strings.Replace(file, "bob@example.com", "BOB@example.com", 1), nil).expect(t, http.StatusCreated, &second)
+	if second.Imported != 0 || second.Skipped != 1 || alice.peopleCount() != 1 {
+		t.Errorf("second import = %+v, want the row skipped", second)
+	}
+}
+
+func TestImportLinkedInConnections(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	file := "Notes:\nSome preamble\n\nFirst Name,Last Name,URL,Email Address,Company,Position,Connected On\nBob,Lee,https://www.linkedin.com/in/bob,,Acme,CTO,01 May 2024\n"
+	var imported dto.ImportRowsResponse
+	alice.upload("/api/import/linkedin", file, nil).expect(t, http.StatusCreated, &imported)
+	if imported.Imported != 1 {
+		t.Fatalf("import = %+v", imported)
+	}
+	var person dto.PersonInfoResponse
+	alice.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d", imported.Rows[0].PersonID), nil, http.StatusOK, &person)
+	if person.Company == nil || *person.Company != "Acme" || person.JobTitle == nil || *person.JobTitle != "CTO" {
+		t.Errorf("person = %+v", person)
+	}
+}
+
+func TestImportTelegramChat(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	file := `{"name": "Bob", "type": "personal_chat", "id": 42, "messages": [
+		{"type": "message", "date_unixtime": "1714550400", "from_id": "user42"},
+		{"type": "message", "date_unixtime": "1714550460", "from_id": "user1"},
+		{"type": "service", "date_unixtime": "1714550500", "from_id": "user1"},
+		{"type": "message", "date_unixtime": "1714636800", "from_id": "user1"}
+	]}`
+	path := fmt.Sprintf("/api/import/telegram?personId=%d", personID)
+	var imported dto.ChatImportResponse
+	alice.upload(path, file, nil).expect(t, http.StatusCreated, &imported)
+	if imported.PersonName != "Bob" || imported.Messages != 3 || imported.Imported != 2 || len(imported.Days) != 2 {
+		t.Fatalf("import = %+v", imported)
+	}
+	if day := imported.Days[0]; day.Initiator != "person" || day.Notes != "Telegram chat, 2 messages: 1 from me, 1 from Bob" || day.ConversationID == 0 {
+		t.Errorf("first day = %+v", day)
+	}
+	if day := imported.Days[1]; day.Initiator != "owner" || day.OwnerMessages != 1 || day.PersonMessages != 0 {
+		t.Errorf("second day = %+v", day)
+	}
+	var again dto.ChatImportResponse
+	alice.upload(path, file, nil).expect(t, http.StatusCreated, &again)
+	if again.Imported != 0 || again.Skipped != 2 {
+		t.Errorf("second import = %+v, want every day skipped", again)
+	}
+	var conversations []dto.ConversationResponse
+	alice.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d/conversations", personID), nil, http.StatusOK, &conversations)
+	if len(conversations) != 2 {
+		t.Errorf("conversations = %+v", conversations)
+	}
+	alice.upload("/api/import/telegram?personId=999", file, nil).problem(t, http.StatusNotFound)
+	alice.upload(path, "not json", nil).problem(t, http.StatusBadRequest)
+}
+
+func TestImportWhatsAppChat(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	file := "01/05/2024, 09:00 - Alice: Hi Bob\n01/05/2024, 09:05 - Bob: Hi!\n"
+	var preview dto.ChatImportResponse
+	alice.upload(fmt.Sprintf("/api/import/whatsapp?personId=%d&owner=Alice&dryRun=true&timezone=Europe/Berlin", personID), file, nil).expect(t, http.StatusOK, &preview)
+	if !preview.DryRun || preview.PersonName != "Bob" || len(preview.Days) != 1 || preview.Days[0].Initiator != "owner" {
+		t.Fatalf("preview = %+v", preview)
+	}
+	problem := alice.upload(fmt.Sprintf("/api/import/whatsapp?personId=%d", personID), file, nil).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "owner", "required") {
+		t.Errorf("errors = %+v, want owner required", problem.Errors)
+	}
+	problem = alice.upload(fmt.Sprintf("/api/import/whatsapp?personId=%d&owner=Alice&timezone=Mars/Base", personID), file, nil).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "timezone", "invalid") {
+		t.Errorf("errors = %+v, want timezone invalid", problem.Errors)
+	}
+}
+
+func TestImportMbox(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	emailType := alice.typeID("/api/contact-types", "Email")
+	alice.mustDo(http.MethodPost, fmt.Sprintf("/api/people/%d/contacts", personID), dto.ContactRequest{ContactTypeID: emailType, Content: "Bob@Example.com"}, http.StatusCreated, nil)
+	mbox := "From bob@example.com Wed May  1 09:00:00 2024\n" +
+		"Message-ID: <1@example.com>\nDate: Wed, 01 May 2024 09:00:00 +0000\nFrom: Bob <bob@example.com>\nTo: alice@example.org\nSubject: Lunch?\n\nBody\n\n" +
+		"From alice@example.org Wed May  1 10:00:00 2024\n" +
+		"Message-ID: <2@example.org>\nDate: Wed, 01 May 2024 10:00:00 +0000\nFrom: alice@example.org\nTo: carol@example.com\nSubject: Hello\n\nBody\n\n" +
+		"From nobody Wed May  1 11:00:00 2024\nSubject: No headers\n\nBody\n"
+	var imported dto.EmailImportResponse
+	alice.upload("/api/import/mbox", mbox, nil).expect(t, http.StatusCreated, &imported)
+	if imported.Emails != 3 || imported.Imported != 1 || imported.Conversations != 1 || imported.Unmatched != 1 || imported.Invalid != 1 {
+		t.Fatalf("import = %+v", imported)
+	}
+	var again dto.EmailImportResponse
+	alice.upload("/api/import/mbox", mbox, nil).expect(t, http.StatusCreated, &again)
+	if again.Conversations != 0 || again.Duplicates != 1 {
+		t.Errorf("second import = %+v, want the email reported as duplicate", again)
+	}
+	var conversations []dto.ConversationResponse
+	alice.mustDo(http.MethodGet, fmt.Sprintf("/api/people/%d/conversations", personID), nil, http.StatusOK, &conversations)
+	if len(conversations) != 1 || conversations[0].Notes != "Lunch?" || conversations[0].Initiator != "person" {
+		t.Errorf("conversations = %+v", conversations)
+	}
+	alice.upload("/api/import/mbox", "not an mbox", nil).problem(t, http.StatusBadRequest)
+}
+
+func TestImportRemovesSpilledUploads(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+	alice := newTestServer(t).signUp(t, "alice")
+	file := "Name\n" + strings.Repeat("x", 9<<20) + "\n"
+	if res := alice.upload("/api/import/csv?dryRun=true", file, csvMapping(t)); res.status == http.StatusInternalServerError {
+		t.Fatalf("status = %d; body: %s", res.status, res.body)
+	}
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("temporary file %s was left behind", entry.Name())
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+const (
+	oidcCookieName = "pcrm_oidc"
+	oidcCookiePath = "/api/auth/oidc/"
+	// oidcLoginTTL bounds how long a user may take at the provider.
+	oidcLoginTTL = 10 * time.Minute
+)
+
+type OIDCAPI struct {
+	service           *services.OIDCService
+	secureCookies     bool
+	postLoginRedirect string
+}
+
+func NewOIDCAPI(service *services.OIDCService, secureCookies bool, postLoginRedirect string) *OIDCAPI {
+	return &OIDCAPI{service: service, secureCookies: secureCookies, postLoginRedirect: postLoginRedirect}
+}
+
+// Login godoc
+// @Summary Log in with single sign-on
+// @Description Redirect the browser to the OpenID Connect provider, remembering the login state in a short-lived cookie
+// @Tags auth
+// @Success 302
+// @Failure 500 {object} problem.Details
+// @Router /api/auth/oidc/login [get]
+func (api *OIDCAPI) Login(w http.ResponseWriter, r *http.Request) {
+	authURL, login, err := api.service.Begin(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	http.SetCookie(w, api.loginCookie(strings.Join([]string{login.State, login.Nonce, login.Verifier}, "."), int(oidcLoginTTL.Seconds())))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback godoc
+// @Summary Single sign-on callback
+// @Description Verify the provider's answer, start a session for the linked user and redirect to the application
+// @Tags auth
+// @Param code query string true "Authorization code"
+// @Param state query string true "Login state"
+// @Success 302
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 409 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Router /api/auth/oidc/callback [get]
+func (api *OIDCAPI) Callback(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, api.loginCookie("", -1))
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		problem.WriteError(w, r, &services.AuthenticationError{Message: "the provider refused the login: " + providerErr})
+		return
+	}
+	var login services.OIDCLogin
+	if cookie, err := r.Cookie(oidcCookieName); err == nil {
+		parts := strings.Split(cookie.Value, ".")
+		if len(parts) == 3 {
+			login = services.OIDCLogin{State: parts[0], Nonce: parts[1], Verifier: parts[2]}
+		}
+	}
+	session, secret, err := api.service.Complete(r.Context(), login, query.Get("state"), query.Get("code"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	http.SetCookie(w, sessionCookie(secret, session.ExpiresAt, api.secureCookies))
+	http.Redirect(w, r, api.postLoginRedirect, http.StatusFound)
+}
+
+func (api *OIDCAPI) loginCookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    value,
+		Path:     oidcCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   api.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
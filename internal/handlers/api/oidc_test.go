@@ -0,0 +1,370 @@
+package api_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/api"
+	"github.com/lincentpega/pcrm/internal/middleware"
+	"github.com/lincentpega/pcrm/internal/repository/memory"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+const oidcClientID = "pcrm"
+
+// oidcProvider is an OpenID Connect provider serving discovery, its signing
+// key and the token endpoint. Tests stand in for the browser at the
+// authorization endpoint by calling authorize.
+type oidcProvider struct {
+	*httptest.Server
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]oidcAuthorization
+}
+
+// oidcAuthorization is what the provider remembers of an authorization
+// request until its code is redeemed.
+type oidcAuthorization struct {
+	challenge string
+	nonce     string
+	claims    map[string]any
+}
+
+func newOIDCProvider(t *testing.T) *oidcProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &oidcProvider{key: key, codes: map[string]oidcAuthorization{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", provider.discovery)
+	mux.HandleFunc("GET /keys", provider.keys)
+	mux.HandleFunc("POST /token", provider.token)
+	provider.Server = httptest.NewServer(mux)
+	t.Cleanup(provider.Close)
+	return provider
+}
+
+func (p *oidcProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                p.URL,
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *oidcProvider) keys(w http.ResponseWriter, r *http.Request) {
+	encoding := base64.RawURLEncoding
+	json.NewEncoder(w).Encode(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   encoding.EncodeToString(p.key.N.Bytes()),
+			"e":   encoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+// token redeems a code for an ID token when the PKCE verifier matches the
+// challenge of the authorization request.
+func (p *oidcProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.mu.Lock()
+	authorization, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(challenge[:]) != authorization.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+	claims := map[string]any{
+		"iss":   p.URL,
+		"aud":   oidcClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+	idToken, err := p.sign(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (p *oidcProvider) sign(claims map[string]any) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": "test"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// authorize answers the authorization request the login redirected to as if
+// the user signed in with the claims, and returns the state and code the
+// provider sends back to the callback.
+func (p *oidcProvider) authorize(t *testing.T, location string, claims map[string]any) (string, string) {
+	t.Helper()
+	authURL, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := authURL.Query()
+	if !strings.HasPrefix(location, p.URL+"/authorize?") || query.Get("client_id") != oidcClientID || query.Get("response_type") != "code" {
+		t.Fatalf("login redirected to %s", location)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" || query.Get("nonce") == "" {
+		t.Fatalf("authorization request %s has no PKCE challenge or nonce", location)
+	}
+	code := rand.Text()
+	p.mu.Lock()
+	p.codes[code] = oidcAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce"), claims: claims}
+	p.mu.Unlock()
+	return query.Get("state"), code
+}
+
+// newOIDCTestServer serves the API like newTestServer with single sign-on
+// through the provider.
+func newOIDCTestServer(t *testing.T, provider *oidcProvider, createUsers bool) *testServer {
+	t.Helper()
+	store := memory.NewStore()
+	users := services.NewUserService(store, time.Hour)
+	oidc := services.NewOIDCService(config.OIDCConfig{
+		Enabled:           true,
+		Issuer:            provider.URL,
+		ClientID:          oidcClientID,
+		ClientSecret:      "secret",
+		RedirectURL:       "http://pcrm.test/api/auth/oidc/callback",
+		Scopes:            []string{"openid", "profile"},
+		UsernameClaim:     "preferred_username",
+		CreateUsers:       createUsers,
+		PostLoginRedirect: "/",
+	}, store, users)
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux, store)
+	api.RegisterAuthRoutes(mux, users, false, false)
+	api.RegisterOIDCRoutes(mux, oidc, false, "/")
+	server := httptest.NewServer(middleware.AuthMiddleware(services.NewTokenService(store), users)(mux))
+	t.Cleanup(server.Close)
+	return &testServer{Server: server, store: store, users: users}
+}
+
+// browser returns an anonymous client that does not follow redirects.
+func (s *testServer) browser(t *testing.T) *client {
+	t.Helper()
+	c := s.anonymous(t)
+	c.http.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return c
+}
+
+// beginOIDCLogin starts a login and returns where it redirected to.
+func (c *client) beginOIDCLogin() string {
+	c.t.Helper()
+	res := c.do(http.MethodGet, "/api/auth/oidc/login", nil)
+	if res.status != http.StatusFound {
+		c.t.Fatalf("status = %d, want 302; body: %s", res.status, res.body)
+	}
+	return res.header.Get("Location")
+}
+
+// callback returns to the server from the provider.
+func (c *client) callback(state, code string) result {
+	c.t.Helper()
+	return c.do(http.MethodGet, "/api/auth/oidc/callback?"+url.Values{"state": {state}, "code": {code}}.Encode(), nil)
+}
+
+// expectLoginCleared checks that the response deletes the login cookie.
+func (res result) expectLoginCleared(t *testing.T) {
+	t.Helper()
+	for _, cookie := range (&http.Response{Header: res.header}).Cookies() {
+		if cookie.Name == "pcrm_oidc" {
+			if cookie.MaxAge >= 0 {
+				t.Errorf("login cookie is set with Max-Age %d, want it deleted", cookie.MaxAge)
+			}
+			return
+		}
+	}
+	t.Error("response keeps the login cookie")
+}
+
+// oidcLogin signs in at the provider with the claims and returns the
+// callback's response.
+func (c *client) oidcLogin(provider *oidcProvider, claims map[string]any) result {
+	c.t.Helper()
+	state, code := provider.authorize(c.t, c.beginOIDCLogin(), claims)
+	return c.callback(state, code)
+}
+
+// replaceLogin rewrites the login cookie kept by the browser.
+func (c *client) replaceLogin(rewrite func(parts []string)) {
+	c.t.Helper()
+	callbackURL, err := url.Parse(c.baseURL + "/api/auth/oidc/callback")
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	for _, cookie := range c.http.Jar.Cookies(callbackURL) {
+		if cookie.Name != "pcrm_oidc" {
+			continue
+		}
+		parts := strings.Split(cookie.Value, ".")
+		rewrite(parts)
+		c.http.Jar.SetCookies(callbackURL, []*http.Cookie{{Name: cookie.Name, Value: strings.Join(parts, "."), Path: "/api/auth/oidc/"}})
+		return
+	}
+	c.t.Fatal("browser has no login cookie")
+}
+
+func (c *client) me() dto.UserResponse {
+	c.t.Helper()
+	var user dto.UserResponse
+	c.mustDo(http.MethodGet, "/api/auth/me", nil, http.StatusOK, &user)
+	return user
+}
+
+func TestOIDCLoginCreatesAndReusesUser(t *testing.T) {
+	provider := newOIDCProvider(t)
+	server := newOIDCTestServer(t, provider, true)
+	claims := map[string]any{"sub": "subject-1", "preferred_username": "alice"}
+	first := server.browser(t)
+	res := first.oidcLogin(provider, claims)
+	if res.status != http.StatusFound || res.header.Get("Location") != "/" {
+		t.Fatalf("callback answered %d to %q; body: %s", res.status, res.header.Get("Location"), res.body)
+	}
+	res.expectLoginCleared(t)
+	alice := first.me()
+	if alice.Username != "alice" {
+		t.Errorf("signed in as %+v, want alice", alice)
+	}
+	second := server.browser(t)
+	second.oidcLogin(provider, map[string]any{"sub": "subject-1", "preferred_username": "renamed"})
+	if user := second.me(); user.ID != alice.ID {
+		t.Errorf("second login signed in as %+v, want user %d", user, alice.ID)
+	}
+}
+
+func TestOIDCLoginWithoutLinkedUser(t *testing.T) {
+	provider := newOIDCProvider(t)
+	server := newOIDCTestServer(t, provider, false)
+	c := server.browser(t)
+	c.oidcLogin(provider, map[string]any{"sub": "subject-1", "preferred_username": "alice"}).problem(t, http.StatusForbidden)
+	c.do(http.MethodGet, "/api/auth/me", nil).problem(t, http.StatusUnauthorized)
+}
+
+func TestOIDCCallbackRejectsMismatchedLogin(t *testing.T) {
+	provider := newOIDCProvider(t)
+	server := newOIDCTestServer(t, provider, true)
+	claims := map[string]any{"sub": "subject-1", "preferred_username": "alice"}
+	tests := []struct {
+		name   string
+		tamper func(c *client, state string) string
+		detail string
+	}{
+		{
+			name:   "state",
+			tamper: func(c *client, state string) string { return rand.Text() },
+			detail: "login state does not match",
+		},
+		{
+			name: "missing login cookie",
+			tamper: func(c *client, state string) string {
+				c.replaceLogin(func(parts []string) { parts[0] = "" })
+				return state
+			},
+			detail: "login state does not match",
+		},
+		{
+			name: "nonce",
+			tamper: func(c *client, state string) string {
+				c.replaceLogin(func(parts []string) { parts[1] = rand.Text() })
+				return state
+			},
+			detail: "nonce does not match",
+		},
+		{
+			name: "PKCE verifier",
+			tamper: func(c *client, state string) string {
+				c.replaceLogin(func(parts []string) { parts[2] = rand.Text() })
+				return state
+			},
+			detail: "rejected the authorization code",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := server.browser(t)
+			state, code := provider.authorize(t, c.beginOIDCLogin(), claims)
+			res := c.callback(tt.tamper(c, state), code)
+			res.expectLoginCleared(t)
+			problem := res.problem(t, http.StatusUnauthorized)
+			if !strings.Contains(problem.Detail, tt.detail) {
+				t.Errorf("detail = %q, want it to say %q", problem.Detail, tt.detail)
+			}
+			c.do(http.MethodGet, "/api/auth/me", nil).problem(t, http.StatusUnauthorized)
+		})
+	}
+	if _, err := server.users.GetUserByUsername(t.Context(), "alice"); err == nil {
+		t.Error("a rejected login created a user")
+	}
+}
+
+func TestOIDCLoginUsernameCollision(t *testing.T) {
+	provider := newOIDCProvider(t)
+	server := newOIDCTestServer(t, provider, true)
+	existing := server.signUp(t, "alice").me()
+	c := server.browser(t)
+	c.oidcLogin(provider, map[string]any{"sub": "subject-1", "preferred_username": "alice"}).problem(t, http.StatusConflict)
+	c.do(http.MethodGet, "/api/auth/me", nil).problem(t, http.StatusUnauthorized)
+	if _, err := server.users.GetUserByUsername(t.Context(), "alice"); err != nil {
+		t.Fatal(err)
+	}
+	c.oidcLogin(provider, map[string]any{"sub": "subject-1", "preferred_username": "alice2"}).expect(t, http.StatusFound, nil)
+	if user := c.me(); user.ID == existing.ID || user.Username != "alice2" {
+		t.Errorf("signed in as %+v, want a new user alice2", user)
+	}
+}
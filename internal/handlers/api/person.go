@@ -1,24 +1,22 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
 	"github.com/lincentpega/pcrm/internal/mappers"
-	"github.com/lincentpega/pcrm/internal/repository"
+	"github.com/lincentpega/pcrm/internal/services"
 	"github.com/lincentpega/pcrm/internal/validators"
 )
 
 type PersonAPI struct {
-	repo        *repository.PersonRepository
-	contactRepo *repository.ContactRepository
+	service *services.PersonService
 }
 
-func NewPersonAPI(repo *repository.PersonRepository, contactRepo *repository.ContactRepository) *PersonAPI {
+func NewPersonAPI(service *services.PersonService) *PersonAPI {
 	return &PersonAPI{
-		repo:        repo,
-		contactRepo: contactRepo,
+		service: service,
 	}
 }
 
@@ -31,7 +29,9 @@ func NewPersonAPI(repo *repository.PersonRepository, contactRepo *repository.Con
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} PaginatedResponse[dto.PersonInfoResponse]
-// @Failure 500 {object} ErrorResponse
+// @Failure 401 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people [get]
 func (api *PersonAPI) ListPeople(w http.ResponseWriter, r *http.Request) {
 	page, limit := validators.ParsePaginationParams(
@@ -39,15 +39,9 @@ func (api *PersonAPI) ListPeople(w http.ResponseWriter, r *http.Request) {
 		r.URL.Query().Get("limit"),
 	)
 
-	people, err := api.repo.GetPaginated(page, limit)
+	people, totalCount, err := api.service.ListPeople(r.Context(), page, limit)
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch people")
-		return
-	}
-
-	totalCount, err := api.repo.GetTotalCount()
-	if err != nil {
-		WriteInternalError(w, "Failed to get total count")
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -69,23 +63,22 @@ func (api *PersonAPI) ListPeople(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Person ID"
 // @Success 200 {object} dto.PersonInfoResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{id} [get]
 func (api *PersonAPI) GetPerson(w http.ResponseWriter, r *http.Request) {
-	id, err := validators.ValidatePersonID(r.PathValue("id"))
+	id, err := PathID(r, "id")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	person, err := api.repo.GetByID(id)
+	person, err := api.service.GetPerson(r.Context(), id)
 	if err != nil {
-		WriteInternalError(w, "Failed to fetch person")
-		return
-	}
-	if person == nil {
-		WriteNotFound(w, "Person not found")
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -93,37 +86,41 @@ func (api *PersonAPI) GetPerson(w http.ResponseWriter, r *http.Request) {
 	WriteSuccess(w, response)
 }
 
-
 // CreatePerson godoc
 // @Summary Create a new person
-// @Description Create a new person in the CRM system
+// @Description Create a new person in the CRM system, optionally together with contacts, connection source,
+// @Description birth date info and an initial conversation. Everything is stored atomically.
 // @Tags people
 // @Accept json
 // @Produce json
-// @Param person body dto.PersonUpsertRequest true "Person data"
-// @Success 201 {object} dto.PersonInfoResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Param person body dto.PersonCreateRequest true "Person data with optional nested sub-resources"
+// @Success 201 {object} dto.PersonProfileResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 422 {object} problem.Details "Unknown contact type, conversation type or introducer"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people [post]
 func (api *PersonAPI) CreatePerson(w http.ResponseWriter, r *http.Request) {
-	var req dto.PersonUpsertRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteBadRequest(w, "Invalid JSON format")
+	var req dto.PersonCreateRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if err := validators.ValidatePersonUpsertRequest(&req); err != nil {
-		WriteBadRequest(w, err.Error())
+	if err := validators.ValidatePersonCreateRequest(&req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	person := mappers.PersonUpsertRequestToDomain(&req)
-	if err := api.repo.Create(person); err != nil {
-		WriteInternalError(w, "Failed to create person")
+	profile := mappers.PersonCreateRequestToDomain(&req)
+	if err := api.service.CreatePersonProfile(r.Context(), profile); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	response := mappers.PersonDomainToResponse(person)
+	response := mappers.PersonProfileDomainToResponse(profile)
 	WriteCreated(w, response)
 }
 
@@ -136,51 +133,43 @@ func (api *PersonAPI) CreatePerson(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Person ID"
 // @Param person body dto.PersonUpsertRequest true "Updated person data"
 // @Success 200 {object} dto.PersonInfoResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{id} [put]
 func (api *PersonAPI) UpdatePerson(w http.ResponseWriter, r *http.Request) {
-	id, err := validators.ValidatePersonID(r.PathValue("id"))
+	id, err := PathID(r, "id")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	var req dto.PersonUpsertRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteBadRequest(w, "Invalid JSON format")
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	if err := validators.ValidatePersonUpsertRequest(&req); err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
 	person := mappers.PersonUpsertRequestToDomain(&req)
 	person.ID = id
 
-	if err := api.repo.Update(person); err != nil {
-		WriteInternalError(w, "Failed to update person")
+	if err := api.service.UpdatePerson(r.Context(), person); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	updatedPerson, err := api.repo.GetByID(id)
-	if err != nil {
-		WriteInternalError(w, "Failed to fetch updated person")
-		return
-	}
-	if updatedPerson == nil {
-		WriteNotFound(w, "Person not found")
-		return
-	}
-
-	response := mappers.PersonDomainToResponse(updatedPerson)
+	response := mappers.PersonDomainToResponse(person)
 	WriteSuccess(w, response)
 }
 
-
 // DeletePerson godoc
 // @Summary Delete a person
 // @Description Delete a person from the CRM system
@@ -189,18 +178,22 @@ func (api *PersonAPI) UpdatePerson(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Person ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
 // @Router /api/people/{id} [delete]
 func (api *PersonAPI) DeletePerson(w http.ResponseWriter, r *http.Request) {
-	id, err := validators.ValidatePersonID(r.PathValue("id"))
+	id, err := PathID(r, "id")
 	if err != nil {
-		WriteBadRequest(w, err.Error())
+		problem.WriteError(w, r, err)
 		return
 	}
 
-	if err := api.repo.Delete(id); err != nil {
-		WriteInternalError(w, "Failed to delete person")
+	if err := api.service.DeletePerson(r.Context(), id); err != nil {
+		problem.WriteError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,122 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/api"
+)
+
+func TestPersonCRUD(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	company := "Acme"
+	var created dto.PersonProfileResponse
+	alice.mustDo(http.MethodPost, "/api/people", dto.PersonCreateRequest{
+		PersonUpsertRequest: dto.PersonUpsertRequest{FirstName: "Bob", Company: &company},
+	}, http.StatusCreated, &created)
+	if created.ID == 0 || created.FirstName != "Bob" || created.Company == nil || *created.Company != "Acme" {
+		t.Fatalf("created person = %+v", created.PersonInfoResponse)
+	}
+	path := fmt.Sprintf("/api/people/%d", created.ID)
+	var got dto.PersonInfoResponse
+	alice.mustDo(http.MethodGet, path, nil, http.StatusOK, &got)
+	if got.FirstName != "Bob" {
+		t.Errorf("firstName = %q, want Bob", got.FirstName)
+	}
+	jobTitle := "CTO"
+	var updated dto.PersonInfoResponse
+	alice.mustDo(http.MethodPut, path, dto.PersonUpsertRequest{FirstName: "Robert", JobTitle: &jobTitle}, http.StatusOK, &updated)
+	if updated.FirstName != "Robert" || updated.JobTitle == nil || *updated.JobTitle != "CTO" || updated.Company != nil {
+		t.Errorf("updated person = %+v", updated)
+	}
+	alice.mustDo(http.MethodDelete, path, nil, http.StatusNoContent, nil)
+	alice.do(http.MethodGet, path, nil).problem(t, http.StatusNotFound)
+	alice.do(http.MethodDelete, path, nil).problem(t, http.StatusNotFound)
+}
+
+func TestCreatePersonWithSubResources(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	emailType := alice.typeID("/api/contact-types", "Email")
+	callType := alice.typeID("/api/conversation-types", "Phone Call")
+	month, day := 3, 7
+	var created dto.PersonProfileResponse
+	alice.mustDo(http.MethodPost, "/api/people", dto.PersonCreateRequest{
+		PersonUpsertRequest: dto.PersonUpsertRequest{FirstName: "Bob"},
+		Contacts:            []dto.ContactRequest{{ContactTypeID: emailType, Content: "bob@example.com"}},
+		BirthDateInfo:       &dto.BirthDateInfoRequest{BirthMonth: &month, BirthDay: &day},
+		Conversation:        &dto.ConversationRequest{ConversationTypeID: callType, Initiator: "owner", Notes: "First call"},
+		Tags:                []string{"friend"},
+	}, http.StatusCreated, &created)
+	if len(created.Contacts) != 1 || created.Contacts[0].ContactType.Name != "Email" {
+		t.Errorf("contacts = %+v", created.Contacts)
+	}
+	if created.BirthDateInfo == nil || *created.BirthDateInfo.BirthDay != 7 {
+		t.Errorf("birthDateInfo = %+v", created.BirthDateInfo)
+	}
+	if len(created.Conversations) != 1 || created.Conversations[0].Notes != "First call" {
+		t.Errorf("conversations = %+v", created.Conversations)
+	}
+	if len(created.Tags) != 1 || created.Tags[0] != "friend" {
+		t.Errorf("tags = %v", created.Tags)
+	}
+}
+
+func TestCreatePersonRollsBackOnInvalidReference(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	res := alice.do(http.MethodPost, "/api/people", dto.PersonCreateRequest{
+		PersonUpsertRequest: dto.PersonUpsertRequest{FirstName: "Bob"},
+		Contacts:            []dto.ContactRequest{{ContactTypeID: 999, Content: "bob@example.com"}},
+	})
+	if res.status < 400 || res.status >= 500 {
+		t.Fatalf("status = %d, want a client error; body: %s", res.status, res.body)
+	}
+	var page api.PaginatedResponse[dto.PersonInfoResponse]
+	alice.mustDo(http.MethodGet, "/api/people", nil, http.StatusOK, &page)
+	if page.TotalCount != 0 {
+		t.Errorf("totalCount = %d after a failed create, want 0", page.TotalCount)
+	}
+}
+
+func TestCreatePersonValidation(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	problem := alice.do(http.MethodPost, "/api/people", dto.PersonCreateRequest{}).problem(t, http.StatusBadRequest)
+	if !hasFieldProblem(problem, "firstName", "required") {
+		t.Errorf("errors = %+v, want firstName required", problem.Errors)
+	}
+	alice.send(http.MethodPost, "/api/people", "application/json", nil).problem(t, http.StatusBadRequest)
+}
+
+func TestListPeoplePagination(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	for i := range 5 {
+		alice.createPerson(fmt.Sprintf("Person %d", i), "")
+	}
+	var page api.PaginatedResponse[dto.PersonInfoResponse]
+	alice.mustDo(http.MethodGet, "/api/people?page=2&limit=2", nil, http.StatusOK, &page)
+	if len(page.Data) != 2 || page.TotalCount != 5 || page.TotalPages != 3 || !page.HasNext || !page.HasPrev {
+		t.Errorf("page = %+v", page)
+	}
+	alice.mustDo(http.MethodGet, "/api/people?page=x&limit=500", nil, http.StatusOK, &page)
+	if page.CurrentPage != 1 || len(page.Data) != 5 {
+		t.Errorf("invalid paging parameters gave page %d with %d people, want the defaults", page.CurrentPage, len(page.Data))
+	}
+}
+
+func TestPeopleAreIsolatedBetweenUsers(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	mallory := server.signUp(t, "mallory")
+	id := alice.createPerson("Bob", "")
+	path := fmt.Sprintf("/api/people/%d", id)
+	mallory.do(http.MethodGet, path, nil).problem(t, http.StatusNotFound)
+	mallory.do(http.MethodPut, path, dto.PersonUpsertRequest{FirstName: "Eve"}).problem(t, http.StatusNotFound)
+	mallory.do(http.MethodDelete, path, nil).problem(t, http.StatusNotFound)
+	var page api.PaginatedResponse[dto.PersonInfoResponse]
+	mallory.mustDo(http.MethodGet, "/api/people", nil, http.StatusOK, &page)
+	if page.TotalCount != 0 {
+		t.Errorf("mallory sees %d people, want 0", page.TotalCount)
+	}
+	alice.mustDo(http.MethodGet, path, nil, http.StatusOK, nil)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// DecodeJSON decodes the request body into dst, rejecting unknown fields and
+// reporting decoding problems as validation errors. A body over the size
+// limit is reported as *http.MaxBytesError.
+func DecodeJSON(r *http.Request, dst any) error {
+	return decodeJSON(r.Body, dst)
+}
+
+func decodeJSON(body io.Reader, dst any) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return decodeErrorToValidation(err)
+	}
+	return nil
+}
+
+func PathID(r *http.Request, name string) (int64, error) {
+	return validators.ValidateID(name, r.PathValue(name))
+}
+
+func decodeErrorToValidation(err error) error {
+	var result validators.Result
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return err
+	case errors.As(err, &typeErr):
+		result.Add(typeErr.Field, validators.CodeInvalidType, fmt.Sprintf("must be of type %s", typeErr.Type))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		result.Add(field, validators.CodeUnknownField, "is not a recognised field")
+	default:
+		result.Add("", validators.CodeMalformed, "request body must be valid JSON matching the expected schema")
+	}
+	return result.Err()
+}
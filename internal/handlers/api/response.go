@@ -14,10 +14,6 @@ type PaginatedResponse[T any] struct {
 	HasPrev     bool `json:"hasPrev"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -42,23 +38,4 @@ func WritePaginated[T any](w http.ResponseWriter, data []T, currentPage, totalPa
 		HasPrev:     currentPage > 1,
 	}
 	WriteJSON(w, http.StatusOK, response)
-}
-
-func WriteError(w http.ResponseWriter, status int, err string) {
-	response := ErrorResponse{
-		Error: err,
-	}
-	WriteJSON(w, status, response)
-}
-
-func WriteBadRequest(w http.ResponseWriter, err string) {
-	WriteError(w, http.StatusBadRequest, err)
-}
-
-func WriteNotFound(w http.ResponseWriter, err string) {
-	WriteError(w, http.StatusNotFound, err)
-}
-
-func WriteInternalError(w http.ResponseWriter, err string) {
-	WriteError(w, http.StatusInternalServerError, err)
 }
\ No newline at end of file
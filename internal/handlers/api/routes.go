@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+// RegisterRoutes mounts the REST API on mux, backed by services built on uow.
+func RegisterRoutes(mux *http.ServeMux, uow services.UnitOfWork) {
+	personAPI := NewPersonAPI(services.NewPersonService(uow))
+	contactAPI := NewContactAPI(services.NewContactService(uow))
+	connectionSourceAPI := NewConnectionSourceAPI(services.NewConnectionSourceService(uow))
+	birthDateInfoAPI := NewBirthDateInfoAPI(services.NewBirthDateInfoService(uow))
+	conversationAPI := NewConversationAPI(services.NewConversationService(uow))
+	tagAPI := NewTagAPI(services.NewTagService(uow))
+	apiTokenAPI := NewAPITokenAPI(services.NewTokenService(uow))
+	archiveAPI := NewArchiveAPI(services.NewArchiveService(uow))
+	importAPI := NewImportAPI(services.NewImportService(uow))
+	dossierAPI := NewDossierAPI(services.NewDossierService(uow))
+	mux.HandleFunc("GET /api/people", personAPI.ListPeople)
+	mux.HandleFunc("POST /api/people", personAPI.CreatePerson)
+	mux.HandleFunc("GET /api/people/{id}", personAPI.GetPerson)
+	mux.HandleFunc("PUT /api/people/{id}", personAPI.UpdatePerson)
+	mux.HandleFunc("DELETE /api/people/{id}", personAPI.DeletePerson)
+	mux.HandleFunc("GET /api/people/{id}/export.md", dossierAPI.ExportDossier)
+	mux.HandleFunc("GET /api/people/{personId}/contacts", contactAPI.ListContactsByPerson)
+	mux.HandleFunc("POST /api/people/{personId}/contacts", contactAPI.CreateContact)
+	mux.HandleFunc("GET /api/contacts/{id}", contactAPI.GetContact)
+	mux.HandleFunc("PUT /api/contacts/{id}", contactAPI.UpdateContact)
+	mux.HandleFunc("DELETE /api/contacts/{id}", contactAPI.DeleteContact)
+	mux.HandleFunc("GET /api/contact-types", contactAPI.ListContactTypes)
+	mux.HandleFunc("GET /api/people/{personId}/conversations", conversationAPI.ListConversationsByPerson)
+	mux.HandleFunc("POST /api/people/{personId}/conversations", conversationAPI.CreateConversation)
+	mux.HandleFunc("GET /api/conversations/{id}", conversationAPI.GetConversation)
+	mux.HandleFunc("PUT /api/conversations/{id}", conversationAPI.UpdateConversation)
+	mux.HandleFunc("DELETE /api/conversations/{id}", conversationAPI.DeleteConversation)
+	mux.HandleFunc("GET /api/conversation-types", conversationAPI.ListConversationTypes)
+	mux.HandleFunc("GET /api/people/{personId}/connection-source", connectionSourceAPI.GetConnectionSource)
+	mux.HandleFunc("PUT /api/people/{personId}/connection-source", connectionSourceAPI.UpsertConnectionSource)
+	mux.HandleFunc("DELETE /api/people/{personId}/connection-source", connectionSourceAPI.DeleteConnectionSource)
+	mux.HandleFunc("GET /api/people/{personId}/birth-date-info", birthDateInfoAPI.GetBirthDateInfo)
+	mux.HandleFunc("PUT /api/people/{personId}/birth-date-info", birthDateInfoAPI.UpsertBirthDateInfo)
+	mux.HandleFunc("DELETE /api/people/{personId}/birth-date-info", birthDateInfoAPI.DeleteBirthDateInfo)
+	mux.HandleFunc("GET /api/people/{personId}/tags", tagAPI.GetTags)
+	mux.HandleFunc("PUT /api/people/{personId}/tags", tagAPI.ReplaceTags)
+	mux.HandleFunc("GET /api/tokens", apiTokenAPI.ListTokens)
+	mux.HandleFunc("POST /api/tokens", apiTokenAPI.CreateToken)
+	mux.HandleFunc("DELETE /api/tokens/{id}", apiTokenAPI.DeleteToken)
+	mux.HandleFunc("GET /api/export", archiveAPI.Export)
+	mux.HandleFunc("GET /api/export/markdown", dossierAPI.ExportDossiers)
+	mux.HandleFunc("POST /api/import", archiveAPI.Import)
+	mux.HandleFunc("POST /api/import/csv", importAPI.ImportCSV)
+	mux.HandleFunc("POST /api/import/google", importAPI.ImportGoogleContacts)
+	mux.HandleFunc("POST /api/import/linkedin", importAPI.ImportLinkedInConnections)
+	mux.HandleFunc("POST /api/import/telegram", importAPI.ImportTelegramChat)
+	mux.HandleFunc("POST /api/import/whatsapp", importAPI.ImportWhatsAppChat)
+	mux.HandleFunc("POST /api/import/mbox", importAPI.ImportMbox)
+}
+
+// RegisterAuthRoutes mounts registration, login, logout and the current
+// user endpoint.
+func RegisterAuthRoutes(mux *http.ServeMux, users *services.UserService, allowRegistration, secureCookies bool) {
+	authAPI := NewAuthAPI(users, allowRegistration, secureCookies)
+	mux.HandleFunc("POST /api/auth/register", authAPI.Register)
+	mux.HandleFunc("POST /api/auth/login", authAPI.Login)
+	mux.HandleFunc("POST /api/auth/logout", authAPI.Logout)
+	mux.HandleFunc("GET /api/auth/me", authAPI.Me)
+}
+
+// RegisterOIDCRoutes mounts the single sign-on login and its callback.
+func RegisterOIDCRoutes(mux *http.ServeMux, oidc *services.OIDCService, secureCookies bool, postLoginRedirect string) {
+	oidcAPI := NewOIDCAPI(oidc, secureCookies, postLoginRedirect)
+	mux.HandleFunc("GET /api/auth/oidc/login", oidcAPI.Login)
+	mux.HandleFunc("GET /api/auth/oidc/callback", oidcAPI.Callback)
+}
+
+// RegisterHealthRoutes mounts the liveness, readiness and build info
+// endpoints used by container orchestrators.
+func RegisterHealthRoutes(mux *http.ServeMux, health *services.HealthService) {
+	healthAPI := NewHealthAPI(health)
+	mux.HandleFunc("GET /healthz", healthAPI.Liveness)
+	mux.HandleFunc("GET /readyz", healthAPI.Readiness)
+	mux.HandleFunc("GET /version", healthAPI.Version)
+}
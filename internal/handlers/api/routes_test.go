@@ -0,0 +1,217 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/api"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/middleware"
+	"github.com/lincentpega/pcrm/internal/repository/memory"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+// testServer serves the whole API over an in-memory store, with
+// authentication and registration enabled as in a multi-user deployment.
+type testServer struct {
+	*httptest.Server
+	store *memory.Store
+	users *services.UserService
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	store := memory.NewStore()
+	users := services.NewUserService(store, time.Hour)
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux, store)
+	api.RegisterAuthRoutes(mux, users, true, false)
+	server := httptest.NewServer(middleware.AuthMiddleware(services.NewTokenService(store), users)(mux))
+	t.Cleanup(server.Close)
+	return &testServer{Server: server, store: store, users: users}
+}
+
+// anonymous returns a client without credentials.
+func (s *testServer) anonymous(t *testing.T) *client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &client{t: t, baseURL: s.URL, http: &http.Client{Jar: jar}}
+}
+
+// signUp creates the user and returns a client with a session cookie of
+// theirs. The user has no password, which keeps password hashing out of
+// tests that are not about logging in.
+func (s *testServer) signUp(t *testing.T, username string) *client {
+	t.Helper()
+	ctx := context.Background()
+	user, err := s.users.EnsureUser(ctx, username)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, secret, err := s.users.StartSession(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := s.anonymous(t)
+	serverURL, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.http.Jar.SetCookies(serverURL, []*http.Cookie{{Name: auth.SessionCookieName, Value: secret}})
+	return c
+}
+
+// client sends requests to a test server as one user.
+type client struct {
+	t       *testing.T
+	baseURL string
+	http    *http.Client
+	// bearer is sent as the Authorization header when set.
+	bearer string
+}
+
+type result struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (c *client) send(method, path, contentType string, body io.Reader) result {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearer)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return result{status: resp.StatusCode, header: resp.Header, body: data}
+}
+
+// do sends payload encoded as JSON, or no body when payload is nil.
+func (c *client) do(method, path string, payload any) result {
+	c.t.Helper()
+	if payload == nil {
+		return c.send(method, path, "", nil)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return c.send(method, path, "application/json", bytes.NewReader(data))
+}
+
+// mustDo sends payload like do, fails the test unless the response has the
+// status and decodes the body into out when it is not nil.
+func (c *client) mustDo(method, path string, payload any, status int, out any) result {
+	c.t.Helper()
+	res := c.do(method, path, payload)
+	res.expect(c.t, status, out)
+	return res
+}
+
+func (res result) expect(t *testing.T, status int, out any) {
+	t.Helper()
+	if res.status != status {
+		t.Fatalf("status = %d, want %d; body: %s", res.status, status, res.body)
+	}
+	if out != nil {
+		if err := json.Unmarshal(res.body, out); err != nil {
+			t.Fatalf("failed to decode %s: %v", res.body, err)
+		}
+	}
+}
+
+// problem decodes the problem details of an error response with the status.
+func (res result) problem(t *testing.T, status int) problem.Details {
+	t.Helper()
+	var details problem.Details
+	res.expect(t, status, &details)
+	if got := res.header.Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", got)
+	}
+	return details
+}
+
+// hasFieldProblem reports whether the problem names the field with the code.
+func hasFieldProblem(details problem.Details, field, code string) bool {
+	for _, fieldProblem := range details.Errors {
+		if fieldProblem.Field == field && fieldProblem.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// createPerson creates a person with the first and second name and returns
+// their id.
+func (c *client) createPerson(firstName, secondName string) int64 {
+	c.t.Helper()
+	var person dto.PersonProfileResponse
+	req := dto.PersonCreateRequest{PersonUpsertRequest: dto.PersonUpsertRequest{FirstName: firstName}}
+	if secondName != "" {
+		req.SecondName = &secondName
+	}
+	c.mustDo(http.MethodPost, "/api/people", req, http.StatusCreated, &person)
+	return person.ID
+}
+
+// typeID looks up the id of the named contact or conversation type listed
+// at path.
+func (c *client) typeID(path, name string) int64 {
+	c.t.Helper()
+	var types []dto.ContactTypeResponse
+	c.mustDo(http.MethodGet, path, nil, http.StatusOK, &types)
+	for _, contactType := range types {
+		if contactType.Name == name {
+			return contactType.ID
+		}
+	}
+	c.t.Fatalf("%s lists no type %q", path, name)
+	return 0
+}
+
+func TestRoutesRequireAuthentication(t *testing.T) {
+	server := newTestServer(t)
+	res := server.anonymous(t).do(http.MethodGet, "/api/people", nil)
+	res.problem(t, http.StatusUnauthorized)
+	if got := res.header.Get("WWW-Authenticate"); got == "" {
+		t.Error("401 response has no WWW-Authenticate header")
+	}
+}
+
+func TestRoutesUnknownRoute(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	if res := alice.do(http.MethodGet, "/api/nothing-here", nil); res.status != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", res.status)
+	}
+	if res := alice.do(http.MethodPatch, "/api/people", nil); res.status != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", res.status)
+	}
+}
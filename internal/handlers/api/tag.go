@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+type TagAPI struct {
+	service *services.TagService
+}
+
+func NewTagAPI(service *services.TagService) *TagAPI {
+	return &TagAPI{
+		service: service,
+	}
+}
+
+// GetTags godoc
+// @Summary Get the tags of a person
+// @Description Get the tags of a specific person, sorted by name
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param personId path int true "Person ID"
+// @Success 200 {object} dto.TagsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 404 {object} problem.Details "Person not found"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/people/{personId}/tags [get]
+func (api *TagAPI) GetTags(w http.ResponseWriter, r *http.Request) {
+	personID, err := PathID(r, "personId")
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	tags, err := api.service.GetTags(r.Context(), personID)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	WriteSuccess(w, dto.TagsResponse{Tags: tags})
+}
+
+// ReplaceTags godoc
+// @Summary Replace the tags of a person
+// @Description Set the complete list of a person's tags. Tags are trimmed and duplicates are dropped; an empty
+// @Description list removes every tag.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param personId path int true "Person ID"
+// @Param tags body dto.TagsRequest true "New tags"
+// @Success 200 {object} dto.TagsResponse
+// @Failure 400 {object} problem.Details
+// @Failure 401 {object} problem.Details
+// @Failure 403 {object} problem.Details
+// @Failure 404 {object} problem.Details "Person not found"
+// @Failure 500 {object} problem.Details
+// @Security BearerAuth
+// @Router /api/people/{personId}/tags [put]
+func (api *TagAPI) ReplaceTags(w http.ResponseWriter, r *http.Request) {
+	personID, err := PathID(r, "personId")
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	var req dto.TagsRequest
+	if err := DecodeJSON(r, &req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	if err := validators.ValidateTagsRequest(&req); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	tags, err := api.service.ReplaceTags(r.Context(), personID, req.Tags)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	WriteSuccess(w, dto.TagsResponse{Tags: tags})
+}
@@ -0,0 +1,39 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func TestReplaceTags(t *testing.T) {
+	alice := newTestServer(t).signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	path := fmt.Sprintf("/api/people/%d/tags", personID)
+	var tags dto.TagsResponse
+	alice.mustDo(http.MethodGet, path, nil, http.StatusOK, &tags)
+	if len(tags.Tags) != 0 {
+		t.Errorf("tags of a new person = %v, want none", tags.Tags)
+	}
+	alice.mustDo(http.MethodPut, path, dto.TagsRequest{Tags: []string{"work", "friend"}}, http.StatusOK, &tags)
+	alice.mustDo(http.MethodGet, path, nil, http.StatusOK, &tags)
+	if !slices.Equal(tags.Tags, []string{"friend", "work"}) {
+		t.Errorf("tags = %v, want [friend work]", tags.Tags)
+	}
+	alice.mustDo(http.MethodPut, path, dto.TagsRequest{Tags: []string{}}, http.StatusOK, &tags)
+	if len(tags.Tags) != 0 {
+		t.Errorf("tags after clearing = %v", tags.Tags)
+	}
+}
+
+func TestReplaceTagsErrors(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.signUp(t, "alice")
+	personID := alice.createPerson("Bob", "")
+	path := fmt.Sprintf("/api/people/%d/tags", personID)
+	alice.do(http.MethodPut, path, dto.TagsRequest{Tags: []string{" "}}).problem(t, http.StatusBadRequest)
+	server.signUp(t, "mallory").do(http.MethodPut, path, dto.TagsRequest{Tags: []string{"x"}}).problem(t, http.StatusNotFound)
+}
@@ -0,0 +1,212 @@
+// Package problem writes errors as RFC 7807 problem responses, for the API
+// handlers and the middleware in front of them alike.
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/repository"
+	"github.com/lincentpega/pcrm/internal/services"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+const problemContentType = "application/problem+json"
+
+const (
+	problemTypeValidation       = "/problems/validation-error"
+	problemTypeNotFound         = "/problems/not-found"
+	problemTypeConflict         = "/problems/conflict"
+	problemTypeReferenceMissing = "/problems/reference-violation"
+	problemTypeInternal         = "/problems/internal-error"
+	problemTypeCanceled         = "/problems/request-canceled"
+	problemTypeTimeout          = "/problems/timeout"
+	problemTypeUnauthorized     = "/problems/unauthorized"
+	problemTypeForbidden        = "/problems/forbidden"
+	problemTypeTooLarge         = "/problems/payload-too-large"
+	problemTypeRateLimited      = "/problems/rate-limited"
+)
+
+// statusClientClosedRequest is the non-standard status recorded when the
+// client goes away before the response is written.
+const statusClientClosedRequest = 499
+
+// Details is an RFC 7807 error body extended with field-level errors.
+type Details struct {
+	Type     string  `json:"type"`
+	Title    string  `json:"title"`
+	Status   int     `json:"status"`
+	Detail   string  `json:"detail,omitempty"`
+	Instance string  `json:"instance,omitempty"`
+	Errors   []Field `json:"errors,omitempty"`
+}
+
+type Field struct {
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func Write(w http.ResponseWriter, r *http.Request, problem Details) {
+	problem.Instance = r.URL.Path
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// WriteRateLimited answers 429 and tells the client how long to wait before
+// retrying.
+func WriteRateLimited(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	Write(w, r, Details{
+		Type:   problemTypeRateLimited,
+		Title:  "Too many requests",
+		Status: http.StatusTooManyRequests,
+		Detail: "the request rate limit was exceeded",
+	})
+}
+
+// WriteError maps validation, authentication and repository domain errors to
+// their problem response and hides any other error behind a generic internal
+// error.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := problemFromError(err)
+	logError(r, problem.Status, err)
+	Write(w, r, problem)
+}
+
+// logError records the cause of server-side failures, which clients only see
+// as a generic problem, at error level and client errors at debug level.
+func logError(r *http.Request, status int, err error) {
+	level := slog.LevelDebug
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	slog.Log(r.Context(), level, "Request failed", "method", r.Method, "path", r.URL.Path, "status", status, "error", err)
+}
+
+func problemFromError(err error) Details {
+	var fieldErrs validators.ValidationErrors
+	var notFoundErr *repository.NotFoundError
+	var conflictErr *repository.ConflictError
+	var foreignKeyErr *repository.ForeignKeyViolationError
+	var validationErr *repository.ValidationError
+	var authenticationErr *services.AuthenticationError
+	var permissionErr *services.PermissionError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &fieldErrs):
+		return Details{
+			Type:   problemTypeValidation,
+			Title:  "Invalid request",
+			Status: http.StatusBadRequest,
+			Detail: "one or more fields are invalid",
+			Errors: validationProblems(fieldErrs),
+		}
+	case errors.As(err, &notFoundErr):
+		return Details{
+			Type:   problemTypeNotFound,
+			Title:  "Resource not found",
+			Status: http.StatusNotFound,
+			Detail: notFoundErr.Error(),
+		}
+	case errors.As(err, &conflictErr):
+		return Details{
+			Type:   problemTypeConflict,
+			Title:  "Resource already exists",
+			Status: http.StatusConflict,
+			Detail: conflictErr.Error(),
+			Errors: fieldProblems(conflictErr.Field, "unique", "must be unique"),
+		}
+	case errors.As(err, &foreignKeyErr):
+		return Details{
+			Type:   problemTypeReferenceMissing,
+			Title:  "Referenced resource does not exist",
+			Status: http.StatusUnprocessableEntity,
+			Detail: foreignKeyErr.Error(),
+			Errors: fieldProblems(foreignKeyErr.Field, "reference", "references a resource that does not exist"),
+		}
+	case errors.As(err, &validationErr):
+		return Details{
+			Type:   problemTypeValidation,
+			Title:  "Invalid request",
+			Status: http.StatusBadRequest,
+			Detail: validationErr.Error(),
+			Errors: fieldProblems(validationErr.Field, "constraint", validationErr.Message),
+		}
+	case errors.As(err, &authenticationErr):
+		return Details{
+			Type:   problemTypeUnauthorized,
+			Title:  "Authentication required",
+			Status: http.StatusUnauthorized,
+			Detail: authenticationErr.Error(),
+		}
+	case errors.As(err, &permissionErr):
+		return Details{
+			Type:   problemTypeForbidden,
+			Title:  "Permission denied",
+			Status: http.StatusForbidden,
+			Detail: permissionErr.Error(),
+		}
+	case errors.As(err, &maxBytesErr):
+		return Details{
+			Type:   problemTypeTooLarge,
+			Title:  "Request body too large",
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: fmt.Sprintf("the request body must not exceed %d bytes", maxBytesErr.Limit),
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return Details{
+			Type:   problemTypeTimeout,
+			Title:  "Request timed out",
+			Status: http.StatusServiceUnavailable,
+			Detail: "the database did not respond in time",
+		}
+	case errors.Is(err, context.Canceled):
+		return Details{
+			Type:   problemTypeCanceled,
+			Title:  "Request canceled",
+			Status: statusClientClosedRequest,
+			Detail: "the request was canceled before it completed",
+		}
+	}
+	return Details{
+		Type:   problemTypeInternal,
+		Title:  "Internal server error",
+		Status: http.StatusInternalServerError,
+		Detail: "an unexpected error occurred",
+	}
+}
+
+func validationProblems(fieldErrs validators.ValidationErrors) []Field {
+	problems := make([]Field, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		problems[i] = Field{Field: fieldErr.Field, Code: fieldErr.Code, Message: fieldErr.Message}
+	}
+	return problems
+}
+
+func fieldProblems(column, code, message string) []Field {
+	if column == "" {
+		return nil
+	}
+	return []Field{{Field: jsonFieldName(column), Code: code, Message: message}}
+}
+
+func jsonFieldName(column string) string {
+	parts := strings.Split(column, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
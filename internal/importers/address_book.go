@@ -0,0 +1,99 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// The contact types the address book importers store contacts as. They are
+// seeded by the migrations.
+const (
+	ContactTypeEmail       = "Email"
+	ContactTypePhone       = "Phone"
+	ContactTypeAddress     = "Address"
+	ContactTypeWebsite     = "Website"
+	ContactTypeSocialMedia = "Social Media"
+)
+
+// ContactTypeIDs picks the ids of the named contact types out of
+// contactTypes, failing when one of them does not exist.
+func ContactTypeIDs(contactTypes map[string]int64, names ...string) ([]int64, error) {
+	var result validators.Result
+	ids := make([]int64, len(names))
+	for i, name := range names {
+		id, ok := contactTypes[name]
+		if !ok {
+			result.Add("", validators.CodeInvalid, fmt.Sprintf("contact type %q does not exist", name))
+		}
+		ids[i] = id
+	}
+	return ids, result.Err()
+}
+
+// addressBook reads the rows of an address book export by column name.
+type addressBook struct {
+	reader  *csv.Reader
+	indices map[string]int
+	record  []string
+	line    int
+}
+
+// next advances to the next row and reports false at the end of the file.
+func (b *addressBook) next() (bool, error) {
+	record, err := b.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, malformedCSV(err)
+	}
+	b.record = record
+	b.line, _ = b.reader.FieldPos(0)
+	return true, nil
+}
+
+// cell returns the trimmed value of the first of the named columns that the
+// file has and that is not empty in the current row.
+func (b *addressBook) cell(names ...string) string {
+	for _, name := range names {
+		index, ok := b.indices[name]
+		if !ok || index >= len(b.record) {
+			continue
+		}
+		if value := strings.TrimSpace(b.record[index]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// blank reports whether every cell of the current row is empty.
+func (b *addressBook) blank() bool {
+	for _, value := range b.record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func addContacts(person *dto.PersonCreateRequest, contactTypeID int64, values ...string) {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			person.Contacts = append(person.Contacts, dto.ContactRequest{ContactTypeID: contactTypeID, Content: value})
+		}
+	}
+}
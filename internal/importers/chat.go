@@ -0,0 +1,20 @@
+package importers
+
+import "time"
+
+// Chat is the history of a chat between the user and one person read from a
+// messenger export.
+type Chat struct {
+	// App names the messenger in the notes of the conversations.
+	App string
+	// PersonName is how the export names the other person.
+	PersonName string
+	Messages   []Message
+}
+
+// Message is one message of a chat. Only who sent it and when matter; the
+// text is not kept.
+type Message struct {
+	SentAt    time.Time
+	FromOwner bool
+}
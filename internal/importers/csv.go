@@ -0,0 +1,217 @@
+// Package importers reads people from files exported by spreadsheets and
+// other tools into create requests, so they pass the same validation as
+// people created through the API, and chat histories from messenger exports.
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// Row is a person read from one row of an import file together with the
+// problems that keep it from being imported.
+type Row struct {
+	Line   int
+	Person dto.PersonCreateRequest
+	Errors validators.ValidationErrors
+}
+
+func (r *Row) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// boundColumn is a mapped column resolved against the header row.
+type boundColumn struct {
+	index         int
+	field         string
+	contactTypeID int64
+}
+
+// ReadCSV reads the people of a CSV file whose first row names the columns.
+// contactTypes maps the names of the contact types to their ids. Rows whose
+// mapped cells are all empty are skipped. Problems with the file or the
+// mapping as a whole are returned as validation errors; those of a single
+// row are recorded on the row.
+func ReadCSV(r io.Reader, mapping dto.CSVMapping, contactTypes map[string]int64) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if mapping.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(mapping.Delimiter)
+	}
+	header, err := readHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := bindColumns(header, mapping, contactTypes)
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, malformedCSV(err)
+		}
+		line, _ := reader.FieldPos(0)
+		if row, ok := readCSVRow(record, columns); ok {
+			row.Line = line
+			rows = append(rows, row)
+		}
+	}
+}
+
+// readHeader reads the first row of a file, which names the columns.
+func readHeader(reader *csv.Reader) ([]string, error) {
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		var result validators.Result
+		result.Add("file", validators.CodeRequired, "file must start with a header row")
+		return nil, result.Err()
+	}
+	if err != nil {
+		return nil, malformedCSV(err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	return header, nil
+}
+
+// columnIndices maps the trimmed names of the columns to their position; a
+// name that appears twice refers to its first column.
+func columnIndices(header []string) map[string]int {
+	indices := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, ok := indices[name]; !ok {
+			indices[name] = i
+		}
+	}
+	return indices
+}
+
+func bindColumns(header []string, mapping dto.CSVMapping, contactTypes map[string]int64) ([]boundColumn, error) {
+	indices := columnIndices(header)
+	var result validators.Result
+	columns := make([]boundColumn, len(mapping.Columns))
+	for i, column := range mapping.Columns {
+		field := fmt.Sprintf("mapping.columns[%d]", i)
+		index, ok := indices[strings.TrimSpace(column.Column)]
+		if !ok {
+			result.Add(field+".column", validators.CodeInvalid, fmt.Sprintf("column %q is not in the header row", column.Column))
+		}
+		columns[i] = boundColumn{index: index, field: column.Field}
+		if column.Field == dto.CSVFieldContact {
+			if columns[i].contactTypeID, ok = contactTypes[strings.TrimSpace(column.ContactType)]; !ok {
+				result.Add(field+".contactType", validators.CodeInvalid, fmt.Sprintf("contact type %q does not exist", column.ContactType))
+			}
+		}
+	}
+	return columns, result.Err()
+}
+
+func readCSVRow(record []string, columns []boundColumn) (Row, bool) {
+	var row Row
+	var result validators.Result
+	person := &row.Person
+	empty := true
+	for _, column := range columns {
+		var value string
+		if column.index < len(record) {
+			value = strings.TrimSpace(record[column.index])
+		}
+		if value == "" {
+			continue
+		}
+		empty = false
+		switch column.field {
+		case dto.CSVFieldFirstName:
+			person.FirstName = value
+		case dto.CSVFieldSecondName:
+			person.SecondName = &value
+		case dto.CSVFieldMiddleName:
+			person.MiddleName = &value
+		case dto.CSVFieldCompany:
+			person.Company = &value
+		case dto.CSVFieldJobTitle:
+			person.JobTitle = &value
+		case dto.CSVFieldContact:
+			person.Contacts = append(person.Contacts, dto.ContactRequest{ContactTypeID: column.contactTypeID, Content: value})
+		case dto.CSVFieldBirthDate:
+			birthDateInfo, ok := parseBirthDate(value)
+			if !ok {
+				result.Add("birthDateInfo", validators.CodeInvalid, fmt.Sprintf("birth date %q must be YYYY-MM-DD or MM-DD", value))
+				continue
+			}
+			person.BirthDateInfo = birthDateInfo
+		case dto.CSVFieldTag:
+			person.Tags = append(person.Tags, splitTags(value)...)
+		case dto.CSVFieldMeetingStory:
+			person.ConnectionSource = &dto.ConnectionSourceRequest{MeetingStory: &value}
+		}
+	}
+	if empty {
+		return row, false
+	}
+	return newRow(row.Person, result), true
+}
+
+// newRow validates a person read from an import file, adding to the
+// problems found while reading it.
+func newRow(person dto.PersonCreateRequest, result validators.Result) Row {
+	result.Merge("", validators.ValidatePersonCreateRequest(&person))
+	row := Row{Person: person}
+	if err := result.Err(); err != nil {
+		row.Errors = err.(validators.ValidationErrors)
+	}
+	return row
+}
+
+// parseBirthDate reads a full date as YYYY-MM-DD and a date without a year
+// as MM-DD or --MM-DD, the form used by vCard. Ranges are left to the birth
+// date validator.
+func parseBirthDate(value string) (*dto.BirthDateInfoRequest, bool) {
+	parts := strings.Split(strings.TrimPrefix(value, "--"), "-")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, false
+	}
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers[i] = number
+	}
+	if len(numbers) == 2 {
+		return &dto.BirthDateInfoRequest{BirthMonth: &numbers[0], BirthDay: &numbers[1]}, true
+	}
+	return &dto.BirthDateInfoRequest{BirthYear: &numbers[0], BirthMonth: &numbers[1], BirthDay: &numbers[2]}, true
+}
+
+// splitTags reads a cell holding several tags separated by commas or
+// semicolons.
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func malformedCSV(err error) error {
+	var result validators.Result
+	result.Add("file", validators.CodeMalformed, err.Error())
+	return result.Err()
+}
@@ -0,0 +1,123 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// googleValueSeparator joins the values of a cell that holds several,
+// such as the addresses of "E-mail 1 - Value" or the labels of a contact.
+const googleValueSeparator = ":::"
+
+// googleContactColumn matches the numbered columns that hold contacts, in
+// both the current export ("Address 1 - Formatted") and the older one
+// ("E-mail 1 - Value").
+var googleContactColumn = regexp.MustCompile(`^(E-mail|Phone|Website|Address) \d+ - (Value|Formatted)$`)
+
+var googleContactTypes = map[string]string{
+	"E-mail":  ContactTypeEmail,
+	"Phone":   ContactTypePhone,
+	"Website": ContactTypeWebsite,
+	"Address": ContactTypeAddress,
+}
+
+type googleContactCell struct {
+	index         int
+	contactTypeID int64
+}
+
+// ReadGoogleContacts reads the "Google CSV" export of Google Contacts, in its
+// current form or the older one with "Given Name" and "Family Name" columns.
+// Emails, phones, websites and formatted addresses become contacts, the
+// organization becomes the company and job title, and labels other than the
+// system ones such as "* myContacts" become tags. An entry without a given
+// name is named after its display name, or its organization.
+func ReadGoogleContacts(r io.Reader, contactTypes map[string]int64) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := readHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	var cells []googleContactCell
+	for i, name := range header {
+		match := googleContactColumn.FindStringSubmatch(strings.TrimSpace(name))
+		if match == nil {
+			continue
+		}
+		ids, err := ContactTypeIDs(contactTypes, googleContactTypes[match[1]])
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, googleContactCell{index: i, contactTypeID: ids[0]})
+	}
+	book := &addressBook{reader: reader, indices: columnIndices(header)}
+	var rows []Row
+	for {
+		ok, err := book.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return rows, nil
+		}
+		if book.blank() {
+			continue
+		}
+		row := readGoogleContact(book, cells)
+		row.Line = book.line
+		rows = append(rows, row)
+	}
+}
+
+func readGoogleContact(book *addressBook, cells []googleContactCell) Row {
+	var result validators.Result
+	company := book.cell("Organization Name", "Organization 1 - Name")
+	person := dto.PersonCreateRequest{
+		PersonUpsertRequest: dto.PersonUpsertRequest{
+			FirstName:  book.cell("First Name", "Given Name", "Name", "File As", "Nickname"),
+			SecondName: optional(book.cell("Last Name", "Family Name")),
+			MiddleName: optional(book.cell("Middle Name", "Additional Name")),
+			Company:    optional(company),
+			JobTitle:   optional(book.cell("Organization Title", "Organization 1 - Title")),
+		},
+	}
+	if person.FirstName == "" {
+		person.FirstName = company
+	}
+	for _, cell := range cells {
+		if cell.index < len(book.record) {
+			addContacts(&person, cell.contactTypeID, splitGoogleValues(book.record[cell.index])...)
+		}
+	}
+	if birthday := book.cell("Birthday"); birthday != "" {
+		if birthDateInfo, ok := parseBirthDate(birthday); ok {
+			person.BirthDateInfo = birthDateInfo
+		} else {
+			result.Add("birthDateInfo", validators.CodeInvalid, fmt.Sprintf("birth date %q must be YYYY-MM-DD or --MM-DD", birthday))
+		}
+	}
+	for _, label := range splitGoogleValues(book.cell("Labels", "Group Membership")) {
+		if !strings.HasPrefix(label, "* ") && !slices.Contains(person.Tags, label) {
+			person.Tags = append(person.Tags, label)
+		}
+	}
+	return newRow(person, result)
+}
+
+func splitGoogleValues(cell string) []string {
+	var values []string
+	for _, value := range strings.Split(cell, googleValueSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
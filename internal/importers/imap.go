@@ -0,0 +1,112 @@
+package importers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+)
+
+// imapHeaderFields are the header fields fetched of every email.
+var imapHeaderFields = []string{"Message-ID", "Date", "From", "To", "Cc", "Subject"}
+
+// IMAPMailbox is a mailbox on an IMAP server.
+type IMAPMailbox struct {
+	// Address is the host and port of the server.
+	Address  string
+	TLS      bool
+	Username string
+	Password string
+	Mailbox  string
+}
+
+// ParseIMAPURL reads a mailbox from a URL such as
+// imaps://alice@mail.example.com/INBOX. The imaps scheme connects over TLS,
+// by default to port 993; imap connects without encryption, by default to
+// port 143, and is meant for servers on the local machine. The mailbox
+// defaults to INBOX. The password is not read from the URL.
+func ParseIMAPURL(raw string) (IMAPMailbox, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return IMAPMailbox{}, fmt.Errorf("invalid IMAP URL: %w", err)
+	}
+	mailbox := IMAPMailbox{Mailbox: strings.TrimPrefix(parsed.Path, "/")}
+	port := "143"
+	switch parsed.Scheme {
+	case "imaps":
+		mailbox.TLS = true
+		port = "993"
+	case "imap":
+	default:
+		return IMAPMailbox{}, errors.New("IMAP URL must start with imaps:// or imap://")
+	}
+	if parsed.Hostname() == "" || parsed.User == nil || parsed.User.Username() == "" {
+		return IMAPMailbox{}, errors.New("IMAP URL must name the user and host, as in imaps://alice@mail.example.com/INBOX")
+	}
+	if _, hasPassword := parsed.User.Password(); hasPassword {
+		return IMAPMailbox{}, errors.New("IMAP URL must not contain the password")
+	}
+	if parsed.Port() != "" {
+		port = parsed.Port()
+	}
+	mailbox.Address = net.JoinHostPort(parsed.Hostname(), port)
+	mailbox.Username = parsed.User.Username()
+	if mailbox.Mailbox == "" {
+		mailbox.Mailbox = "INBOX"
+	}
+	return mailbox, nil
+}
+
+// ReadIMAP reads the headers of the emails in the mailbox. The mailbox is
+// opened read-only, so no email is marked as seen.
+func ReadIMAP(mailbox IMAPMailbox) ([]Email, error) {
+	var c *client.Client
+	var err error
+	if mailbox.TLS {
+		c, err = client.DialTLS(mailbox.Address, nil)
+	} else {
+		c, err = client.Dial(mailbox.Address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", mailbox.Address, err)
+	}
+	defer c.Logout()
+	if err := c.Login(mailbox.Username, mailbox.Password); err != nil {
+		return nil, fmt.Errorf("failed to log in as %s: %w", mailbox.Username, err)
+	}
+	status, err := c.Select(mailbox.Mailbox, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mailbox %s: %w", mailbox.Mailbox, err)
+	}
+	if status.Messages == 0 {
+		return nil, nil
+	}
+	section := &imap.BodySectionName{
+		BodyPartName: imap.BodyPartName{Specifier: imap.HeaderSpecifier, Fields: imapHeaderFields},
+		Peek:         true,
+	}
+	seqSet := new(imap.SeqSet)
+	seqSet.AddRange(1, status.Messages)
+	messages := make(chan *imap.Message, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Fetch(seqSet, []imap.FetchItem{imap.FetchUid, section.FetchItem()}, messages)
+	}()
+	var emails []Email
+	for message := range messages {
+		header := message.GetBody(section)
+		if header == nil {
+			header = bytes.NewReader(nil)
+		}
+		emails = append(emails, readEmailHeader(fmt.Sprintf("UID %d", message.Uid), header))
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("failed to fetch emails: %w", err)
+	}
+	return emails, nil
+}
@@ -0,0 +1,95 @@
+package importers_test
+
+import (
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+
+	"github.com/lincentpega/pcrm/internal/importers"
+)
+
+// newIMAPServer serves the memory backend without TLS on a local port. Its
+// user "username" with password "password" has one seen email in INBOX, to
+// which the messages are appended. It returns the server's address and the
+// INBOX.
+func newIMAPServer(t *testing.T, messages ...*memory.Message) (string, *memory.Mailbox) {
+	t.Helper()
+	backend := memory.New()
+	user, err := backend.Login(nil, "username", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailbox, err := user.GetMailbox("INBOX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inbox := mailbox.(*memory.Mailbox)
+	inbox.Messages = append(inbox.Messages, messages...)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	imapServer := server.New(backend)
+	imapServer.AllowInsecureAuth = true
+	go imapServer.Serve(listener)
+	t.Cleanup(func() { imapServer.Close() })
+	return listener.Addr().String(), inbox
+}
+
+func readIMAP(t *testing.T, address, password string) ([]importers.Email, error) {
+	t.Helper()
+	mailbox, err := importers.ParseIMAPURL("imap://username@" + address + "/INBOX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailbox.Password = password
+	return importers.ReadIMAP(mailbox)
+}
+
+func TestReadIMAP(t *testing.T) {
+	body := aliceToBob + "\r\nShall we meet at noon?\r\n"
+	unseen := &memory.Message{Uid: 7, Date: time.Now(), Size: uint32(len(body)), Body: []byte(body)}
+	address, inbox := newIMAPServer(t, unseen, &memory.Message{Uid: 9, Date: time.Now(), Body: []byte(noMessageID)})
+	emails, err := readIMAP(t, address, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 3 {
+		t.Fatalf("read %d emails, want 3", len(emails))
+	}
+	if !emails[0].Valid() || emails[0].Source != "UID 6" || emails[0].From != "contact@example.org" {
+		t.Errorf("email = %+v, want the seeded email with UID 6", emails[0])
+	}
+	expectAliceToBob(t, emails[1], "UID 7")
+	if emails[2].Source != "UID 9" {
+		t.Errorf("source = %q, want UID 9", emails[2].Source)
+	}
+	expectFieldError(t, emails[2], "messageId")
+	if slices.Contains(inbox.Messages[1].Flags, imap.SeenFlag) {
+		t.Error("reading the mailbox marked an email as seen")
+	}
+}
+
+func TestReadIMAPRejectsWrongPassword(t *testing.T) {
+	address, _ := newIMAPServer(t)
+	if _, err := readIMAP(t, address, "wrong"); err == nil {
+		t.Error("logged in with a wrong password")
+	}
+}
+
+func TestReadIMAPRejectsMissingMailbox(t *testing.T) {
+	address, _ := newIMAPServer(t)
+	mailbox, err := importers.ParseIMAPURL("imap://username@" + address + "/Archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailbox.Password = "password"
+	if _, err := importers.ReadIMAP(mailbox); err == nil {
+		t.Error("read a mailbox that does not exist")
+	}
+}
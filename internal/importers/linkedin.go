@@ -0,0 +1,93 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// linkedInDateLayout is the format of the "Connected On" column, e.g.
+// "12 Mar 2023".
+const linkedInDateLayout = "02 Jan 2006"
+
+// ReadLinkedInConnections reads the Connections.csv file of a LinkedIn data
+// export, skipping the notes LinkedIn puts above the header. The profile URL
+// becomes a Social Media contact, the company and position are kept on the
+// person, and the date of connecting is recorded as the meeting time of the
+// connection source.
+func ReadLinkedInConnections(r io.Reader, contactTypes map[string]int64) ([]Row, error) {
+	ids, err := ContactTypeIDs(contactTypes, ContactTypeEmail, ContactTypeSocialMedia)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := readLinkedInHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	book := &addressBook{reader: reader, indices: columnIndices(header)}
+	var rows []Row
+	for {
+		ok, err := book.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return rows, nil
+		}
+		if book.blank() {
+			continue
+		}
+		row := readLinkedInConnection(book, ids[0], ids[1])
+		row.Line = book.line
+		rows = append(rows, row)
+	}
+}
+
+// readLinkedInHeader skips to the row that starts with "First Name".
+func readLinkedInHeader(reader *csv.Reader) ([]string, error) {
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			var result validators.Result
+			result.Add("file", validators.CodeInvalid, `file has no header row starting with "First Name"`)
+			return nil, result.Err()
+		}
+		if err != nil {
+			return nil, malformedCSV(err)
+		}
+		if strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff")) == "First Name" {
+			return record, nil
+		}
+	}
+}
+
+func readLinkedInConnection(book *addressBook, emailTypeID, profileTypeID int64) Row {
+	var result validators.Result
+	person := dto.PersonCreateRequest{
+		PersonUpsertRequest: dto.PersonUpsertRequest{
+			FirstName:  book.cell("First Name"),
+			SecondName: optional(book.cell("Last Name")),
+			Company:    optional(book.cell("Company")),
+			JobTitle:   optional(book.cell("Position")),
+		},
+	}
+	addContacts(&person, emailTypeID, book.cell("Email Address"))
+	addContacts(&person, profileTypeID, book.cell("URL"))
+	if connectedOn := book.cell("Connected On"); connectedOn != "" {
+		meetingTimestamp, err := time.Parse(linkedInDateLayout, connectedOn)
+		if err != nil {
+			result.Add("connectionSource.meetingTimestamp", validators.CodeInvalid, fmt.Sprintf("date %q must look like 12 Mar 2023", connectedOn))
+		} else {
+			person.ConnectionSource = &dto.ConnectionSourceRequest{MeetingTimestamp: &meetingTimestamp}
+		}
+	}
+	return newRow(person, result)
+}
@@ -0,0 +1,183 @@
+package importers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// Email is the header of an email, which is all the importers read of it,
+// together with the problems that keep it from being imported.
+type Email struct {
+	// Source locates the email: its line in an mbox, its file in a Maildir
+	// or its UID on an IMAP server.
+	Source    string
+	MessageID string
+	Subject   string
+	Date      time.Time
+	// From and Recipients are lowercase addresses; Recipients holds the To
+	// and Cc addresses.
+	From       string
+	Recipients []string
+	Errors     validators.ValidationErrors
+}
+
+func (e *Email) Valid() bool {
+	return len(e.Errors) == 0
+}
+
+// ReadMbox reads the headers of the emails in an mbox file, where every
+// email starts on a line beginning with "From ". Bodies are skipped.
+func ReadMbox(r io.Reader) ([]Email, error) {
+	reader := bufio.NewReader(r)
+	var emails []Email
+	var header bytes.Buffer
+	inHeader := false
+	start := 0
+	for number := 1; ; number++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, malformedMbox(err.Error())
+		}
+		if line == "" && errors.Is(err, io.EOF) {
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, "From "):
+			if inHeader {
+				emails = append(emails, readEmailHeader(fmt.Sprintf("line %d", start), &header))
+			}
+			header.Reset()
+			inHeader = true
+			start = number
+		case number == 1:
+			return nil, malformedMbox(`file must start with a "From " line`)
+		case inHeader && strings.TrimRight(line, "\r\n") == "":
+			emails = append(emails, readEmailHeader(fmt.Sprintf("line %d", start), &header))
+			inHeader = false
+		case inHeader:
+			header.WriteString(line)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+	if inHeader {
+		emails = append(emails, readEmailHeader(fmt.Sprintf("line %d", start), &header))
+	}
+	return emails, nil
+}
+
+// ReadMaildir reads the headers of the emails in the cur and new directories
+// of a Maildir.
+func ReadMaildir(dir string) ([]Email, error) {
+	var emails []Email
+	found := false
+	for _, sub := range []string{"cur", "new"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read maildir: %w", err)
+		}
+		found = true
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			source := filepath.Join(sub, entry.Name())
+			email, err := readEmailFile(filepath.Join(dir, source), source)
+			if err != nil {
+				return nil, err
+			}
+			emails = append(emails, email)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not a maildir: it has neither a cur nor a new directory", dir)
+	}
+	return emails, nil
+}
+
+func readEmailFile(path, source string) (Email, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to read email: %w", err)
+	}
+	defer file.Close()
+	return readEmailHeader(source, file), nil
+}
+
+// readEmailHeader parses the header that starts r; the body, if any, is not
+// read.
+func readEmailHeader(source string, r io.Reader) Email {
+	email := Email{Source: source}
+	var result validators.Result
+	message, err := mail.ReadMessage(io.MultiReader(r, strings.NewReader("\r\n")))
+	if err != nil {
+		result.Add("header", validators.CodeMalformed, err.Error())
+		email.Errors = result.Err().(validators.ValidationErrors)
+		return email
+	}
+	email.MessageID = strings.TrimSpace(message.Header.Get("Message-ID"))
+	switch {
+	case email.MessageID == "":
+		result.Add("messageId", validators.CodeRequired, "Message-ID header is required")
+	case utf8.RuneCountInString(email.MessageID) > validators.MaxMessageIDLength:
+		result.Add("messageId", validators.CodeTooLong, fmt.Sprintf("must be at most %d characters", validators.MaxMessageIDLength))
+	}
+	if email.Date, err = message.Header.Date(); err != nil {
+		result.Add("date", validators.CodeInvalid, "Date header is missing or invalid")
+	}
+	from, err := message.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		result.Add("from", validators.CodeInvalid, "From header is missing or invalid")
+	} else {
+		email.From = strings.ToLower(from[0].Address)
+	}
+	for _, name := range []string{"To", "Cc"} {
+		addresses, err := message.Header.AddressList(name)
+		if err != nil && !errors.Is(err, mail.ErrHeaderNotPresent) {
+			result.Add(strings.ToLower(name), validators.CodeInvalid, name+" header is invalid")
+		}
+		for _, address := range addresses {
+			if recipient := strings.ToLower(address.Address); !slices.Contains(email.Recipients, recipient) {
+				email.Recipients = append(email.Recipients, recipient)
+			}
+		}
+	}
+	email.Subject = decodeSubject(message.Header.Get("Subject"))
+	if err := result.Err(); err != nil {
+		email.Errors = err.(validators.ValidationErrors)
+	}
+	return email
+}
+
+// decodeSubject decodes the encoded words of a subject, keeping them as they
+// are in charsets the standard library does not know.
+func decodeSubject(subject string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(subject)
+	if err != nil {
+		return strings.TrimSpace(subject)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+func malformedMbox(message string) error {
+	var result validators.Result
+	result.Add("file", validators.CodeMalformed, message)
+	return result.Err()
+}
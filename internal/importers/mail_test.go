@@ -0,0 +1,135 @@
+package importers_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/importers"
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+const aliceToBob = "Message-ID: <1@example.com>\r\n" +
+	"Date: Mon, 02 Sep 2024 10:00:00 +0200\r\n" +
+	"From: Alice <Alice@Example.com>\r\n" +
+	"To: bob@example.com, Carol <carol@example.com>\r\n" +
+	"Cc: BOB@example.com\r\n" +
+	"Subject: =?UTF-8?B?THVuY2gg8J+NlQ==?=\r\n"
+
+const noMessageID = "Date: Tue, 03 Sep 2024 09:00:00 +0000\r\n" +
+	"From: bob@example.com\r\n" +
+	"Subject: Re: Lunch\r\n"
+
+// expectAliceToBob checks the email read from aliceToBob.
+func expectAliceToBob(t *testing.T, email importers.Email, source string) {
+	t.Helper()
+	if !email.Valid() {
+		t.Fatalf("email is invalid: %v", email.Errors)
+	}
+	if email.Source != source {
+		t.Errorf("source = %q, want %q", email.Source, source)
+	}
+	if email.MessageID != "<1@example.com>" || email.Subject != "Lunch 🍕" || email.From != "alice@example.com" {
+		t.Errorf("email = %+v", email)
+	}
+	if want := time.Date(2024, 9, 2, 8, 0, 0, 0, time.UTC); !email.Date.Equal(want) {
+		t.Errorf("date = %v, want %v", email.Date, want)
+	}
+	if want := []string{"bob@example.com", "carol@example.com"}; !slices.Equal(email.Recipients, want) {
+		t.Errorf("recipients = %v, want %v", email.Recipients, want)
+	}
+}
+
+// expectFieldError checks that the email failed on field alone.
+func expectFieldError(t *testing.T, email importers.Email, field string) {
+	t.Helper()
+	if len(email.Errors) != 1 || email.Errors[0].Field != field {
+		t.Errorf("errors = %v, want one on %s", email.Errors, field)
+	}
+}
+
+func TestReadMbox(t *testing.T) {
+	mbox := "From alice@example.com Mon Sep  2 10:00:00 2024\r\n" +
+		aliceToBob +
+		"\r\n" +
+		"Shall we meet at noon?\r\n" +
+		">From the office, Alice\r\n" +
+		"\r\n" +
+		"From bob@example.com Tue Sep  3 09:00:00 2024\n" +
+		strings.ReplaceAll(noMessageID, "\r\n", "\n") +
+		"\n" +
+		"Sure.\n"
+	emails, err := importers.ReadMbox(strings.NewReader(mbox))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 2 {
+		t.Fatalf("read %d emails, want 2", len(emails))
+	}
+	expectAliceToBob(t, emails[0], "line 1")
+	if emails[1].Source != "line 12" {
+		t.Errorf("source = %q, want line 12", emails[1].Source)
+	}
+	expectFieldError(t, emails[1], "messageId")
+}
+
+func TestReadMboxWithoutBody(t *testing.T) {
+	emails, err := importers.ReadMbox(strings.NewReader("From alice@example.com Mon Sep  2 10:00:00 2024\n" + aliceToBob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 1 {
+		t.Fatalf("read %d emails, want 1", len(emails))
+	}
+	expectAliceToBob(t, emails[0], "line 1")
+}
+
+func TestReadMboxRejectsOtherFiles(t *testing.T) {
+	_, err := importers.ReadMbox(strings.NewReader(aliceToBob))
+	var fieldErrs validators.ValidationErrors
+	if !errors.As(err, &fieldErrs) || fieldErrs[0].Field != "file" {
+		t.Errorf("err = %v, want a malformed file", err)
+	}
+}
+
+func writeEmail(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadMaildir(t *testing.T) {
+	dir := t.TempDir()
+	writeEmail(t, filepath.Join(dir, "cur", "1725264000.1.host:2,S"), aliceToBob+"\r\nShall we meet at noon?\r\n")
+	writeEmail(t, filepath.Join(dir, "new", "1725350400.2.host"), noMessageID)
+	writeEmail(t, filepath.Join(dir, "tmp", "1725350401.3.host"), aliceToBob)
+	if err := os.Mkdir(filepath.Join(dir, "new", "subdir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	emails, err := importers.ReadMaildir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 2 {
+		t.Fatalf("read %d emails, want 2", len(emails))
+	}
+	expectAliceToBob(t, emails[0], filepath.Join("cur", "1725264000.1.host:2,S"))
+	if emails[1].Source != filepath.Join("new", "1725350400.2.host") {
+		t.Errorf("source = %q", emails[1].Source)
+	}
+	expectFieldError(t, emails[1], "messageId")
+}
+
+func TestReadMaildirRejectsOtherDirectories(t *testing.T) {
+	if _, err := importers.ReadMaildir(t.TempDir()); err == nil {
+		t.Error("read a directory without cur and new as a maildir")
+	}
+}
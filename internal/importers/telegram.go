@@ -0,0 +1,130 @@
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// telegramDateLayout is the format of the local "date" of a message, used
+// when the export has no "date_unixtime".
+const telegramDateLayout = "2006-01-02T15:04:05"
+
+const telegramPersonalChat = "personal_chat"
+
+// telegramExport is the result.json of a Telegram Desktop export: either a
+// single chat or, for an export of all data, a list of chats.
+type telegramExport struct {
+	telegramChat
+	Chats *struct {
+		List []telegramChat `json:"list"`
+	} `json:"chats"`
+}
+
+type telegramChat struct {
+	// Name is null for deleted accounts.
+	Name     *string           `json:"name"`
+	Type     string            `json:"type"`
+	ID       int64             `json:"id"`
+	Messages []telegramMessage `json:"messages"`
+}
+
+type telegramMessage struct {
+	Type         string `json:"type"`
+	Date         string `json:"date"`
+	DateUnixtime string `json:"date_unixtime"`
+	FromID       string `json:"from_id"`
+}
+
+// ReadTelegramChat reads a personal chat from the result.json of a Telegram
+// Desktop export in JSON format. An export of a single chat is read as is;
+// from an export of all data the personal chat named chatName is picked.
+// Messages sent by the other person, whose user id is the id of the chat,
+// count as theirs and all others as the user's. Service messages such as
+// calls are left out. Dates without a timezone are read in loc.
+func ReadTelegramChat(r io.Reader, chatName string, loc *time.Location) (*Chat, error) {
+	var export telegramExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		var result validators.Result
+		result.Add("file", validators.CodeMalformed, "file is not a Telegram JSON export: "+err.Error())
+		return nil, result.Err()
+	}
+	source := &export.telegramChat
+	if export.Chats != nil {
+		var err error
+		if source, err = findTelegramChat(export.Chats.List, chatName); err != nil {
+			return nil, err
+		}
+	}
+	if source.Type != telegramPersonalChat {
+		var result validators.Result
+		result.Add("file", validators.CodeInvalid, "chat must be a personal chat")
+		return nil, result.Err()
+	}
+	chat := &Chat{App: "Telegram", PersonName: "Deleted Account"}
+	if source.Name != nil {
+		chat.PersonName = *source.Name
+	}
+	personID := "user" + strconv.FormatInt(source.ID, 10)
+	for i, message := range source.Messages {
+		if message.Type != "message" {
+			continue
+		}
+		sentAt, err := telegramMessageTime(message, loc)
+		if err != nil {
+			var result validators.Result
+			result.Add("file", validators.CodeInvalid, fmt.Sprintf("message %d: %v", i+1, err))
+			return nil, result.Err()
+		}
+		chat.Messages = append(chat.Messages, Message{SentAt: sentAt, FromOwner: message.FromID != personID})
+	}
+	return chat, nil
+}
+
+// findTelegramChat picks the personal chat named name out of an export of
+// all data.
+func findTelegramChat(chats []telegramChat, name string) (*telegramChat, error) {
+	var result validators.Result
+	if strings.TrimSpace(name) == "" {
+		result.Add("chat", validators.CodeRequired, "chat is required for an export of all data")
+		return nil, result.Err()
+	}
+	var found *telegramChat
+	for i := range chats {
+		if chats[i].Type != telegramPersonalChat || chats[i].Name == nil || !strings.EqualFold(strings.TrimSpace(*chats[i].Name), strings.TrimSpace(name)) {
+			continue
+		}
+		if found != nil {
+			result.Add("chat", validators.CodeConflict, fmt.Sprintf("several personal chats are named %q", name))
+			return nil, result.Err()
+		}
+		found = &chats[i]
+	}
+	if found == nil {
+		result.Add("chat", validators.CodeInvalid, fmt.Sprintf("no personal chat is named %q", name))
+		return nil, result.Err()
+	}
+	return found, nil
+}
+
+// telegramMessageTime prefers the absolute "date_unixtime" of newer exports
+// over the local "date".
+func telegramMessageTime(message telegramMessage, loc *time.Location) (time.Time, error) {
+	if message.DateUnixtime != "" {
+		seconds, err := strconv.ParseInt(message.DateUnixtime, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("date_unixtime %q must be a number of seconds", message.DateUnixtime)
+		}
+		return time.Unix(seconds, 0).In(loc), nil
+	}
+	sentAt, err := time.ParseInLocation(telegramDateLayout, message.Date, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date %q must look like 2023-03-12T14:05:33", message.Date)
+	}
+	return sentAt, nil
+}
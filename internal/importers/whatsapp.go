@@ -0,0 +1,169 @@
+package importers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/validators"
+)
+
+// maxWhatsAppLine caps the length of a line of a chat export.
+const maxWhatsAppLine = 1 << 20
+
+// whatsAppMessagePattern matches the first line of a message in both the
+// Android format, "12/03/2023, 14:05 - Bob: Hi", and the iOS format,
+// "[12/03/2023, 14:05:33] Bob: Hi", with 24 or 12 hour clocks. Lines that
+// do not match continue the previous message or are notices such as the one
+// on end-to-end encryption.
+var whatsAppMessagePattern = regexp.MustCompile(`^\[?(\d{1,4})[./-](\d{1,2})[./-](\d{1,4}),? (\d{1,2})[:.](\d{2})(?:[:.](\d{2}))?(?: ?([AaPp])\.? ?[Mm]\.?)?\]?(?: -)? ([^:]+): `)
+
+// whatsAppInvisibles are left out of lines before matching them: direction
+// marks, and the narrow and non-breaking spaces some locales put before
+// AM and PM.
+var whatsAppInvisibles = strings.NewReplacer("\ufeff", "", "\u200e", "", "\u200f", "", "\u202f", " ", "\u00a0", " ")
+
+// whatsAppEncryptionNotice starts the notice that opens every chat, which
+// the iOS format signs with the name of the chat.
+const whatsAppEncryptionNotice = "Messages and calls are end-to-end encrypted"
+
+// whatsAppLine is the first line of a message split into its parts.
+type whatsAppLine struct {
+	number               int
+	date                 [3]int
+	yearFirst            bool
+	hour, minute, second int
+	meridiem             string
+	sender               string
+	senderIsOwner        bool
+}
+
+// ReadWhatsAppChat reads a chat with one person from the .txt file of
+// WhatsApp's "Export chat". The export does not tell whose phone it comes
+// from, so owner is the name the user's own messages are signed with; the
+// one other sender is the person. Whether dates put the day or the month
+// first is told from the dates themselves, and is the day when no date
+// settles it. Times are read in loc.
+func ReadWhatsAppChat(r io.Reader, owner string, loc *time.Location) (*Chat, error) {
+	lines, err := readWhatsAppLines(r, owner)
+	if err != nil {
+		return nil, err
+	}
+	chat := &Chat{App: "WhatsApp", PersonName: "them"}
+	var others []string
+	ownerFound := false
+	for _, line := range lines {
+		if line.senderIsOwner {
+			ownerFound = true
+		} else if !slices.Contains(others, line.sender) {
+			others = append(others, line.sender)
+		}
+	}
+	var result validators.Result
+	switch {
+	case len(others) > 1 && !ownerFound:
+		result.Add("owner", validators.CodeInvalid, fmt.Sprintf("owner %q has sent no messages in this chat", owner))
+		return nil, result.Err()
+	case len(others) > 1:
+		result.Add("file", validators.CodeInvalid, "chat must be with one person but has messages from "+strings.Join(others, ", "))
+		return nil, result.Err()
+	case len(others) == 1:
+		chat.PersonName = others[0]
+	}
+	dayFirst := whatsAppDayFirst(lines)
+	for _, line := range lines {
+		sentAt, err := line.time(dayFirst, loc)
+		if err != nil {
+			result.Add("file", validators.CodeInvalid, fmt.Sprintf("line %d: %v", line.number, err))
+			return nil, result.Err()
+		}
+		chat.Messages = append(chat.Messages, Message{SentAt: sentAt, FromOwner: line.senderIsOwner})
+	}
+	return chat, nil
+}
+
+func readWhatsAppLines(r io.Reader, owner string) ([]whatsAppLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxWhatsAppLine)
+	var lines []whatsAppLine
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := whatsAppInvisibles.Replace(scanner.Text())
+		match := whatsAppMessagePattern.FindStringSubmatch(text)
+		if match == nil || strings.HasPrefix(text[len(match[0]):], whatsAppEncryptionNotice) {
+			continue
+		}
+		line := whatsAppLine{
+			number:    number,
+			yearFirst: len(match[1]) == 4,
+			meridiem:  strings.ToUpper(match[7]),
+			sender:    strings.TrimSpace(match[8]),
+		}
+		for i := range line.date {
+			line.date[i], _ = strconv.Atoi(match[1+i])
+		}
+		line.hour, _ = strconv.Atoi(match[4])
+		line.minute, _ = strconv.Atoi(match[5])
+		line.second, _ = strconv.Atoi(match[6])
+		line.senderIsOwner = strings.EqualFold(line.sender, strings.TrimSpace(owner))
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		var result validators.Result
+		result.Add("file", validators.CodeMalformed, err.Error())
+		return nil, result.Err()
+	}
+	return lines, nil
+}
+
+// whatsAppDayFirst tells whether the dates of the export put the day before
+// the month, as in 31/12/2023, rather than after it, as in 12/31/23.
+func whatsAppDayFirst(lines []whatsAppLine) bool {
+	for _, line := range lines {
+		switch {
+		case line.yearFirst:
+		case line.date[0] > 12:
+			return true
+		case line.date[1] > 12:
+			return false
+		}
+	}
+	return true
+}
+
+func (l *whatsAppLine) time(dayFirst bool, loc *time.Location) (time.Time, error) {
+	year, month, day := l.date[2], l.date[1], l.date[0]
+	switch {
+	case l.yearFirst:
+		year, month, day = l.date[0], l.date[1], l.date[2]
+	case !dayFirst:
+		month, day = l.date[0], l.date[1]
+	}
+	if year < 100 {
+		year += 2000
+	}
+	hour := l.hour
+	switch {
+	case l.meridiem != "" && (hour < 1 || hour > 12):
+		return time.Time{}, fmt.Errorf("hour %d does not exist on a 12 hour clock", hour)
+	case l.meridiem == "A" && hour == 12:
+		hour = 0
+	case l.meridiem == "P" && hour < 12:
+		hour += 12
+	}
+	if month < 1 || month > 12 || day < 1 || hour > 23 || l.minute > 59 || l.second > 59 {
+		return time.Time{}, errors.New("date or time out of range")
+	}
+	sentAt := time.Date(year, time.Month(month), day, hour, l.minute, l.second, 0, loc)
+	if sentAt.Day() != day {
+		return time.Time{}, fmt.Errorf("day %d does not exist in month %d", day, month)
+	}
+	return sentAt, nil
+}
@@ -0,0 +1,25 @@
+package mappers
+
+import (
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+func APITokenRequestToDomain(req *dto.APITokenRequest) *models.APIToken {
+	return &models.APIToken{
+		Name:      req.Name,
+		Scope:     req.Scope,
+		ExpiresAt: req.ExpiresAt,
+	}
+}
+
+func APITokenDomainToResponse(token *models.APIToken) dto.APITokenResponse {
+	return dto.APITokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scope:      token.Scope,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,166 @@
+package mappers
+
+import (
+	"strings"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// ArchiveDomainToResponse maps an export to the archive format. The export
+// time is left to the caller.
+func ArchiveDomainToResponse(archive *models.Archive) dto.Archive {
+	response := dto.Archive{
+		Version:           dto.ArchiveVersion,
+		ContactTypes:      make([]dto.ArchiveType, len(archive.ContactTypes)),
+		ConversationTypes: make([]dto.ArchiveType, len(archive.ConversationTypes)),
+		People:            make([]dto.ArchivePerson, len(archive.People)),
+		Contacts:          make([]dto.ArchiveContact, len(archive.Contacts)),
+		ConnectionSources: make([]dto.ArchiveConnectionSource, len(archive.ConnectionSources)),
+		BirthDateInfo:     make([]dto.ArchiveBirthDateInfo, len(archive.BirthDateInfo)),
+		Conversations:     make([]dto.ArchiveConversation, len(archive.Conversations)),
+	}
+	for i, contactType := range archive.ContactTypes {
+		response.ContactTypes[i] = dto.ArchiveType{ID: contactType.ID, Name: contactType.Name}
+	}
+	for i, conversationType := range archive.ConversationTypes {
+		response.ConversationTypes[i] = dto.ArchiveType{ID: conversationType.ID, Name: conversationType.Name}
+	}
+	for i, person := range archive.People {
+		response.People[i] = dto.ArchivePerson{
+			ID: person.ID,
+			PersonUpsertRequest: dto.PersonUpsertRequest{
+				FirstName:  person.FirstName,
+				SecondName: person.SecondName,
+				MiddleName: person.MiddleName,
+				Company:    person.Company,
+				JobTitle:   person.JobTitle,
+			},
+			Tags:              archive.Tags[person.ID],
+			ArchiveTimestamps: dto.ArchiveTimestamps{CreatedAt: person.CreatedAt, UpdatedAt: person.UpdatedAt},
+		}
+	}
+	for i, contact := range archive.Contacts {
+		response.Contacts[i] = dto.ArchiveContact{
+			ID:       contact.ID,
+			PersonID: contact.PersonID,
+			ContactRequest: dto.ContactRequest{
+				ContactTypeID: contact.ContactTypeID,
+				Content:       contact.Content,
+			},
+			ArchiveTimestamps: dto.ArchiveTimestamps{CreatedAt: contact.CreatedAt, UpdatedAt: contact.UpdatedAt},
+		}
+	}
+	for i, connectionSource := range archive.ConnectionSources {
+		response.ConnectionSources[i] = dto.ArchiveConnectionSource{
+			PersonID: connectionSource.PersonID,
+			ConnectionSourceRequest: dto.ConnectionSourceRequest{
+				MeetingStory:       connectionSource.MeetingStory,
+				MeetingTimestamp:   connectionSource.MeetingTimestamp,
+				WasIntroduced:      connectionSource.WasIntroduced,
+				IntroducerPersonID: connectionSource.IntroducerPersonID,
+				IntroducerName:     connectionSource.IntroducerName,
+			},
+			ArchiveTimestamps: dto.ArchiveTimestamps{CreatedAt: connectionSource.CreatedAt, UpdatedAt: connectionSource.UpdatedAt},
+		}
+	}
+	for i, birthDateInfo := range archive.BirthDateInfo {
+		response.BirthDateInfo[i] = dto.ArchiveBirthDateInfo{
+			PersonID: birthDateInfo.PersonID,
+			BirthDateInfoRequest: dto.BirthDateInfoRequest{
+				BirthYear:      birthDateInfo.BirthYear,
+				BirthMonth:     birthDateInfo.BirthMonth,
+				BirthDay:       birthDateInfo.BirthDay,
+				ApproximateAge: birthDateInfo.ApproximateAge,
+			},
+			ApproximateAgeUpdatedAt: birthDateInfo.ApproximateAgeUpdatedAt,
+			ArchiveTimestamps:       dto.ArchiveTimestamps{CreatedAt: birthDateInfo.CreatedAt, UpdatedAt: birthDateInfo.UpdatedAt},
+		}
+	}
+	for i, conversation := range archive.Conversations {
+		response.Conversations[i] = dto.ArchiveConversation{
+			ID:       conversation.ID,
+			PersonID: conversation.PersonID,
+			ConversationRequest: dto.ConversationRequest{
+				ConversationTypeID: conversation.ConversationTypeID,
+				Initiator:          conversation.Initiator,
+				Notes:              conversation.Notes,
+			},
+			MessageID:         conversation.MessageID,
+			ArchiveTimestamps: dto.ArchiveTimestamps{CreatedAt: conversation.CreatedAt, UpdatedAt: conversation.UpdatedAt},
+		}
+	}
+	return response
+}
+
+// ArchiveRequestToDomain maps a validated archive to records that keep their
+// archive ids. A birth date record without the time its approximate age was
+// recorded gets the current time, as when it is created through the API.
+func ArchiveRequestToDomain(archive *dto.Archive) *models.Archive {
+	domain := &models.Archive{
+		ContactTypes:      make([]models.ContactType, len(archive.ContactTypes)),
+		ConversationTypes: make([]models.ConversationType, len(archive.ConversationTypes)),
+		People:            make([]models.Person, len(archive.People)),
+		Contacts:          make([]models.Contact, len(archive.Contacts)),
+		ConnectionSources: make([]models.ConnectionSource, len(archive.ConnectionSources)),
+		BirthDateInfo:     make([]models.BirthDateInfo, len(archive.BirthDateInfo)),
+		Conversations:     make([]models.Conversation, len(archive.Conversations)),
+		Tags:              make(map[int64][]string),
+	}
+	for i, contactType := range archive.ContactTypes {
+		domain.ContactTypes[i] = models.ContactType{ID: contactType.ID, Name: strings.TrimSpace(contactType.Name)}
+	}
+	for i, conversationType := range archive.ConversationTypes {
+		domain.ConversationTypes[i] = models.ConversationType{ID: conversationType.ID, Name: strings.TrimSpace(conversationType.Name)}
+	}
+	for i := range archive.People {
+		person := &archive.People[i]
+		domain.People[i] = *PersonUpsertRequestToDomain(&person.PersonUpsertRequest)
+		domain.People[i].ID = person.ID
+		domain.People[i].CreatedAt, domain.People[i].UpdatedAt = person.CreatedAt, person.UpdatedAt
+		if len(person.Tags) > 0 {
+			domain.Tags[person.ID] = person.Tags
+		}
+	}
+	for i := range archive.Contacts {
+		contact := &archive.Contacts[i]
+		domain.Contacts[i] = *ContactRequestToDomain(contact.PersonID, &contact.ContactRequest)
+		domain.Contacts[i].ID = contact.ID
+		domain.Contacts[i].CreatedAt, domain.Contacts[i].UpdatedAt = contact.CreatedAt, contact.UpdatedAt
+	}
+	for i := range archive.ConnectionSources {
+		connectionSource := &archive.ConnectionSources[i]
+		domain.ConnectionSources[i] = *ConnectionSourceRequestToDomain(connectionSource.PersonID, &connectionSource.ConnectionSourceRequest)
+		domain.ConnectionSources[i].CreatedAt, domain.ConnectionSources[i].UpdatedAt = connectionSource.CreatedAt, connectionSource.UpdatedAt
+	}
+	for i := range archive.BirthDateInfo {
+		birthDateInfo := &archive.BirthDateInfo[i]
+		domain.BirthDateInfo[i] = *BirthDateInfoRequestToDomain(birthDateInfo.PersonID, &birthDateInfo.BirthDateInfoRequest)
+		if birthDateInfo.ApproximateAge != nil && birthDateInfo.ApproximateAgeUpdatedAt != nil {
+			domain.BirthDateInfo[i].ApproximateAgeUpdatedAt = birthDateInfo.ApproximateAgeUpdatedAt
+		}
+		domain.BirthDateInfo[i].CreatedAt, domain.BirthDateInfo[i].UpdatedAt = birthDateInfo.CreatedAt, birthDateInfo.UpdatedAt
+	}
+	for i := range archive.Conversations {
+		conversation := &archive.Conversations[i]
+		domain.Conversations[i] = *ConversationRequestToDomain(conversation.PersonID, &conversation.ConversationRequest)
+		domain.Conversations[i].ID = conversation.ID
+		domain.Conversations[i].MessageID = conversation.MessageID
+		domain.Conversations[i].CreatedAt, domain.Conversations[i].UpdatedAt = conversation.CreatedAt, conversation.UpdatedAt
+	}
+	return domain
+}
+
+func ImportResultDomainToResponse(result *models.ImportResult) dto.ImportResponse {
+	return dto.ImportResponse{
+		DryRun:            result.DryRun,
+		ContactTypes:      result.ContactTypes,
+		ConversationTypes: result.ConversationTypes,
+		People:            result.People,
+		Contacts:          result.Contacts,
+		ConnectionSources: result.ConnectionSources,
+		BirthDateInfo:     result.BirthDateInfo,
+		Conversations:     result.Conversations,
+		PersonIDs:         result.PersonIDs,
+	}
+}
@@ -0,0 +1,21 @@
+package mappers
+
+import (
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+func UserDomainToResponse(user *models.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func SessionDomainToResponse(user *models.User, session *models.Session) dto.SessionResponse {
+	return dto.SessionResponse{
+		User:      UserDomainToResponse(user),
+		ExpiresAt: session.ExpiresAt,
+	}
+}
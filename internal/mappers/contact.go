@@ -0,0 +1,22 @@
+package mappers
+
+import (
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+func ContactRequestToDomain(personID int64, req *dto.ContactRequest) *models.Contact {
+	return &models.Contact{
+		PersonID:      personID,
+		ContactTypeID: req.ContactTypeID,
+		Content:       req.Content,
+	}
+}
+
+func ContactTypeDomainToResponse(contactType *models.ContactType) dto.ContactTypeResponse {
+	return dto.ContactTypeResponse{
+		ID:        contactType.ID,
+		Name:      contactType.Name,
+		CreatedAt: contactType.CreatedAt,
+	}
+}
@@ -20,6 +20,7 @@ func ConversationDomainToResponse(conversation *models.Conversation) dto.Convers
         PersonID:           conversation.PersonID,
         Initiator:          conversation.Initiator,
         Notes:              conversation.Notes,
+        MessageID:          conversation.MessageID,
         CreatedAt:          conversation.CreatedAt,
         UpdatedAt:          conversation.UpdatedAt,
         ConversationType: dto.ConversationTypeResponse{
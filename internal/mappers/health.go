@@ -0,0 +1,32 @@
+package mappers
+
+import (
+	"github.com/lincentpega/pcrm/internal/buildinfo"
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/migrate"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// HealthChecksToReadinessResponse reports ready only when every check passed.
+func HealthChecksToReadinessResponse(checks []models.HealthCheck) dto.ReadinessResponse {
+	response := dto.ReadinessResponse{Status: "ready", Checks: make([]dto.CheckResponse, len(checks))}
+	for i, check := range checks {
+		response.Checks[i] = dto.CheckResponse{Name: check.Name, Status: "ok"}
+		if check.Err != nil {
+			response.Checks[i].Status = "failing"
+			response.Checks[i].Error = check.Err.Error()
+			response.Status = "not ready"
+		}
+	}
+	return response
+}
+
+func BuildInfoToVersionResponse(info buildinfo.Info, schema migrate.State) dto.VersionResponse {
+	return dto.VersionResponse{
+		Commit:        info.Commit,
+		BuildTime:     info.BuildTime,
+		GoVersion:     info.GoVersion,
+		SchemaVersion: schema.Version,
+		SchemaDirty:   schema.Dirty,
+	}
+}
@@ -0,0 +1,185 @@
+package mappers
+
+import (
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/importers"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// ImportRowToResponse reports a row read from an import file; personID is
+// zero unless the row was stored.
+func ImportRowToResponse(row *importers.Row, personID int64, skipped bool) dto.ImportRowResponse {
+	response := dto.ImportRowResponse{
+		Line:     row.Line,
+		PersonID: personID,
+		Skipped:  skipped,
+		Person:   row.Person,
+	}
+	for _, fieldErr := range row.Errors {
+		response.Errors = append(response.Errors, dto.ImportRowError{
+			Field:   fieldErr.Field,
+			Code:    fieldErr.Code,
+			Message: fieldErr.Message,
+		})
+	}
+	return response
+}
+
+// ImportRowsToDomain turns the rows read from an import file into profiles,
+// leaving nil in place of the rows that are invalid.
+func ImportRowsToDomain(rows []importers.Row) []*models.PersonProfile {
+	profiles := make([]*models.PersonProfile, len(rows))
+	for i := range rows {
+		if rows[i].Valid() {
+			profiles[i] = PersonCreateRequestToDomain(&rows[i].Person)
+		}
+	}
+	return profiles
+}
+
+// ProfileImportToResponse reports what importing the rows did; summary holds
+// the outcome of every row, in order.
+func ProfileImportToResponse(rows []importers.Row, summary *models.ProfileImport, dryRun bool) dto.ImportRowsResponse {
+	response := dto.ImportRowsResponse{
+		DryRun:   dryRun,
+		Imported: summary.Imported,
+		Skipped:  summary.Skipped,
+		Invalid:  summary.Invalid,
+		Rows:     make([]dto.ImportRowResponse, len(rows)),
+	}
+	for i := range rows {
+		response.Rows[i] = ImportRowToResponse(&rows[i], summary.Profiles[i].PersonID, summary.Profiles[i].Skipped)
+	}
+	return response
+}
+
+// ChatToDomain keeps who sent each message of the chat and when.
+func ChatToDomain(chat *importers.Chat) *models.Chat {
+	messages := make([]models.ChatMessage, len(chat.Messages))
+	for i, message := range chat.Messages {
+		messages[i] = models.ChatMessage{SentAt: message.SentAt, FromOwner: message.FromOwner}
+	}
+	return &models.Chat{App: chat.App, PersonName: chat.PersonName, Messages: messages}
+}
+
+// ChatImportToResponse reports what importing the chat did with each of its
+// days.
+func ChatImportToResponse(chat *importers.Chat, summary *models.ChatImport, dryRun bool) dto.ChatImportResponse {
+	response := dto.ChatImportResponse{
+		DryRun:     dryRun,
+		PersonName: chat.PersonName,
+		Messages:   len(chat.Messages),
+		Imported:   summary.Imported,
+		Skipped:    summary.Skipped,
+		Days:       make([]dto.ChatImportDayResponse, len(summary.Days)),
+	}
+	for i := range summary.Days {
+		response.Days[i] = chatDayImportToResponse(&summary.Days[i])
+	}
+	return response
+}
+
+func chatDayImportToResponse(day *models.ChatDayImport) dto.ChatImportDayResponse {
+	return dto.ChatImportDayResponse{
+		Date:           day.FirstMessageAt.Format(time.DateOnly),
+		ConversationID: day.ConversationID,
+		Skipped:        day.Skipped,
+		Initiator:      day.Initiator,
+		Notes:          day.Notes,
+		OwnerMessages:  day.OwnerMessages,
+		PersonMessages: day.PersonMessages,
+		FirstMessageAt: day.FirstMessageAt,
+		LastMessageAt:  day.LastMessageAt,
+	}
+}
+
+func EmailToDomain(email *importers.Email) models.Email {
+	return models.Email{
+		MessageID:  email.MessageID,
+		Subject:    email.Subject,
+		Date:       email.Date,
+		From:       email.From,
+		Recipients: email.Recipients,
+	}
+}
+
+// EmailsToDomain returns the emails that could be read, leaving out those
+// with errors.
+func EmailsToDomain(emails []importers.Email) []models.Email {
+	var valid []models.Email
+	for i := range emails {
+		if emails[i].Valid() {
+			valid = append(valid, EmailToDomain(&emails[i]))
+		}
+	}
+	return valid
+}
+
+// EmailImportsToResponse counts what importing the emails did and lists the
+// emails that concern someone or could not be read. imports holds the result
+// of every valid email, in order.
+func EmailImportsToResponse(emails []importers.Email, imports []models.EmailImport, dryRun bool) dto.EmailImportResponse {
+	response := dto.EmailImportResponse{DryRun: dryRun, Emails: len(emails), Items: []dto.EmailImportItemResponse{}}
+	next := 0
+	for i := range emails {
+		if !emails[i].Valid() {
+			response.Invalid++
+			response.Items = append(response.Items, emailImportToResponse(&emails[i], nil, false))
+			continue
+		}
+		result := &imports[next]
+		next++
+		switch {
+		case len(result.Conversations) > 0:
+			response.Imported++
+			response.Conversations += len(result.Conversations)
+		case len(result.DuplicatePersonIDs) > 0:
+			response.Duplicates++
+		default:
+			response.Unmatched++
+			continue
+		}
+		response.Items = append(response.Items, emailImportToResponse(&emails[i], result, !dryRun))
+	}
+	return response
+}
+
+// emailImportToResponse reports an email of a mailbox; result is nil for an
+// email that could not be read. conversations is false on a dry run, whose
+// conversations were not kept.
+func emailImportToResponse(email *importers.Email, result *models.EmailImport, conversations bool) dto.EmailImportItemResponse {
+	response := dto.EmailImportItemResponse{
+		Source:    email.Source,
+		Status:    dto.EmailImportInvalid,
+		MessageID: email.MessageID,
+		From:      email.From,
+		Subject:   email.Subject,
+	}
+	if !email.Date.IsZero() {
+		response.Date = &email.Date
+	}
+	for _, fieldErr := range email.Errors {
+		response.Errors = append(response.Errors, dto.ImportRowError{
+			Field:   fieldErr.Field,
+			Code:    fieldErr.Code,
+			Message: fieldErr.Message,
+		})
+	}
+	if result == nil {
+		return response
+	}
+	response.Status = dto.EmailImportDuplicate
+	if len(result.Conversations) > 0 {
+		response.Status = dto.EmailImportImported
+	}
+	for _, conversation := range result.Conversations {
+		response.PersonIDs = append(response.PersonIDs, conversation.PersonID)
+		if conversations {
+			response.ConversationIDs = append(response.ConversationIDs, conversation.ID)
+		}
+	}
+	response.DuplicatePersonIDs = result.DuplicatePersonIDs
+	return response
+}
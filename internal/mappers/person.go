@@ -10,6 +10,8 @@ func PersonUpsertRequestToDomain(req *dto.PersonUpsertRequest) *models.Person {
 		FirstName:  req.FirstName,
 		SecondName: req.SecondName,
 		MiddleName: req.MiddleName,
+		Company:    req.Company,
+		JobTitle:   req.JobTitle,
 	}
 }
 
@@ -19,6 +21,8 @@ func PersonDomainToResponse(person *models.Person) dto.PersonInfoResponse {
 		FirstName:  person.FirstName,
 		SecondName: person.SecondName,
 		MiddleName: person.MiddleName,
+		Company:    person.Company,
+		JobTitle:   person.JobTitle,
 		CreatedAt:  person.CreatedAt,
 		UpdatedAt:  person.UpdatedAt,
 	}
@@ -0,0 +1,53 @@
+package mappers
+
+import (
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// PersonCreateRequestToDomain maps the nested create request to a profile
+// whose sub-resources are not yet bound to a person id.
+func PersonCreateRequestToDomain(req *dto.PersonCreateRequest) *models.PersonProfile {
+	profile := &models.PersonProfile{
+		Person:   *PersonUpsertRequestToDomain(&req.PersonUpsertRequest),
+		Contacts: make([]models.Contact, len(req.Contacts)),
+		Tags:     req.Tags,
+	}
+	for i := range req.Contacts {
+		profile.Contacts[i] = *ContactRequestToDomain(0, &req.Contacts[i])
+	}
+	if req.ConnectionSource != nil {
+		profile.ConnectionSource = ConnectionSourceRequestToDomain(0, req.ConnectionSource)
+	}
+	if req.BirthDateInfo != nil {
+		profile.BirthDateInfo = BirthDateInfoRequestToDomain(0, req.BirthDateInfo)
+	}
+	if req.Conversation != nil {
+		profile.Conversations = []models.Conversation{*ConversationRequestToDomain(0, req.Conversation)}
+	}
+	return profile
+}
+
+func PersonProfileDomainToResponse(profile *models.PersonProfile) dto.PersonProfileResponse {
+	response := dto.PersonProfileResponse{
+		PersonInfoResponse: PersonDomainToResponse(&profile.Person),
+		Contacts:           make([]dto.ContactResponse, len(profile.Contacts)),
+		Conversations:      make([]dto.ConversationResponse, len(profile.Conversations)),
+		Tags:               profile.Tags,
+	}
+	for i := range profile.Contacts {
+		response.Contacts[i] = ContactDomainToResponse(&profile.Contacts[i])
+	}
+	for i := range profile.Conversations {
+		response.Conversations[i] = ConversationDomainToResponse(&profile.Conversations[i])
+	}
+	if profile.ConnectionSource != nil {
+		connectionSource := ConnectionSourceDomainToResponse(profile.ConnectionSource)
+		response.ConnectionSource = &connectionSource
+	}
+	if profile.BirthDateInfo != nil {
+		birthDateInfo := BirthDateInfoDomainToResponse(profile.BirthDateInfo)
+		response.BirthDateInfo = &birthDateInfo
+	}
+	return response
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const scrapeTimeout = 5 * time.Second
+
+type domainCollector struct {
+	stats             DomainStats
+	people            *prometheus.Desc
+	conversationsWeek *prometheus.Desc
+}
+
+func newDomainCollector(stats DomainStats) *domainCollector {
+	return &domainCollector{
+		stats:             stats,
+		people:            prometheus.NewDesc(namespace+"_people", "People stored.", nil, nil),
+		conversationsWeek: prometheus.NewDesc(namespace+"_conversations_this_week", "Conversations logged since Monday 00:00.", nil, nil),
+	}
+}
+
+func (c *domainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.people
+	ch <- c.conversationsWeek
+}
+
+// Collect reports a query failure as an invalid metric, which drops that
+// gauge from the scrape without hiding the others.
+func (c *domainCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+	people, err := c.stats.CountPeople(ctx)
+	ch <- gaugeOrInvalid(c.people, people, err)
+	conversations, err := c.stats.CountConversationsThisWeek(ctx, time.Now())
+	ch <- gaugeOrInvalid(c.conversationsWeek, conversations, err)
+}
+
+func gaugeOrInvalid(desc *prometheus.Desc, value int, err error) prometheus.Metric {
+	if err != nil {
+		return prometheus.NewInvalidMetric(desc, err)
+	}
+	return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value))
+}
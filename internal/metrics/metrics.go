@@ -0,0 +1,92 @@
+// Package metrics exposes HTTP, database pool and domain metrics in the
+// Prometheus format.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pcrm"
+
+// UnmatchedRoute labels requests no route pattern matched, keeping raw paths
+// out of the label values.
+const UnmatchedRoute = "unmatched"
+
+// HTTPMetrics records request counts, latencies and in-flight requests per
+// route pattern.
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+func NewHTTPMetrics(registerer prometheus.Registerer) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route pattern and status code.",
+		}, []string{"route", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served.",
+		}),
+	}
+	registerer.MustRegister(m.requests, m.duration, m.inFlight)
+	return m
+}
+
+func (m *HTTPMetrics) RequestStarted() {
+	m.inFlight.Inc()
+}
+
+func (m *HTTPMetrics) RequestFinished(route string, status int, elapsed time.Duration) {
+	m.inFlight.Dec()
+	m.requests.WithLabelValues(route, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(route).Observe(elapsed.Seconds())
+}
+
+// DomainStats provides the figures behind the domain gauges.
+type DomainStats interface {
+	CountPeople(ctx context.Context) (int, error)
+	CountConversationsThisWeek(ctx context.Context, now time.Time) (int, error)
+}
+
+// NewRegistry returns a registry with Go runtime, process, database pool and
+// domain collectors. Domain figures are queried on every scrape.
+func NewRegistry(db *sql.DB, stats DomainStats) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, namespace),
+		newDomainCollector(stats),
+	)
+	return registry
+}
+
+// Handler serves the registry's metrics. Collection errors are logged and
+// leave out only the affected metrics.
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		Registry:      registry,
+		ErrorHandling: promhttp.ContinueOnError,
+		ErrorLog:      slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+	})
+}
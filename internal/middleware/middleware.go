@@ -1,45 +1,369 @@
 package middleware
 
 import (
-	"log"
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/logging"
+	"github.com/lincentpega/pcrm/internal/metrics"
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/repository"
+	"github.com/lincentpega/pcrm/internal/services"
 )
 
-func LoggingMiddleware(next http.Handler) http.Handler {
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestIDMiddleware reuses a well-formed incoming X-Request-ID or generates
+// one, echoes it in the response and stores it in the request context.
+func RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		
-		next.ServeHTTP(w, r)
-		
-		log.Printf("%s %s %v", r.Method, r.URL.Path, time.Since(start))
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = rand.Text()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
 	})
 }
 
-func RecoveryMiddleware(next http.Handler) http.Handler {
+// TracingMiddleware starts a server span per request, continuing the trace
+// of an incoming traceparent header. The span is named after the ServeMux
+// pattern that RouteMiddleware reports once routing has matched one.
+func TracingMiddleware(next http.Handler) http.Handler {
+	tracer := otel.Tracer("github.com/lincentpega/pcrm/internal/middleware")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(r.URL.Path),
+			semconv.UserAgentOriginal(r.UserAgent()),
+			attribute.String("request.id", logging.RequestID(ctx)),
+		))
+		defer span.End()
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		r, route := withRoute(r.WithContext(ctx))
+		next.ServeHTTP(recorder, r)
+		if route.pattern != "" {
+			span.SetName(r.Method + " " + patternPath(route.pattern))
+			span.SetAttributes(semconv.HTTPRoute(patternPath(route.pattern)))
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+type routeContextKey struct{}
+
+// route carries the ServeMux pattern of a request back out to the outer
+// middleware. The mux sets Request.Pattern only on the request it is given,
+// which is a copy whenever a middleware in between replaces the context.
+type route struct {
+	pattern string
+}
+
+// withRoute returns r with a route in its context, reusing the one an outer
+// middleware has already added.
+func withRoute(r *http.Request) (*http.Request, *route) {
+	if current, ok := r.Context().Value(routeContextKey{}).(*route); ok {
+		return r, current
+	}
+	current := &route{}
+	return r.WithContext(context.WithValue(r.Context(), routeContextKey{}, current)), current
+}
+
+// RouteMiddleware reports the pattern the wrapped ServeMux matched to
+// TracingMiddleware and MetricsMiddleware. It must wrap the mux directly,
+// inside every middleware that replaces the request.
+func RouteMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
-			if err := recover(); err != nil {
-				log.Printf("panic: %v", err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			if current, ok := r.Context().Value(routeContextKey{}).(*route); ok {
+				current.pattern = r.Pattern
 			}
 		}()
-		
 		next.ServeHTTP(w, r)
 	})
 }
 
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// patternPath strips the method and host from a ServeMux pattern such as
+// "GET /api/people/{id}".
+func patternPath(pattern string) string {
+	if i := strings.IndexByte(pattern, '/'); i >= 0 {
+		return pattern[i:]
+	}
+	return pattern
+}
+
+// AuthMiddleware requires an "Authorization: Bearer" personal access token
+// or a session cookie on every /api request except the login and
+// registration endpoints, and makes the request act for the token's or
+// session's user. Token reads need the read scope; token writes and token
+// management need the write scope. Sessions may do anything their user can.
+// The token is stored in the request context.
+func AuthMiddleware(tokens *services.TokenService, users *services.UserService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, "/api/") || isPublicAuthRoute(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, err := authenticate(tokens, users, r)
+			if err != nil {
+				var authenticationErr *services.AuthenticationError
+				if errors.As(err, &authenticationErr) {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="pcrm"`)
+				}
+				problem.WriteError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 		
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// LocalUserMiddleware makes every /api request act for the named user, for
+// single-user deployments without authentication. The user is created on
+// the first request if needed, so the server starts before migrations run.
+func LocalUserMiddleware(users *services.UserService, username string) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	var userID int64
+	resolve := func(ctx context.Context) (int64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if userID == 0 {
+			user, err := users.EnsureUser(ctx, username)
+			if err != nil {
+				return 0, err
+			}
+			userID = user.ID
 		}
-		
-		next.ServeHTTP(w, r)
-	})
+		return userID, nil
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id, err := resolve(r.Context())
+			if err != nil {
+				problem.WriteError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), id)))
+		})
+	}
+}
+
+func isPublicAuthRoute(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost:
+		return r.URL.Path == "/api/auth/login" || r.URL.Path == "/api/auth/register" || r.URL.Path == "/api/auth/logout"
+	case http.MethodGet:
+		return r.URL.Path == "/api/auth/oidc/login" || r.URL.Path == "/api/auth/oidc/callback"
+	}
+	return false
+}
+
+func authenticate(tokens *services.TokenService, users *services.UserService, r *http.Request) (context.Context, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, secret, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(secret) == "" {
+			return nil, &services.AuthenticationError{Message: "a bearer token is required"}
+		}
+		token, err := tokens.Authenticate(r.Context(), strings.TrimSpace(secret))
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, &services.AuthenticationError{Message: "invalid token"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := tokens.Authorize(token, requiredScope(r)); err != nil {
+			return nil, err
+		}
+		return auth.WithUserID(services.ContextWithToken(r.Context(), token), token.UserID), nil
+	}
+	cookie, err := r.Cookie(auth.SessionCookieName)
+	if err != nil {
+		return nil, &services.AuthenticationError{Message: "a bearer token or session cookie is required"}
+	}
+	session, err := users.AuthenticateSession(r.Context(), cookie.Value)
+	if err != nil {
+		return nil, err
+	}
+	return auth.WithUserID(r.Context(), session.UserID), nil
+}
+
+func requiredScope(r *http.Request) string {
+	readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead
+	if readOnly && !strings.HasPrefix(r.URL.Path, "/api/tokens") {
+		return models.TokenScopeRead
+	}
+	return models.TokenScopeWrite
+}
+
+// LoggingMiddleware logs every completed request with its status, response
+// size, latency and user agent.
+func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+			logger.LogAttrs(r.Context(), slog.LevelInfo, "Request completed",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", recorder.status),
+				slog.Int("bytes", recorder.bytes),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("user_agent", r.UserAgent()),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// MetricsMiddleware records every request under the ServeMux pattern that
+// matched it, as reported by RouteMiddleware.
+func MetricsMiddleware(httpMetrics *metrics.HTTPMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			r, route := withRoute(r)
+			httpMetrics.RequestStarted()
+			defer func() {
+				pattern := route.pattern
+				if pattern == "" {
+					pattern = metrics.UnmatchedRoute
+				}
+				httpMetrics.RequestFinished(pattern, recorder.status, time.Since(start))
+			}()
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if err := recover(); err != nil {
+					logger.ErrorContext(r.Context(), "Panic while serving request", "panic", err, "path", r.URL.Path)
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// responseRecorder captures the status code and body size written by the
+// wrapped handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func CORSMiddleware(cfg config.CORSConfig) func(http.Handler) http.Handler {
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge / time.Second))
+	return func(next http.Handler) http.Handler {
+		if len(cfg.AllowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+			origin := r.Header.Get("Origin")
+			if origin == "" || !originAllowed(cfg.AllowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				if exposed != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposed)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			if headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			if cfg.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+func originAllowed(patterns []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard {
+			if pattern == origin {
+				return true
+			}
+			continue
+		}
+		if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
 }
\ No newline at end of file
@@ -0,0 +1,286 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justinas/alice"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/metrics"
+	"github.com/lincentpega/pcrm/internal/middleware"
+	"github.com/lincentpega/pcrm/internal/repository/memory"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+// routedServer chains the tracing and metrics middleware around user, with
+// RouteMiddleware around the mux as in the server, and records what they
+// report.
+type routedServer struct {
+	handler  http.Handler
+	registry *prometheus.Registry
+	spans    *tracetest.SpanRecorder
+}
+
+func newRoutedServer(t *testing.T, user alice.Constructor) *routedServer {
+	t.Helper()
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	registry := prometheus.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/people/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := alice.New(
+		middleware.TracingMiddleware,
+		middleware.MetricsMiddleware(metrics.NewHTTPMetrics(registry)),
+		user,
+		middleware.RouteMiddleware,
+	).Then(mux)
+	return &routedServer{handler: handler, registry: registry, spans: spans}
+}
+
+func (s *routedServer) serve(t *testing.T, req *http.Request, status int) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, req)
+	if recorder.Code != status {
+		t.Fatalf("status = %d, want %d", recorder.Code, status)
+	}
+}
+
+// requests returns the request count recorded under the route and code.
+func (s *routedServer) requests(t *testing.T, route, code string) float64 {
+	t.Helper()
+	families, err := s.registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "pcrm_http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["route"] == route && labels["code"] == code {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func (s *routedServer) lastSpan(t *testing.T) sdktrace.ReadOnlySpan {
+	t.Helper()
+	ended := s.spans.Ended()
+	if len(ended) == 0 {
+		t.Fatal("no span was recorded")
+	}
+	return ended[len(ended)-1]
+}
+
+func (s *routedServer) expectRoute(t *testing.T, route string) {
+	t.Helper()
+	if got := s.requests(t, route, "200"); got != 1 {
+		t.Errorf("requests for route %q = %v, want 1", route, got)
+	}
+	if got := s.lastSpan(t).Name(); got != route {
+		t.Errorf("span name = %q, want %q", got, route)
+	}
+}
+
+func TestRouteLabelBehindAuthMiddleware(t *testing.T) {
+	store := memory.NewStore()
+	users := services.NewUserService(store, time.Hour)
+	server := newRoutedServer(t, middleware.AuthMiddleware(services.NewTokenService(store), users))
+	ctx := context.Background()
+	user, err := users.EnsureUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, secret, err := users.StartSession(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/people/7", nil)
+	req.AddCookie(&http.Cookie{Name: auth.SessionCookieName, Value: secret})
+	server.serve(t, req, http.StatusOK)
+	server.expectRoute(t, "GET /api/people/{id}")
+	server.serve(t, httptest.NewRequest(http.MethodGet, "/api/people/7", nil), http.StatusUnauthorized)
+	if got := server.requests(t, metrics.UnmatchedRoute, "401"); got != 1 {
+		t.Errorf("rejected requests labelled %q = %v, want 1", metrics.UnmatchedRoute, got)
+	}
+}
+
+func TestRouteLabelBehindLocalUserMiddleware(t *testing.T) {
+	users := services.NewUserService(memory.NewStore(), time.Hour)
+	server := newRoutedServer(t, middleware.LocalUserMiddleware(users, "owner"))
+	server.serve(t, httptest.NewRequest(http.MethodGet, "/api/people/7", nil), http.StatusOK)
+	server.expectRoute(t, "GET /api/people/{id}")
+	server.serve(t, httptest.NewRequest(http.MethodGet, "/api/nothing-here", nil), http.StatusNotFound)
+	if got := server.requests(t, metrics.UnmatchedRoute, "404"); got != 1 {
+		t.Errorf("unknown routes labelled %q = %v, want 1", metrics.UnmatchedRoute, got)
+	}
+}
+
+func TestAuthFailureLimitThrottlesRejectedCredentials(t *testing.T) {
+	store := memory.NewStore()
+	users := services.NewUserService(store, time.Hour)
+	ctx := context.Background()
+	user, err := users.EnsureUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, secret, err := users.StartSession(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/people", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := alice.New(
+		middleware.AuthFailureLimitMiddleware(config.RateLimitConfig{Auth: config.RateLimitRule{Rate: 0.01, Burst: 2}}),
+		middleware.AuthMiddleware(services.NewTokenService(store), users),
+	).Then(mux)
+	serve := func(remoteAddr, bearer string, status int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/people", nil)
+		req.RemoteAddr = remoteAddr
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		} else {
+			req.AddCookie(&http.Cookie{Name: auth.SessionCookieName, Value: secret})
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != status {
+			t.Fatalf("status = %d, want %d", recorder.Code, status)
+		}
+		return recorder
+	}
+	for range 5 {
+		serve("192.0.2.1:1234", "", http.StatusOK)
+	}
+	serve("192.0.2.1:1234", "guess-1", http.StatusUnauthorized)
+	serve("192.0.2.1:1234", "guess-2", http.StatusUnauthorized)
+	if got := serve("192.0.2.1:1234", "guess-3", http.StatusTooManyRequests).Header().Get("Retry-After"); got == "" {
+		t.Error("429 response has no Retry-After header")
+	}
+	serve("192.0.2.1:1234", "", http.StatusTooManyRequests)
+	serve("192.0.2.2:1234", "guess-4", http.StatusUnauthorized)
+	serve("192.0.2.2:1234", "", http.StatusOK)
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins:   []string{"https://crm.example.com", "https://*.example.org", "http://localhost:*"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+	tests := []struct {
+		name          string
+		method        string
+		origin        string
+		requestMethod string
+		allowed       bool
+		preflight     bool
+	}{
+		{name: "exact origin", method: http.MethodGet, origin: "https://crm.example.com", allowed: true},
+		{name: "exact origin in another case", method: http.MethodGet, origin: "HTTPS://CRM.example.com", allowed: true},
+		{name: "wildcard subdomain", method: http.MethodPost, origin: "https://app.example.org", allowed: true},
+		{name: "wildcard port", method: http.MethodGet, origin: "http://localhost:5173", allowed: true},
+		{name: "wildcard matching nothing", method: http.MethodGet, origin: "https://.example.org"},
+		{name: "suffix of an exact origin", method: http.MethodGet, origin: "https://evil-crm.example.com"},
+		{name: "other scheme", method: http.MethodGet, origin: "http://crm.example.com"},
+		{name: "wildcard origin on another domain", method: http.MethodGet, origin: "https://app.example.org.evil.com"},
+		{name: "no origin", method: http.MethodGet},
+		{name: "preflight", method: http.MethodOptions, origin: "https://crm.example.com", requestMethod: http.MethodPost, allowed: true, preflight: true},
+		{name: "OPTIONS without a requested method", method: http.MethodOptions, origin: "https://crm.example.com", allowed: true},
+		{name: "preflight from a disallowed origin", method: http.MethodOptions, origin: "https://evil.com", requestMethod: http.MethodPost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := middleware.CORSMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(tt.method, "/api/people", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			header := recorder.Header()
+			if !slices.Contains(header.Values("Vary"), "Origin") {
+				t.Errorf("Vary = %v, want Origin", header.Values("Vary"))
+			}
+			if reached == tt.preflight {
+				t.Errorf("handler reached = %v, want %v", reached, !tt.preflight)
+			}
+			if !tt.allowed {
+				for name := range header {
+					if strings.HasPrefix(name, "Access-Control-Allow-") {
+						t.Errorf("disallowed origin got %s: %q", name, header.Get(name))
+					}
+				}
+				return
+			}
+			want := map[string]string{
+				"Access-Control-Allow-Origin":      tt.origin,
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+				"Access-Control-Allow-Methods":     "",
+				"Access-Control-Allow-Headers":     "",
+				"Access-Control-Max-Age":           "",
+			}
+			if tt.preflight {
+				want["Access-Control-Expose-Headers"] = ""
+				want["Access-Control-Allow-Methods"] = "GET, POST"
+				want["Access-Control-Allow-Headers"] = "Authorization, Content-Type"
+				want["Access-Control-Max-Age"] = "600"
+				if recorder.Code != http.StatusNoContent {
+					t.Errorf("preflight status = %d, want 204", recorder.Code)
+				}
+			}
+			for name, value := range want {
+				if got := header.Get(name); got != value {
+					t.Errorf("%s = %q, want %q", name, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareWithoutOrigins(t *testing.T) {
+	handler := middleware.CORSMiddleware(config.CORSConfig{AllowCredentials: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/people", nil)
+	req.Header.Set("Origin", "https://crm.example.com")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q without allowed origins", got)
+	}
+}
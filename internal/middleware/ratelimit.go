@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+// sweepInterval is how often buckets that have refilled completely are
+// dropped.
+const sweepInterval = time.Minute
+
+// importPath is the prefix of the import endpoints, which accept larger
+// bodies.
+const importPath = "/api/import"
+
+// BodyLimitMiddleware rejects request bodies larger than limit bytes, or
+// importLimit bytes for the import endpoints, with 413. Bodies without a
+// declared length are cut off while they are read.
+func BodyLimitMiddleware(limit, importLimit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limit
+			if r.URL.Path == importPath || strings.HasPrefix(r.URL.Path, importPath+"/") {
+				limit = importLimit
+			}
+			if r.ContentLength > limit {
+				problem.WriteError(w, r, &http.MaxBytesError{Limit: limit})
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitMiddleware throttles /api requests with one token bucket per API
+// token, or per client IP for requests without one, in each of the read,
+// write and auth route groups. It must run after authentication so the
+// token is known; AuthFailureLimitMiddleware throttles the requests that
+// authentication rejects.
+func RateLimitMiddleware(cfg config.RateLimitConfig) func(http.Handler) http.Handler {
+	groups := map[string]*rateLimiter{
+		"read":  newRateLimiter(cfg.Read),
+		"write": newRateLimiter(cfg.Write),
+		"auth":  newRateLimiter(cfg.Auth),
+	}
+	trusted := parsePrefixes(cfg.TrustedProxies)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limiter := groups[rateLimitGroup(r)]
+			if limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := "ip:" + clientIP(r, trusted)
+			if token := services.TokenFromContext(r.Context()); token != nil {
+				key = "token:" + strconv.FormatInt(token.ID, 10)
+			}
+			if ok, retryAfter := limiter.allow(key, time.Now()); !ok {
+				problem.WriteRateLimited(w, r, retryAfter)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthFailureLimitMiddleware throttles guessing API tokens and session
+// cookies. Every /api request answered with 401 draws from a bucket of the
+// client IP kept with the auth rule, and once it is empty every /api request
+// of that IP is refused until it refills. It must run before authentication,
+// which rejects requests before RateLimitMiddleware sees them. The login,
+// registration and single sign-on endpoints are left to the auth group of
+// RateLimitMiddleware.
+func AuthFailureLimitMiddleware(cfg config.RateLimitConfig) func(http.Handler) http.Handler {
+	limiter := newRateLimiter(cfg.Auth)
+	trusted := parsePrefixes(cfg.TrustedProxies)
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rateLimitGroup(r) == "" || isPublicAuthRoute(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := "ip:" + clientIP(r, trusted)
+			if retryAfter := limiter.wait(key, time.Now()); retryAfter > 0 {
+				problem.WriteRateLimited(w, r, retryAfter)
+				return
+			}
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+			if recorder.status == http.StatusUnauthorized {
+				limiter.allow(key, time.Now())
+			}
+		})
+	}
+}
+
+func rateLimitGroup(r *http.Request) string {
+	switch {
+	case !strings.HasPrefix(r.URL.Path, "/api/"):
+		return ""
+	case isPublicAuthRoute(r):
+		return "auth"
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return "read"
+	}
+	return "write"
+}
+
+// clientIP returns the address the request came from. When the peer is a
+// trusted proxy the X-Forwarded-For chain is walked from the nearest hop
+// and the first address that is not a trusted proxy is used.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	peer, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	ip := peer.Addr().Unmap()
+	if !isTrusted(ip, trusted) {
+		return ip.String()
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		ip = hop.Unmap()
+		if !isTrusted(ip, trusted) {
+			break
+		}
+	}
+	return ip.String()
+}
+
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePrefixes(values []string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, value := range values {
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(value); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+type rateLimiter struct {
+	rate    float64
+	burst   float64
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// newRateLimiter returns nil for a rule without a rate, which leaves the
+// group unlimited.
+func newRateLimiter(rule config.RateLimitRule) *rateLimiter {
+	if rule.Rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{rate: rule.Rate, burst: float64(rule.Burst), buckets: make(map[string]*bucket)}
+}
+
+// allow takes a token from key's bucket, or reports how long until one is
+// available.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.refill(key, now)
+	if b.tokens < 1 {
+		return false, l.untilToken(b)
+	}
+	b.tokens--
+	return true, 0
+}
+
+// wait reports how long until key's bucket holds a token, without taking
+// one.
+func (l *rateLimiter) wait(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.refill(key, now)
+	if b.tokens < 1 {
+		return l.untilToken(b)
+	}
+	return 0
+}
+
+// refill returns key's bucket with the tokens it regained since it was last
+// used.
+func (l *rateLimiter) refill(key string, now time.Time) *bucket {
+	if now.Sub(l.swept) >= sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+	return b
+}
+
+func (l *rateLimiter) untilToken(b *bucket) time.Duration {
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.swept = now
+}
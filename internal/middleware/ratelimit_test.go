@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/handlers/problem"
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+// step calls the limiter at an offset from the start and expects its answer.
+type step struct {
+	at         time.Duration
+	key        string
+	wait       bool
+	ok         bool
+	retryAfter time.Duration
+}
+
+func TestRateLimiter(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		rule  config.RateLimitRule
+		steps []step
+	}{
+		{
+			name: "burst then refill",
+			rule: config.RateLimitRule{Rate: 2, Burst: 2},
+			steps: []step{
+				{key: "a", ok: true},
+				{key: "a", ok: true},
+				{key: "a", retryAfter: 500 * time.Millisecond},
+				{at: 250 * time.Millisecond, key: "a", retryAfter: 250 * time.Millisecond},
+				{at: 500 * time.Millisecond, key: "a", ok: true},
+				{at: 500 * time.Millisecond, key: "a", retryAfter: 500 * time.Millisecond},
+			},
+		},
+		{
+			name: "refill stops at burst",
+			rule: config.RateLimitRule{Rate: 1, Burst: 2},
+			steps: []step{
+				{key: "a", ok: true},
+				{at: time.Hour, key: "a", ok: true},
+				{at: time.Hour, key: "a", ok: true},
+				{at: time.Hour, key: "a", retryAfter: time.Second},
+			},
+		},
+		{
+			name: "keys have their own buckets",
+			rule: config.RateLimitRule{Rate: 1, Burst: 1},
+			steps: []step{
+				{key: "a", ok: true},
+				{key: "a", retryAfter: time.Second},
+				{key: "b", ok: true},
+			},
+		},
+		{
+			name: "wait does not take a token",
+			rule: config.RateLimitRule{Rate: 1, Burst: 1},
+			steps: []step{
+				{key: "a", wait: true, ok: true},
+				{key: "a", wait: true, ok: true},
+				{key: "a", ok: true},
+				{key: "a", wait: true, retryAfter: time.Second},
+				{at: 400 * time.Millisecond, key: "a", wait: true, retryAfter: 600 * time.Millisecond},
+				{at: time.Second, key: "a", wait: true, ok: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newRateLimiter(tt.rule)
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				var ok bool
+				var retryAfter time.Duration
+				if s.wait {
+					retryAfter = limiter.wait(s.key, now)
+					ok = retryAfter == 0
+				} else {
+					ok, retryAfter = limiter.allow(s.key, now)
+				}
+				if ok != s.ok || retryAfter != s.retryAfter {
+					t.Errorf("step %d: got %v, %v, want %v, %v", i, ok, retryAfter, s.ok, s.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterWithoutRate(t *testing.T) {
+	if limiter := newRateLimiter(config.RateLimitRule{Burst: 5}); limiter != nil {
+		t.Error("a rule without a rate got a limiter")
+	}
+}
+
+func TestRateLimiterSweepsRefilledBuckets(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(config.RateLimitRule{Rate: 0.1, Burst: 10})
+	limiter.allow("idle", start)
+	for range 9 {
+		limiter.allow("drained", start)
+	}
+	limiter.allow("recent", start.Add(50*time.Second))
+	limiter.allow("recent", start.Add(50*time.Second))
+	limiter.allow("new", start.Add(sweepInterval))
+	for key, want := range map[string]bool{"idle": false, "drained": true, "recent": true, "new": true} {
+		if _, ok := limiter.buckets[key]; ok != want {
+			t.Errorf("bucket %q kept = %v, want %v", key, ok, want)
+		}
+	}
+	if !limiter.swept.Equal(start.Add(sweepInterval)) {
+		t.Errorf("swept = %v, want %v", limiter.swept, start.Add(sweepInterval))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := parsePrefixes([]string{"10.0.0.0/8", "192.0.2.1", "not a prefix"})
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{name: "untrusted peer", remoteAddr: "203.0.113.9:1234", forwarded: []string{"198.51.100.7"}, want: "203.0.113.9"},
+		{name: "trusted peer without header", remoteAddr: "10.0.0.1:1234", want: "10.0.0.1"},
+		{name: "trusted peer", remoteAddr: "10.0.0.1:1234", forwarded: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "single trusted address", remoteAddr: "192.0.2.1:1234", forwarded: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "spoofed left-most hop", remoteAddr: "10.0.0.1:1234", forwarded: []string{"1.1.1.1, 198.51.100.7"}, want: "198.51.100.7"},
+		{name: "chain of trusted proxies", remoteAddr: "10.0.0.1:1234", forwarded: []string{"1.1.1.1, 198.51.100.7, 10.0.0.2"}, want: "198.51.100.7"},
+		{name: "repeated headers", remoteAddr: "10.0.0.1:1234", forwarded: []string{"1.1.1.1", "198.51.100.7, 10.0.0.2"}, want: "198.51.100.7"},
+		{name: "only trusted hops", remoteAddr: "10.0.0.1:1234", forwarded: []string{"10.0.0.3"}, want: "10.0.0.3"},
+		{name: "malformed hop", remoteAddr: "10.0.0.1:1234", forwarded: []string{"198.51.100.7, unknown"}, want: "10.0.0.1"},
+		{name: "IPv4-mapped peer", remoteAddr: "[::ffff:10.0.0.1]:1234", forwarded: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "unparsable peer", remoteAddr: "pipe", forwarded: []string{"198.51.100.7"}, want: "pipe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/people", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if got := clientIP(req, trusted); got != tt.want {
+				t.Errorf("clientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddlewareKeys(t *testing.T) {
+	type request struct {
+		remoteAddr string
+		tokenID    int64
+	}
+	tests := []struct {
+		name   string
+		first  request
+		second request
+		status int
+	}{
+		{name: "same IP", first: request{remoteAddr: "198.51.100.7:1"}, second: request{remoteAddr: "198.51.100.7:2"}, status: http.StatusTooManyRequests},
+		{name: "other IP", first: request{remoteAddr: "198.51.100.7:1"}, second: request{remoteAddr: "198.51.100.8:1"}, status: http.StatusOK},
+		{name: "same token from other IPs", first: request{remoteAddr: "198.51.100.7:1", tokenID: 1}, second: request{remoteAddr: "198.51.100.8:1", tokenID: 1}, status: http.StatusTooManyRequests},
+		{name: "other token from the same IP", first: request{remoteAddr: "198.51.100.7:1", tokenID: 1}, second: request{remoteAddr: "198.51.100.7:1", tokenID: 2}, status: http.StatusOK},
+		{name: "token after its IP", first: request{remoteAddr: "198.51.100.7:1"}, second: request{remoteAddr: "198.51.100.7:1", tokenID: 1}, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RateLimitMiddleware(config.RateLimitConfig{Read: config.RateLimitRule{Rate: 0.001, Burst: 1}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			serve := func(req request) *httptest.ResponseRecorder {
+				r := httptest.NewRequest(http.MethodGet, "/api/people", nil)
+				r.RemoteAddr = req.remoteAddr
+				if req.tokenID != 0 {
+					r = r.WithContext(services.ContextWithToken(r.Context(), &models.APIToken{ID: req.tokenID}))
+				}
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, r)
+				return recorder
+			}
+			if recorder := serve(tt.first); recorder.Code != http.StatusOK {
+				t.Fatalf("first status = %d, want 200", recorder.Code)
+			}
+			recorder := serve(tt.second)
+			if recorder.Code != tt.status {
+				t.Fatalf("second status = %d, want %d", recorder.Code, tt.status)
+			}
+			if tt.status == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") == "" {
+				t.Error("429 response has no Retry-After header")
+			}
+		})
+	}
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		streamed bool
+		status   int
+		reached  bool
+	}{
+		{name: "within the limit", path: "/api/people", body: strings.Repeat("a", 10), status: http.StatusOK, reached: true},
+		{name: "declared length over the limit", path: "/api/people", body: strings.Repeat("a", 11), status: http.StatusRequestEntityTooLarge},
+		{name: "streamed body over the limit", path: "/api/people", body: strings.Repeat("a", 11), streamed: true, status: http.StatusRequestEntityTooLarge, reached: true},
+		{name: "import within its limit", path: "/api/import/csv", body: strings.Repeat("a", 100), status: http.StatusOK, reached: true},
+		{name: "import over its limit", path: "/api/import", body: strings.Repeat("a", 101), status: http.StatusRequestEntityTooLarge},
+		{name: "streamed import over its limit", path: "/api/import/csv", body: strings.Repeat("a", 101), streamed: true, status: http.StatusRequestEntityTooLarge, reached: true},
+		{name: "import prefix of another path", path: "/api/imports", body: strings.Repeat("a", 11), status: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := BodyLimitMiddleware(10, 100)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				if _, err := io.ReadAll(r.Body); err != nil {
+					problem.WriteError(w, r, err)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.streamed {
+				body = io.MultiReader(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, tt.path, body)
+			if tt.streamed {
+				req.ContentLength = -1
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != tt.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.status)
+			}
+			if reached != tt.reached {
+				t.Errorf("handler reached = %v, want %v", reached, tt.reached)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+// Package migrate applies the embedded schema migrations. It records the
+// schema version in the same schema_migrations table as the golang-migrate
+// CLI, so databases migrated with either tool stay interchangeable.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/migrations"
+)
+
+// advisoryLockID identifies the PostgreSQL advisory lock held while
+// migrating, so concurrently starting instances apply migrations one at a time.
+const advisoryLockID = 0x7063726d
+
+// State is the schema version recorded in schema_migrations. Version 0 means
+// no migration has been applied.
+type State struct {
+	Version int64
+	Dirty   bool
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+	lock       func(ctx context.Context, conn *sqlx.Conn) (unlock func(), err error)
+	// check, when set, verifies a migration's changes before they commit.
+	check func(ctx context.Context, tx *sqlx.Tx) error
+	// tableExists reports whether schema_migrations has been created.
+	tableExists string
+}
+
+// New returns a migrator for the migrations embedded for db's driver.
+func New(db *sqlx.DB) (*Migrator, error) {
+	migrator := &Migrator{db: db}
+	switch db.DriverName() {
+	case config.DriverPostgres:
+		migrator.lock = lockPostgres
+		migrator.tableExists = `SELECT to_regclass('schema_migrations') IS NOT NULL`
+	case config.DriverSQLite:
+		migrator.lock = lockSQLite
+		migrator.check = checkSQLite
+		migrator.tableExists = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')`
+	default:
+		return nil, fmt.Errorf("no migrations for database driver %q", db.DriverName())
+	}
+	loaded, err := loadMigrations(migrations.FS, db.DriverName())
+	if err != nil {
+		return nil, err
+	}
+	migrator.migrations = loaded
+	return migrator, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Latest returns the version of the newest embedded migration.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration and returns how many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		for {
+			ok, err := m.step(ctx, conn, func(tx *sqlx.Tx, state State) (bool, error) {
+				next := m.next(state.Version)
+				if next == nil {
+					return false, nil
+				}
+				return true, applyScript(ctx, tx, next.Version, next.up, next.Version)
+			})
+			if err != nil || !ok {
+				return err
+			}
+			applied++
+		}
+	})
+	return applied, err
+}
+
+// Down reverts up to steps migrations and returns how many were reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		for reverted < steps {
+			ok, err := m.step(ctx, conn, func(tx *sqlx.Tx, state State) (bool, error) {
+				if state.Version == 0 {
+					return false, nil
+				}
+				current := m.find(state.Version)
+				if current == nil {
+					return false, fmt.Errorf("no migration found for version %d", state.Version)
+				}
+				if current.down == "" {
+					return false, fmt.Errorf("migration %d has no down script", current.Version)
+				}
+				return true, applyScript(ctx, tx, current.Version, current.down, m.previous(current.Version))
+			})
+			if err != nil || !ok {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force records version as the current, clean schema version without running
+// any migration, to recover from a failed migration that left it dirty.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("no migration found for version %d", version)
+	}
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		tx, err := conn.BeginTxx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		if err := writeState(ctx, tx, version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) (State, error) {
+	var state State
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		var err error
+		state, err = readState(ctx, conn)
+		return err
+	})
+	return state, err
+}
+
+// Version reads the schema version without taking the migration lock or
+// creating the version table, so it is cheap enough for health checks and
+// does not wait for a running migration.
+func (m *Migrator) Version(ctx context.Context) (State, error) {
+	var exists bool
+	if err := m.db.GetContext(ctx, &exists, m.tableExists); err != nil {
+		return State{}, fmt.Errorf("failed to look up schema_migrations table: %w", err)
+	}
+	if !exists {
+		return State{}, nil
+	}
+	return readState(ctx, m.db)
+}
+
+// withLock runs fn on a dedicated connection while holding the migration
+// lock, after making sure the version table exists.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return fn(conn)
+}
+
+// step runs fn in a transaction with the version state read inside it, so
+// the decision what to migrate next is never based on a stale version. The
+// transaction commits only when fn reports that it applied a change.
+func (m *Migrator) step(ctx context.Context, conn *sqlx.Conn, fn func(tx *sqlx.Tx, state State) (bool, error)) (bool, error) {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	state, err := readState(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	if state.Dirty {
+		return false, fmt.Errorf("schema is dirty at version %d, fix it manually and force the version", state.Version)
+	}
+	ok, err := fn(tx, state)
+	if err != nil || !ok {
+		return false, err
+	}
+	if m.check != nil {
+		if err := m.check(ctx, tx); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return true, nil
+}
+
+func applyScript(ctx context.Context, tx *sqlx.Tx, version int64, script string, targetVersion int64) error {
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("failed to run migration %d: %w", version, err)
+	}
+	return writeState(ctx, tx, targetVersion)
+}
+
+func (m *Migrator) next(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version > version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) previous(version int64) int64 {
+	var previous int64
+	for _, migration := range m.migrations {
+		if migration.Version < version {
+			previous = migration.Version
+		}
+	}
+	return previous
+}
+
+func readState(ctx context.Context, q sqlx.QueryerContext) (State, error) {
+	var state State
+	err := q.QueryRowxContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&state.Version, &state.Dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return State{}, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return state, nil
+}
+
+// writeState replaces the single schema_migrations row, removing it for
+// version 0 as golang-migrate does.
+func writeState(ctx context.Context, tx *sqlx.Tx, version int64) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return fmt.Errorf("failed to clear schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)`), version, false); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return nil
+}
+
+func lockPostgres(ctx context.Context, conn *sqlx.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	return func() {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
+	}, nil
+}
+
+// lockSQLite relies on SQLite's database-wide write lock: every step runs in
+// an immediate transaction and re-reads the version inside it, so concurrent
+// migrators never apply the same migration twice. It also turns foreign keys
+// off on the migration connection, which SQLite only allows outside a
+// transaction, so that migrations can rebuild a table without DROP TABLE
+// cascading to the rows that reference it. checkSQLite enforces them instead.
+func lockSQLite(ctx context.Context, conn *sqlx.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return nil, fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	return func() {
+		conn.ExecContext(context.Background(), `PRAGMA foreign_keys = ON`)
+	}, nil
+}
+
+// checkSQLite fails a migration that leaves a row referencing a missing
+// record, which SQLite does not prevent while foreign keys are off.
+func checkSQLite(ctx context.Context, tx *sqlx.Tx) error {
+	rows, err := tx.QueryContext(ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var foreignKey int64
+		if err := rows.Scan(&table, &rowID, &parent, &foreignKey); err != nil {
+			return fmt.Errorf("failed to check foreign keys: %w", err)
+		}
+		return fmt.Errorf("%s row %d references a missing %s record", table, rowID.Int64, parent)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,114 @@
+package migrate_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/migrate"
+)
+
+func newSQLiteMigrator(t *testing.T) (*sqlx.DB, *migrate.Migrator) {
+	t.Helper()
+	db, err := config.NewDatabase(config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "pcrm.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, migrator
+}
+
+// migrateTo moves the schema to version by migrating all the way up and then
+// down again, as the migrator only migrates up to the latest version.
+func migrateTo(t *testing.T, migrator *migrate.Migrator, version int64) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Down(ctx, int(migrator.Latest()-version)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exec(t *testing.T, db *sqlx.DB, query string) {
+	t.Helper()
+	if _, err := db.Exec(query); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
+
+func count(t *testing.T, db *sqlx.DB, query string) int {
+	t.Helper()
+	var n int
+	if err := db.Get(&n, query); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestSQLiteMigrationsRoundTrip(t *testing.T) {
+	_, migrator := newSQLiteMigrator(t)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Down(ctx, int(migrator.Latest())); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	state, err := migrator.Version(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != migrator.Latest() || state.Dirty {
+		t.Errorf("state = %+v, want version %d", state, migrator.Latest())
+	}
+}
+
+func TestSQLiteUsersMigrationKeepsData(t *testing.T) {
+	db, migrator := newSQLiteMigrator(t)
+	migrateTo(t, migrator, 3)
+	exec(t, db, `INSERT INTO people (id, first_name) VALUES (1, 'Bob'), (2, 'Eve')`)
+	exec(t, db, `DELETE FROM people WHERE id = 2`)
+	exec(t, db, `INSERT INTO contacts (person_id, contact_type_id, content) VALUES (1, 1, 'bob@example.com')`)
+	exec(t, db, `INSERT INTO api_tokens (name, token_hash, scope) VALUES ('cli', 'hash', 'read')`)
+	migrateTo(t, migrator, 4)
+	if n := count(t, db, `SELECT COUNT(*) FROM contacts`); n != 1 {
+		t.Fatalf("%d contacts after the users migration, want 1", n)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM people p JOIN users u ON u.id = p.user_id WHERE u.username = 'owner'`); n != 1 {
+		t.Errorf("%d people belong to the owner, want 1", n)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM api_tokens t JOIN users u ON u.id = t.user_id WHERE u.username = 'owner'`); n != 1 {
+		t.Errorf("%d tokens belong to the owner, want 1", n)
+	}
+	for _, table := range []string{"people", "api_tokens"} {
+		if n := count(t, db, `SELECT "notnull" FROM pragma_table_info('`+table+`') WHERE name = 'user_id'`); n != 1 {
+			t.Errorf("%s.user_id is nullable", table)
+		}
+	}
+	exec(t, db, `INSERT INTO people (first_name, user_id) VALUES ('Carol', 1)`)
+	if n := count(t, db, `SELECT MAX(id) FROM people`); n != 3 {
+		t.Errorf("new person got id %d, want 3 after the deleted person 2", n)
+	}
+	migrateTo(t, migrator, 3)
+	if n := count(t, db, `SELECT COUNT(*) FROM contacts`); n != 1 {
+		t.Errorf("%d contacts after reverting the users migration, want 1", n)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM pragma_table_info('people') WHERE name = 'user_id'`); n != 0 {
+		t.Error("people.user_id is still there after reverting the users migration")
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM sqlite_master WHERE name = 'users'`); n != 0 {
+		t.Error("users table is still there after reverting the users migration")
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+// Archive is a complete copy of a user's records. Records keep the ids they
+// had when exported and refer to each other by those ids.
+type Archive struct {
+	ContactTypes      []ContactType
+	ConversationTypes []ConversationType
+	People            []Person
+	Contacts          []Contact
+	ConnectionSources []ConnectionSource
+	BirthDateInfo     []BirthDateInfo
+	Conversations     []Conversation
+	// Tags holds the tags of the people that have any, by person id.
+	Tags map[int64][]string
+}
+
+// ImportResult counts the records an import created and maps the archive
+// id of every imported person to the id it was stored under.
+type ImportResult struct {
+	DryRun            bool
+	ContactTypes      int
+	ConversationTypes int
+	People            int
+	Contacts          int
+	ConnectionSources int
+	BirthDateInfo     int
+	Conversations     int
+	PersonIDs         map[int64]int64
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Chat is the history of a chat between the user and one person read from a
+// messenger export.
+type Chat struct {
+	// App names the messenger in the notes of the conversations.
+	App string
+	// PersonName is how the export names the other person.
+	PersonName string
+	Messages   []ChatMessage
+}
+
+// ChatMessage is one message of a chat. Only who sent it and when matter.
+type ChatMessage struct {
+	SentAt    time.Time
+	FromOwner bool
+}
+
+// ChatDay sums up the messages of a chat sent on one calendar day, which
+// become one conversation.
+type ChatDay struct {
+	FirstMessageAt time.Time
+	LastMessageAt  time.Time
+	// Initiator is the sender of the first message of the day.
+	Initiator      string
+	OwnerMessages  int
+	PersonMessages int
+	Notes          string
+}
+
+// ChatImport tells what importing a chat did with each of its days, oldest
+// first, and counts the outcomes.
+type ChatImport struct {
+	Days     []ChatDayImport
+	Imported int
+	Skipped  int
+}
+
+// ChatDayImport is the outcome of importing a day of a chat.
+// ConversationID is the conversation the day was logged as, and zero when
+// it was skipped or only checked on a dry run.
+type ChatDayImport struct {
+	ChatDay
+	ConversationID int64
+	Skipped        bool
+}
@@ -16,6 +16,8 @@ type Conversation struct {
     ConversationTypeID  int64             `json:"conversationTypeId" db:"conversation_type_id"`
     Initiator           string            `json:"initiator" db:"initiator"`
     Notes               string            `json:"notes" db:"notes"`
+    // MessageID is the Message-ID of the email the conversation was logged from.
+    MessageID           *string           `json:"messageId,omitempty" db:"message_id"`
     CreatedAt           time.Time         `json:"createdAt" db:"created_at"`
     UpdatedAt           time.Time         `json:"updatedAt" db:"updated_at"`
     ConversationType    ConversationType  `json:"conversationType" db:"conversation_type"`
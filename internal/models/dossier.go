@@ -0,0 +1,19 @@
+package models
+
+// Dossier is a person's profile together with the people it links to,
+// gathered for exporting as a note.
+type Dossier struct {
+	Profile PersonProfile
+	// Introducer is the stored person who introduced the user to this one.
+	Introducer *Person
+	// Introduced lists the people this person introduced the user to.
+	Introduced []Person
+	// IntroducedAlongside lists the other people the introducer introduced
+	// the user to.
+	IntroducedAlongside []Person
+	// Colleagues lists the other people at the same company.
+	Colleagues []Person
+	// FollowUps lists the conversations the person started that the user has
+	// not reached out since, most recent first.
+	FollowUps []Conversation
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Email is the header of an email to log as conversations with the people it
+// was exchanged with. Addresses are lowercase.
+type Email struct {
+	MessageID  string
+	Subject    string
+	Date       time.Time
+	From       string
+	Recipients []string
+}
+
+// EmailImport tells what importing an email did: the conversations it
+// logged, one for each person it was sent from or to, and the people who
+// already had it logged.
+type EmailImport struct {
+	Conversations      []Conversation
+	DuplicatePersonIDs []int64
+}
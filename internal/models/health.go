@@ -0,0 +1,8 @@
+package models
+
+// HealthCheck is the outcome of one readiness check; Err is nil when it
+// passed.
+type HealthCheck struct {
+	Name string
+	Err  error
+}
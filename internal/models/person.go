@@ -6,9 +6,12 @@ import (
 
 type Person struct {
 	ID         int64     `db:"id"`
+	UserID     int64     `db:"user_id"`
 	FirstName  string    `db:"first_name"`
 	SecondName *string   `db:"second_name"`
 	MiddleName *string   `db:"middle_name"`
+	Company    *string   `db:"company"`
+	JobTitle   *string   `db:"job_title"`
 	CreatedAt  time.Time `db:"created_at"`
 	UpdatedAt  time.Time `db:"updated_at"`
 }
@@ -0,0 +1,29 @@
+package models
+
+// PersonProfile aggregates a person with the records that describe them.
+type PersonProfile struct {
+	Person           Person
+	Contacts         []Contact
+	ConnectionSource *ConnectionSource
+	BirthDateInfo    *BirthDateInfo
+	Conversations    []Conversation
+	Tags             []string
+}
+
+// ProfileImport tells what importing profiles did with each of them, in
+// order, and counts the outcomes.
+type ProfileImport struct {
+	Profiles []ProfileImportResult
+	Imported int
+	Skipped  int
+	Invalid  int
+}
+
+// ProfileImportResult is the outcome of importing one profile. PersonID is
+// the person the profile was stored as, and zero when it was invalid,
+// skipped or only checked on a dry run.
+type ProfileImportResult struct {
+	PersonID int64
+	Skipped  bool
+	Invalid  bool
+}
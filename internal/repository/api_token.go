@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type sqlAPITokenRepository struct {
+	db *Executor
+}
+
+func NewAPITokenRepository(db *Executor) APITokenRepository {
+	return &sqlAPITokenRepository{db: db}
+}
+
+func (r *sqlAPITokenRepository) List(ctx context.Context) ([]models.APIToken, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []models.APIToken
+	query := `
+		SELECT id, user_id, name, token_hash, scope, expires_at, last_used_at, created_at
+		FROM api_tokens
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`
+
+	if err := r.db.selectAll(ctx, "APITokenRepository.List", &tokens, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *sqlAPITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	query := `
+		SELECT id, user_id, name, token_hash, scope, expires_at, last_used_at, created_at
+		FROM api_tokens
+		WHERE token_hash = ?
+	`
+
+	if err := r.db.get(ctx, "APITokenRepository.GetByHash", &token, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &NotFoundError{Resource: "api token"}
+		}
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *sqlAPITokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+	token.UserID = userID
+
+	query := `
+		INSERT INTO api_tokens (user_id, name, token_hash, scope, expires_at)
+		VALUES (:user_id, :name, :token_hash, :scope, :expires_at)
+		RETURNING id, created_at
+	`
+
+	if err := r.db.namedQueryRow(ctx, "APITokenRepository.Create", query, token, &token.ID, &token.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqlAPITokenRepository) UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	query := `UPDATE api_tokens SET last_used_at = ? WHERE id = ?`
+
+	if _, err := r.db.exec(ctx, "APITokenRepository.UpdateLastUsed", query, lastUsedAt.UTC(), id); err != nil {
+		return fmt.Errorf("failed to update api token last use: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqlAPITokenRepository) Delete(ctx context.Context, id int64) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM api_tokens WHERE id = ? AND user_id = ?`
+
+	result, err := r.db.exec(ctx, "APITokenRepository.Delete", query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete api token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound("api token", "id", id)
+	}
+
+	return nil
+}
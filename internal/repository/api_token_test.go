@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+func TestGetAPITokenByHash(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	ctx := context.Background()
+	tokens := NewAPITokenRepository(executor)
+	created := &models.APIToken{Name: "laptop", TokenHash: "hash", Scope: models.TokenScopeRead}
+	if err := tokens.Create(auth.WithUserID(ctx, 1), created); err != nil {
+		t.Fatal(err)
+	}
+	token, err := tokens.GetByHash(ctx, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ID != created.ID || token.UserID != 1 {
+		t.Errorf("token = %+v, want the one created for user 1", token)
+	}
+	token, err = tokens.GetByHash(ctx, "unknown")
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) || token != nil {
+		t.Errorf("GetByHash = %+v, %v, want a not found error", token, err)
+	}
+	if err.Error() != "api token not found" {
+		t.Errorf("err = %q, want the hash left out", err)
+	}
+}
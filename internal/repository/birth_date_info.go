@@ -1,32 +1,38 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/lincentpega/pcrm/internal/models"
 )
 
-type BirthDateInfoRepository struct {
-	db *sqlx.DB
+type sqlBirthDateInfoRepository struct {
+	db *Executor
 }
 
-func NewBirthDateInfoRepository(db *sqlx.DB) *BirthDateInfoRepository {
-	return &BirthDateInfoRepository{db: db}
+func NewBirthDateInfoRepository(db *Executor) BirthDateInfoRepository {
+	return &sqlBirthDateInfoRepository{db: db}
 }
 
-func (r *BirthDateInfoRepository) GetByPersonID(personID int64) (*models.BirthDateInfo, error) {
+func (r *sqlBirthDateInfoRepository) GetByPersonID(ctx context.Context, personID int64) (*models.BirthDateInfo, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var birthDateInfo models.BirthDateInfo
 	query := `
 		SELECT id, person_id, birth_year, birth_month, birth_day,
 		       approximate_age, approximate_age_updated_at, created_at, updated_at
 		FROM birth_date_info
-		WHERE person_id = $1
+		WHERE person_id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)
 	`
 
-	if err := r.db.Get(&birthDateInfo, query, personID); err != nil {
+	if err := r.db.get(ctx, "BirthDateInfoRepository.GetByPersonID", &birthDateInfo, query, personID, userID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -36,7 +42,11 @@ func (r *BirthDateInfoRepository) GetByPersonID(personID int64) (*models.BirthDa
 	return &birthDateInfo, nil
 }
 
-func (r *BirthDateInfoRepository) Create(birthDateInfo *models.BirthDateInfo) error {
+func (r *sqlBirthDateInfoRepository) Create(ctx context.Context, birthDateInfo *models.BirthDateInfo) error {
+	if err := requirePerson(ctx, r.db, "BirthDateInfoRepository.Create", birthDateInfo.PersonID); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO birth_date_info (person_id, birth_year, birth_month, birth_day,
 		                            approximate_age, approximate_age_updated_at)
@@ -45,47 +55,42 @@ func (r *BirthDateInfoRepository) Create(birthDateInfo *models.BirthDateInfo) er
 		RETURNING id, created_at, updated_at
 	`
 
-	rows, err := r.db.NamedQuery(query, birthDateInfo)
-	if err != nil {
+	if err := r.db.namedQueryRow(ctx, "BirthDateInfoRepository.Create", query, birthDateInfo, &birthDateInfo.ID, &birthDateInfo.CreatedAt, &birthDateInfo.UpdatedAt); err != nil {
 		return fmt.Errorf("failed to create birth date info: %w", err)
 	}
-	defer rows.Close()
-
-	if rows.Next() {
-		if err := rows.Scan(&birthDateInfo.ID, &birthDateInfo.CreatedAt, &birthDateInfo.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan created birth date info: %w", err)
-		}
-	}
 
 	return nil
 }
 
-func (r *BirthDateInfoRepository) Update(birthDateInfo *models.BirthDateInfo) error {
+func (r *sqlBirthDateInfoRepository) Update(ctx context.Context, birthDateInfo *models.BirthDateInfo) error {
+	if err := requirePerson(ctx, r.db, "BirthDateInfoRepository.Update", birthDateInfo.PersonID); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE birth_date_info 
 		SET birth_year = :birth_year, birth_month = :birth_month, birth_day = :birth_day,
 		    approximate_age = :approximate_age, approximate_age_updated_at = :approximate_age_updated_at,
-		    updated_at = NOW()
+		    updated_at = CURRENT_TIMESTAMP
 		WHERE person_id = :person_id
-		RETURNING id, updated_at
+		RETURNING id, created_at, updated_at
 	`
 
-	rows, err := r.db.NamedQuery(query, birthDateInfo)
-	if err != nil {
-		return fmt.Errorf("failed to update birth date info: %w", err)
-	}
-	defer rows.Close()
-
-	if rows.Next() {
-		if err := rows.Scan(&birthDateInfo.ID, &birthDateInfo.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan updated birth date info: %w", err)
+	if err := r.db.namedQueryRow(ctx, "BirthDateInfoRepository.Update", query, birthDateInfo, &birthDateInfo.ID, &birthDateInfo.CreatedAt, &birthDateInfo.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound("birth date info", "person id", birthDateInfo.PersonID)
 		}
+		return fmt.Errorf("failed to update birth date info: %w", err)
 	}
 
 	return nil
 }
 
-func (r *BirthDateInfoRepository) Upsert(birthDateInfo *models.BirthDateInfo) error {
+func (r *sqlBirthDateInfoRepository) Upsert(ctx context.Context, birthDateInfo *models.BirthDateInfo) error {
+	if err := requirePerson(ctx, r.db, "BirthDateInfoRepository.Upsert", birthDateInfo.PersonID); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO birth_date_info (person_id, birth_year, birth_month, birth_day,
 		                            approximate_age, approximate_age_updated_at)
@@ -97,29 +102,26 @@ func (r *BirthDateInfoRepository) Upsert(birthDateInfo *models.BirthDateInfo) er
 		    birth_day = EXCLUDED.birth_day,
 		    approximate_age = EXCLUDED.approximate_age,
 		    approximate_age_updated_at = EXCLUDED.approximate_age_updated_at,
-		    updated_at = NOW()
+		    updated_at = CURRENT_TIMESTAMP
 		RETURNING id, created_at, updated_at
 	`
 
-	rows, err := r.db.NamedQuery(query, birthDateInfo)
-	if err != nil {
+	if err := r.db.namedQueryRow(ctx, "BirthDateInfoRepository.Upsert", query, birthDateInfo, &birthDateInfo.ID, &birthDateInfo.CreatedAt, &birthDateInfo.UpdatedAt); err != nil {
 		return fmt.Errorf("failed to upsert birth date info: %w", err)
 	}
-	defer rows.Close()
-
-	if rows.Next() {
-		if err := rows.Scan(&birthDateInfo.ID, &birthDateInfo.CreatedAt, &birthDateInfo.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan upserted birth date info: %w", err)
-		}
-	}
 
 	return nil
 }
 
-func (r *BirthDateInfoRepository) Delete(personID int64) error {
-	query := `DELETE FROM birth_date_info WHERE person_id = $1`
+func (r *sqlBirthDateInfoRepository) Delete(ctx context.Context, personID int64) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
 
-	result, err := r.db.Exec(query, personID)
+	query := `DELETE FROM birth_date_info WHERE person_id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)`
+
+	result, err := r.db.exec(ctx, "BirthDateInfoRepository.Delete", query, personID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete birth date info: %w", err)
 	}
@@ -130,8 +132,12 @@ func (r *BirthDateInfoRepository) Delete(personID int64) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("birth date info for person %d not found", personID)
+		return notFound("birth date info", "person id", personID)
 	}
 
 	return nil
 }
+
+func (r *sqlBirthDateInfoRepository) RestoreTimestamps(ctx context.Context, personID int64, createdAt, updatedAt time.Time) error {
+	return restoreTimestamps(ctx, r.db, "BirthDateInfoRepository.RestoreTimestamps", "birth_date_info", "person_id", "birth date info", personID, createdAt, updatedAt)
+}
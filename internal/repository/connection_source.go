@@ -1,125 +1,192 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/lincentpega/pcrm/internal/encryption"
 	"github.com/lincentpega/pcrm/internal/models"
 )
 
-type ConnectionSourceRepository struct {
-	db *sqlx.DB
+type sqlConnectionSourceRepository struct {
+	db   *Executor
+	keys *encryption.Keyring
 }
 
-func NewConnectionSourceRepository(db *sqlx.DB) *ConnectionSourceRepository {
-	return &ConnectionSourceRepository{db: db}
+// NewConnectionSourceRepository returns a repository that encrypts meeting
+// stories with keys, or stores them in plaintext when keys has no active key.
+func NewConnectionSourceRepository(db *Executor, keys *encryption.Keyring) ConnectionSourceRepository {
+	return &sqlConnectionSourceRepository{db: db, keys: keys}
 }
 
-func (r *ConnectionSourceRepository) GetByPersonID(personID int64) (*models.ConnectionSource, error) {
-	var connectionSource models.ConnectionSource
+type connectionSourceRow struct {
+	models.ConnectionSource
+	envelope
+}
+
+func (row *connectionSourceRow) sealedFields() []sealedField {
+	return []sealedField{{"connection_sources.meeting_story", row.MeetingStory}}
+}
+
+func (r *sqlConnectionSourceRepository) GetByPersonID(ctx context.Context, personID int64) (*models.ConnectionSource, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var row connectionSourceRow
 	query := `
-		SELECT id, person_id, meeting_story, meeting_timestamp, was_introduced,
+		SELECT id, person_id, meeting_story, key_id, wrapped_key, meeting_timestamp, was_introduced,
 		       introducer_person_id, introducer_name, created_at, updated_at
 		FROM connection_sources
-		WHERE person_id = $1
+		WHERE person_id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)
 	`
 	
-	if err := r.db.Get(&connectionSource, query, personID); err != nil {
+	if err := r.db.get(ctx, "ConnectionSourceRepository.GetByPersonID", &row, query, personID, userID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get connection source for person %d: %w", personID, err)
 	}
 	
-	return &connectionSource, nil
+	if err := openFields(r.keys, row.envelope, row.sealedFields()...); err != nil {
+		return nil, fmt.Errorf("failed to decrypt connection source for person %d: %w", personID, err)
+	}
+
+	return &row.ConnectionSource, nil
 }
 
-func (r *ConnectionSourceRepository) Create(connectionSource *models.ConnectionSource) error {
+func (r *sqlConnectionSourceRepository) GetByIntroducerPersonID(ctx context.Context, introducerPersonID int64) ([]models.ConnectionSource, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []connectionSourceRow
 	query := `
-		INSERT INTO connection_sources (person_id, meeting_story, meeting_timestamp, was_introduced,
-		                               introducer_person_id, introducer_name)
-		VALUES (:person_id, :meeting_story, :meeting_timestamp, :was_introduced,
-		        :introducer_person_id, :introducer_name)
-		RETURNING id, created_at, updated_at
+		SELECT id, person_id, meeting_story, key_id, wrapped_key, meeting_timestamp, was_introduced,
+		       introducer_person_id, introducer_name, created_at, updated_at
+		FROM connection_sources
+		WHERE introducer_person_id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)
+		ORDER BY person_id
 	`
-	
-	rows, err := r.db.NamedQuery(query, connectionSource)
+
+	if err := r.db.selectAll(ctx, "ConnectionSourceRepository.GetByIntroducerPersonID", &rows, query, introducerPersonID, userID); err != nil {
+		return nil, fmt.Errorf("failed to get connection sources introduced by person %d: %w", introducerPersonID, err)
+	}
+
+	connectionSources := make([]models.ConnectionSource, len(rows))
+	for i := range rows {
+		if err := openFields(r.keys, rows[i].envelope, rows[i].sealedFields()...); err != nil {
+			return nil, fmt.Errorf("failed to decrypt connection source for person %d: %w", rows[i].PersonID, err)
+		}
+		connectionSources[i] = rows[i].ConnectionSource
+	}
+
+	return connectionSources, nil
+}
+
+func (r *sqlConnectionSourceRepository) Create(ctx context.Context, connectionSource *models.ConnectionSource) error {
+	if err := r.checkOwnership(ctx, "ConnectionSourceRepository.Create", connectionSource); err != nil {
+		return err
+	}
+
+	row, err := r.seal(connectionSource)
 	if err != nil {
-		return fmt.Errorf("failed to create connection source: %w", err)
+		return err
 	}
-	defer rows.Close()
+
+	query := `
+		INSERT INTO connection_sources (person_id, meeting_story, key_id, wrapped_key, meeting_timestamp,
+		                               was_introduced, introducer_person_id, introducer_name)
+		VALUES (:person_id, :meeting_story, :key_id, :wrapped_key, :meeting_timestamp,
+		        :was_introduced, :introducer_person_id, :introducer_name)
+		RETURNING id, created_at, updated_at
+	`
 	
-	if rows.Next() {
-		if err := rows.Scan(&connectionSource.ID, &connectionSource.CreatedAt, &connectionSource.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan created connection source: %w", err)
-		}
+	if err := r.db.namedQueryRow(ctx, "ConnectionSourceRepository.Create", query, row, &connectionSource.ID, &connectionSource.CreatedAt, &connectionSource.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create connection source: %w", err)
 	}
 	
 	return nil
 }
 
-func (r *ConnectionSourceRepository) Update(connectionSource *models.ConnectionSource) error {
+func (r *sqlConnectionSourceRepository) Update(ctx context.Context, connectionSource *models.ConnectionSource) error {
+	if err := r.checkOwnership(ctx, "ConnectionSourceRepository.Update", connectionSource); err != nil {
+		return err
+	}
+
+	row, err := r.seal(connectionSource)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE connection_sources 
-		SET meeting_story = :meeting_story, meeting_timestamp = :meeting_timestamp,
+		SET meeting_story = :meeting_story, key_id = :key_id, wrapped_key = :wrapped_key,
+		    meeting_timestamp = :meeting_timestamp,
 		    was_introduced = :was_introduced, introducer_person_id = :introducer_person_id,
-		    introducer_name = :introducer_name, updated_at = NOW()
+		    introducer_name = :introducer_name, updated_at = CURRENT_TIMESTAMP
 		WHERE person_id = :person_id
-		RETURNING id, updated_at
+		RETURNING id, created_at, updated_at
 	`
 	
-	rows, err := r.db.NamedQuery(query, connectionSource)
-	if err != nil {
-		return fmt.Errorf("failed to update connection source: %w", err)
-	}
-	defer rows.Close()
-	
-	if rows.Next() {
-		if err := rows.Scan(&connectionSource.ID, &connectionSource.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan updated connection source: %w", err)
+	if err := r.db.namedQueryRow(ctx, "ConnectionSourceRepository.Update", query, row, &connectionSource.ID, &connectionSource.CreatedAt, &connectionSource.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound("connection source", "person id", connectionSource.PersonID)
 		}
+		return fmt.Errorf("failed to update connection source: %w", err)
 	}
 	
 	return nil
 }
 
-func (r *ConnectionSourceRepository) Upsert(connectionSource *models.ConnectionSource) error {
+func (r *sqlConnectionSourceRepository) Upsert(ctx context.Context, connectionSource *models.ConnectionSource) error {
+	if err := r.checkOwnership(ctx, "ConnectionSourceRepository.Upsert", connectionSource); err != nil {
+		return err
+	}
+
+	row, err := r.seal(connectionSource)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO connection_sources (person_id, meeting_story, meeting_timestamp, was_introduced,
-		                               introducer_person_id, introducer_name)
-		VALUES (:person_id, :meeting_story, :meeting_timestamp, :was_introduced,
-		        :introducer_person_id, :introducer_name)
+		INSERT INTO connection_sources (person_id, meeting_story, key_id, wrapped_key, meeting_timestamp,
+		                               was_introduced, introducer_person_id, introducer_name)
+		VALUES (:person_id, :meeting_story, :key_id, :wrapped_key, :meeting_timestamp,
+		        :was_introduced, :introducer_person_id, :introducer_name)
 		ON CONFLICT (person_id) DO UPDATE SET
 		    meeting_story = EXCLUDED.meeting_story,
+		    key_id = EXCLUDED.key_id,
+		    wrapped_key = EXCLUDED.wrapped_key,
 		    meeting_timestamp = EXCLUDED.meeting_timestamp,
 		    was_introduced = EXCLUDED.was_introduced,
 		    introducer_person_id = EXCLUDED.introducer_person_id,
 		    introducer_name = EXCLUDED.introducer_name,
-		    updated_at = NOW()
+		    updated_at = CURRENT_TIMESTAMP
 		RETURNING id, created_at, updated_at
 	`
 	
-	rows, err := r.db.NamedQuery(query, connectionSource)
-	if err != nil {
+	if err := r.db.namedQueryRow(ctx, "ConnectionSourceRepository.Upsert", query, row, &connectionSource.ID, &connectionSource.CreatedAt, &connectionSource.UpdatedAt); err != nil {
 		return fmt.Errorf("failed to upsert connection source: %w", err)
 	}
-	defer rows.Close()
-	
-	if rows.Next() {
-		if err := rows.Scan(&connectionSource.ID, &connectionSource.CreatedAt, &connectionSource.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan upserted connection source: %w", err)
-		}
-	}
 	
 	return nil
 }
 
-func (r *ConnectionSourceRepository) Delete(personID int64) error {
-	query := `DELETE FROM connection_sources WHERE person_id = $1`
+func (r *sqlConnectionSourceRepository) Delete(ctx context.Context, personID int64) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
 	
-	result, err := r.db.Exec(query, personID)
+	query := `DELETE FROM connection_sources WHERE person_id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)`
+
+	result, err := r.db.exec(ctx, "ConnectionSourceRepository.Delete", query, personID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete connection source: %w", err)
 	}
@@ -130,8 +197,44 @@ func (r *ConnectionSourceRepository) Delete(personID int64) error {
 	}
 	
 	if rowsAffected == 0 {
-		return fmt.Errorf("connection source for person %d not found", personID)
+		return notFound("connection source", "person id", personID)
 	}
 	
 	return nil
+}
+
+func (r *sqlConnectionSourceRepository) checkOwnership(ctx context.Context, name string, connectionSource *models.ConnectionSource) error {
+	if err := requirePerson(ctx, r.db, name, connectionSource.PersonID); err != nil {
+		return err
+	}
+
+	if connectionSource.IntroducerPersonID != nil {
+		return requireReference(ctx, r.db, name, "connection_sources", "introducer_person_id", *connectionSource.IntroducerPersonID)
+	}
+
+	return nil
+}
+
+// seal copies connectionSource into a row with its meeting story encrypted,
+// leaving the caller's plaintext untouched.
+func (r *sqlConnectionSourceRepository) seal(connectionSource *models.ConnectionSource) (*connectionSourceRow, error) {
+	row := &connectionSourceRow{ConnectionSource: *connectionSource}
+	if row.MeetingStory != nil {
+		story := *row.MeetingStory
+		row.MeetingStory = &story
+	}
+
+	if err := sealFields(r.keys, &row.envelope, row.sealedFields()...); err != nil {
+		return nil, err
+	}
+
+	return row, nil
+}
+
+func (r *sqlConnectionSourceRepository) Reencrypt(ctx context.Context, limit int) (int, error) {
+	return reencryptColumn(ctx, r.db, "ConnectionSourceRepository.Reencrypt", r.keys, "connection_sources", "meeting_story", limit)
+}
+
+func (r *sqlConnectionSourceRepository) RestoreTimestamps(ctx context.Context, personID int64, createdAt, updatedAt time.Time) error {
+	return restoreTimestamps(ctx, r.db, "ConnectionSourceRepository.RestoreTimestamps", "connection_sources", "person_id", "connection source", personID, createdAt, updatedAt)
 }
\ No newline at end of file
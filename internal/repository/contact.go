@@ -1,120 +1,228 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/lincentpega/pcrm/internal/encryption"
 	"github.com/lincentpega/pcrm/internal/models"
 )
 
-type ContactRepository struct {
-	db *sqlx.DB
+type sqlContactRepository struct {
+	db   *Executor
+	keys *encryption.Keyring
 }
 
-func NewContactRepository(db *sqlx.DB) *ContactRepository {
-	return &ContactRepository{db: db}
+// NewContactRepository returns a repository that encrypts contact content
+// with keys, or stores it in plaintext when keys has no active key.
+func NewContactRepository(db *Executor, keys *encryption.Keyring) ContactRepository {
+	return &sqlContactRepository{db: db, keys: keys}
 }
 
-func (r *ContactRepository) GetContactTypes() ([]models.ContactType, error) {
+type contactRow struct {
+	models.Contact
+	envelope
+}
+
+func (row *contactRow) sealedFields() []sealedField {
+	return []sealedField{{"contacts.content", &row.Content}}
+}
+
+func (r *sqlContactRepository) GetContactTypes(ctx context.Context) ([]models.ContactType, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var types []models.ContactType
-	query := `SELECT id, name, created_at FROM contact_types ORDER BY name`
+	query := `SELECT id, name, created_at FROM contact_types WHERE user_id IS NULL OR user_id = ? ORDER BY name`
 	
-	if err := r.db.Select(&types, query); err != nil {
+	if err := r.db.selectAll(ctx, "ContactRepository.GetContactTypes", &types, query, userID); err != nil {
 		return nil, fmt.Errorf("failed to get contact types: %w", err)
 	}
 	
 	return types, nil
 }
 
-func (r *ContactRepository) GetByPersonID(personID int64) ([]models.Contact, error) {
-	var contacts []models.Contact
+func (r *sqlContactRepository) CreateContactType(ctx context.Context, contactType *models.ContactType) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO contact_types (name, user_id) VALUES (:name, :user_id) RETURNING id, created_at`
+	args := map[string]any{"name": contactType.Name, "user_id": userID}
+
+	if err := r.db.namedQueryRow(ctx, "ContactRepository.CreateContactType", query, args, &contactType.ID, &contactType.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create contact type: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqlContactRepository) GetByPersonID(ctx context.Context, personID int64) ([]models.Contact, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []contactRow
 	query := `
-		SELECT c.id, c.person_id, c.contact_type_id, c.content, c.created_at, c.updated_at,
+		SELECT c.id, c.person_id, c.contact_type_id, c.content, c.key_id, c.wrapped_key, c.created_at, c.updated_at,
 		       ct.id as "contact_type.id", ct.name as "contact_type.name", ct.created_at as "contact_type.created_at"
 		FROM contacts c
 		JOIN contact_types ct ON c.contact_type_id = ct.id
-		WHERE c.person_id = $1
+		JOIN people p ON c.person_id = p.id
+		WHERE c.person_id = ? AND p.user_id = ?
 		ORDER BY ct.name, c.created_at DESC
 	`
 	
-	if err := r.db.Select(&contacts, query, personID); err != nil {
+	if err := r.db.selectAll(ctx, "ContactRepository.GetByPersonID", &rows, query, personID, userID); err != nil {
 		return nil, fmt.Errorf("failed to get contacts for person %d: %w", personID, err)
 	}
+
+	contacts := make([]models.Contact, len(rows))
+	for i := range rows {
+		if err := openFields(r.keys, rows[i].envelope, rows[i].sealedFields()...); err != nil {
+			return nil, fmt.Errorf("failed to decrypt contact %d: %w", rows[i].ID, err)
+		}
+		contacts[i] = rows[i].Contact
+	}
 	
 	return contacts, nil
 }
 
-func (r *ContactRepository) GetByID(id int64) (*models.Contact, error) {
-	var contact models.Contact
+func (r *sqlContactRepository) GetByContactTypeID(ctx context.Context, contactTypeID int64) ([]models.Contact, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []contactRow
 	query := `
-		SELECT c.id, c.person_id, c.contact_type_id, c.content, c.created_at, c.updated_at,
+		SELECT c.id, c.person_id, c.contact_type_id, c.content, c.key_id, c.wrapped_key, c.created_at, c.updated_at,
 		       ct.id as "contact_type.id", ct.name as "contact_type.name", ct.created_at as "contact_type.created_at"
 		FROM contacts c
 		JOIN contact_types ct ON c.contact_type_id = ct.id
-		WHERE c.id = $1
+		JOIN people p ON c.person_id = p.id
+		WHERE c.contact_type_id = ? AND p.user_id = ?
+		ORDER BY c.id
 	`
 	
-	if err := r.db.Get(&contact, query, id); err != nil {
+	if err := r.db.selectAll(ctx, "ContactRepository.GetByContactTypeID", &rows, query, contactTypeID, userID); err != nil {
+		return nil, fmt.Errorf("failed to get contacts of type %d: %w", contactTypeID, err)
+	}
+
+	contacts := make([]models.Contact, len(rows))
+	for i := range rows {
+		if err := openFields(r.keys, rows[i].envelope, rows[i].sealedFields()...); err != nil {
+			return nil, fmt.Errorf("failed to decrypt contact %d: %w", rows[i].ID, err)
+		}
+		contacts[i] = rows[i].Contact
+	}
+
+	return contacts, nil
+}
+
+func (r *sqlContactRepository) GetByID(ctx context.Context, id int64) (*models.Contact, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var row contactRow
+	query := `
+		SELECT c.id, c.person_id, c.contact_type_id, c.content, c.key_id, c.wrapped_key, c.created_at, c.updated_at,
+		       ct.id as "contact_type.id", ct.name as "contact_type.name", ct.created_at as "contact_type.created_at"
+		FROM contacts c
+		JOIN contact_types ct ON c.contact_type_id = ct.id
+		JOIN people p ON c.person_id = p.id
+		WHERE c.id = ? AND p.user_id = ?
+	`
+
+	if err := r.db.get(ctx, "ContactRepository.GetByID", &row, query, id, userID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
+			return nil, notFound("contact", "id", id)
 		}
 		return nil, fmt.Errorf("failed to get contact by id %d: %w", id, err)
 	}
 	
-	return &contact, nil
+	if err := openFields(r.keys, row.envelope, row.sealedFields()...); err != nil {
+		return nil, fmt.Errorf("failed to decrypt contact %d: %w", id, err)
+	}
+
+	return &row.Contact, nil
 }
 
-func (r *ContactRepository) Create(contact *models.Contact) error {
+func (r *sqlContactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	if err := requirePerson(ctx, r.db, "ContactRepository.Create", contact.PersonID); err != nil {
+		return err
+	}
+	if err := requireType(ctx, r.db, "ContactRepository.Create", "contact_types", contact.ContactTypeID, "contacts", "contact_type_id"); err != nil {
+		return err
+	}
+
+	row := contactRow{Contact: *contact}
+	if err := sealFields(r.keys, &row.envelope, row.sealedFields()...); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO contacts (person_id, contact_type_id, content)
-		VALUES (:person_id, :contact_type_id, :content)
+		INSERT INTO contacts (person_id, contact_type_id, content, key_id, wrapped_key)
+		VALUES (:person_id, :contact_type_id, :content, :key_id, :wrapped_key)
 		RETURNING id, created_at, updated_at
 	`
 	
-	rows, err := r.db.NamedQuery(query, contact)
-	if err != nil {
+	if err := r.db.namedQueryRow(ctx, "ContactRepository.Create", query, row, &contact.ID, &contact.CreatedAt, &contact.UpdatedAt); err != nil {
 		return fmt.Errorf("failed to create contact: %w", err)
 	}
-	defer rows.Close()
-	
-	if rows.Next() {
-		if err := rows.Scan(&contact.ID, &contact.CreatedAt, &contact.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan created contact: %w", err)
-		}
-	}
 	
 	return nil
 }
 
-func (r *ContactRepository) Update(contact *models.Contact) error {
+func (r *sqlContactRepository) Update(ctx context.Context, contact *models.Contact) error {
+	if err := requireRecord(ctx, r.db, "ContactRepository.Update", "contacts", "contact", contact.ID); err != nil {
+		return err
+	}
+	if err := requireType(ctx, r.db, "ContactRepository.Update", "contact_types", contact.ContactTypeID, "contacts", "contact_type_id"); err != nil {
+		return err
+	}
+
+	row := contactRow{Contact: *contact}
+	if err := sealFields(r.keys, &row.envelope, row.sealedFields()...); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE contacts 
-		SET contact_type_id = :contact_type_id, content = :content, updated_at = NOW()
+		SET contact_type_id = :contact_type_id, content = :content,
+		    key_id = :key_id, wrapped_key = :wrapped_key, updated_at = CURRENT_TIMESTAMP
 		WHERE id = :id
-		RETURNING updated_at
+		RETURNING person_id, created_at, updated_at
 	`
 	
-	rows, err := r.db.NamedQuery(query, contact)
-	if err != nil {
-		return fmt.Errorf("failed to update contact: %w", err)
-	}
-	defer rows.Close()
-	
-	if rows.Next() {
-		if err := rows.Scan(&contact.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan updated contact: %w", err)
+	if err := r.db.namedQueryRow(ctx, "ContactRepository.Update", query, row, &contact.PersonID, &contact.CreatedAt, &contact.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound("contact", "id", contact.ID)
 		}
+		return fmt.Errorf("failed to update contact: %w", err)
 	}
 	
 	return nil
 }
 
-func (r *ContactRepository) Delete(id int64) error {
-	query := `DELETE FROM contacts WHERE id = $1`
+func (r *sqlContactRepository) Delete(ctx context.Context, id int64) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
 	
-	result, err := r.db.Exec(query, id)
+	query := `DELETE FROM contacts WHERE id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)`
+
+	result, err := r.db.exec(ctx, "ContactRepository.Delete", query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete contact: %w", err)
 	}
@@ -125,8 +233,16 @@ func (r *ContactRepository) Delete(id int64) error {
 	}
 	
 	if rowsAffected == 0 {
-		return fmt.Errorf("contact with id %d not found", id)
+		return notFound("contact", "id", id)
 	}
 	
 	return nil
+}
+
+func (r *sqlContactRepository) Reencrypt(ctx context.Context, limit int) (int, error) {
+	return reencryptColumn(ctx, r.db, "ContactRepository.Reencrypt", r.keys, "contacts", "content", limit)
+}
+
+func (r *sqlContactRepository) RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error {
+	return restoreTimestamps(ctx, r.db, "ContactRepository.RestoreTimestamps", "contacts", "id", "contact", id, createdAt, updatedAt)
 }
\ No newline at end of file
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+func TestCreateContactTypeNamesAreUniquePerUser(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	ctx := context.Background()
+	if _, err := executor.exec(ctx, "test", `INSERT INTO users (id, username, password_hash) VALUES (2, 'mallory', '')`); err != nil {
+		t.Fatal(err)
+	}
+	contacts := NewContactRepository(executor, nil)
+	for _, userID := range []int64{1, 2} {
+		if err := contacts.CreateContactType(auth.WithUserID(ctx, userID), &models.ContactType{Name: "Signal"}); err != nil {
+			t.Fatalf("user %d: %v", userID, err)
+		}
+	}
+	err := contacts.CreateContactType(auth.WithUserID(ctx, 1), &models.ContactType{Name: "Signal"})
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) || conflictErr.Table != "contact_types" || conflictErr.Field != "name" {
+		t.Errorf("err = %v, want a conflict on contact_types.name", err)
+	}
+}
@@ -1,107 +1,181 @@
 package repository
 
 import (
+    "context"
     "database/sql"
     "errors"
     "fmt"
+    "time"
 
-    "github.com/jmoiron/sqlx"
+    "github.com/lincentpega/pcrm/internal/encryption"
     "github.com/lincentpega/pcrm/internal/models"
 )
 
-type ConversationRepository struct {
-    db *sqlx.DB
+type sqlConversationRepository struct {
+    db   *Executor
+    keys *encryption.Keyring
 }
 
-func NewConversationRepository(db *sqlx.DB) *ConversationRepository {
-    return &ConversationRepository{db: db}
+// NewConversationRepository returns a repository that encrypts notes with
+// keys, or stores them in plaintext when keys has no active key.
+func NewConversationRepository(db *Executor, keys *encryption.Keyring) ConversationRepository {
+    return &sqlConversationRepository{db: db, keys: keys}
 }
 
-func (r *ConversationRepository) GetConversationTypes() ([]models.ConversationType, error) {
+type conversationRow struct {
+    models.Conversation
+    envelope
+}
+
+func (row *conversationRow) sealedFields() []sealedField {
+    return []sealedField{{"conversations.notes", &row.Notes}}
+}
+
+func (r *sqlConversationRepository) GetConversationTypes(ctx context.Context) ([]models.ConversationType, error) {
+    userID, err := ownerID(ctx)
+    if err != nil {
+        return nil, err
+    }
     var types []models.ConversationType
-    query := `SELECT id, name, created_at FROM conversation_types ORDER BY name`
-    if err := r.db.Select(&types, query); err != nil {
+    query := `SELECT id, name, created_at FROM conversation_types WHERE user_id IS NULL OR user_id = ? ORDER BY name`
+    if err := r.db.selectAll(ctx, "ConversationRepository.GetConversationTypes", &types, query, userID); err != nil {
         return nil, fmt.Errorf("failed to get conversation types: %w", err)
     }
     return types, nil
 }
 
-func (r *ConversationRepository) GetByPersonID(personID int64) ([]models.Conversation, error) {
-    var conversations []models.Conversation
+func (r *sqlConversationRepository) CreateConversationType(ctx context.Context, conversationType *models.ConversationType) error {
+    userID, err := ownerID(ctx)
+    if err != nil {
+        return err
+    }
+    query := `INSERT INTO conversation_types (name, user_id) VALUES (:name, :user_id) RETURNING id, created_at`
+    args := map[string]any{"name": conversationType.Name, "user_id": userID}
+    if err := r.db.namedQueryRow(ctx, "ConversationRepository.CreateConversationType", query, args, &conversationType.ID, &conversationType.CreatedAt); err != nil {
+        return fmt.Errorf("failed to create conversation type: %w", err)
+    }
+    return nil
+}
+
+func (r *sqlConversationRepository) GetByPersonID(ctx context.Context, personID int64) ([]models.Conversation, error) {
+    userID, err := ownerID(ctx)
+    if err != nil {
+        return nil, err
+    }
+    var rows []conversationRow
     query := `
-        SELECT c.id, c.person_id, c.conversation_type_id, c.initiator, c.notes, c.created_at, c.updated_at,
+        SELECT c.id, c.person_id, c.conversation_type_id, c.initiator, c.notes, c.message_id, c.key_id, c.wrapped_key, c.created_at, c.updated_at,
                ct.id as "conversation_type.id", ct.name as "conversation_type.name", ct.created_at as "conversation_type.created_at"
         FROM conversations c
         JOIN conversation_types ct ON c.conversation_type_id = ct.id
-        WHERE c.person_id = $1
+        JOIN people p ON c.person_id = p.id
+        WHERE c.person_id = ? AND p.user_id = ?
         ORDER BY c.created_at DESC
     `
-    if err := r.db.Select(&conversations, query, personID); err != nil {
+    if err := r.db.selectAll(ctx, "ConversationRepository.GetByPersonID", &rows, query, personID, userID); err != nil {
         return nil, fmt.Errorf("failed to get conversations for person %d: %w", personID, err)
     }
+    conversations := make([]models.Conversation, len(rows))
+    for i := range rows {
+        if err := openFields(r.keys, rows[i].envelope, rows[i].sealedFields()...); err != nil {
+            return nil, fmt.Errorf("failed to decrypt conversation %d: %w", rows[i].ID, err)
+        }
+        conversations[i] = rows[i].Conversation
+    }
     return conversations, nil
 }
 
-func (r *ConversationRepository) GetByID(id int64) (*models.Conversation, error) {
-    var conversation models.Conversation
+func (r *sqlConversationRepository) GetByID(ctx context.Context, id int64) (*models.Conversation, error) {
+    userID, err := ownerID(ctx)
+    if err != nil {
+        return nil, err
+    }
+    var row conversationRow
     query := `
-        SELECT c.id, c.person_id, c.conversation_type_id, c.initiator, c.notes, c.created_at, c.updated_at,
+        SELECT c.id, c.person_id, c.conversation_type_id, c.initiator, c.notes, c.message_id, c.key_id, c.wrapped_key, c.created_at, c.updated_at,
                ct.id as "conversation_type.id", ct.name as "conversation_type.name", ct.created_at as "conversation_type.created_at"
         FROM conversations c
         JOIN conversation_types ct ON c.conversation_type_id = ct.id
-        WHERE c.id = $1
+        JOIN people p ON c.person_id = p.id
+        WHERE c.id = ? AND p.user_id = ?
     `
-    if err := r.db.Get(&conversation, query, id); err != nil {
+    if err := r.db.get(ctx, "ConversationRepository.GetByID", &row, query, id, userID); err != nil {
         if errors.Is(err, sql.ErrNoRows) {
-            return nil, nil
+            return nil, notFound("conversation", "id", id)
         }
         return nil, fmt.Errorf("failed to get conversation by id %d: %w", id, err)
     }
-    return &conversation, nil
+    if err := openFields(r.keys, row.envelope, row.sealedFields()...); err != nil {
+        return nil, fmt.Errorf("failed to decrypt conversation %d: %w", id, err)
+    }
+    return &row.Conversation, nil
 }
 
-func (r *ConversationRepository) Create(conversation *models.Conversation) error {
+func (r *sqlConversationRepository) CountSince(ctx context.Context, since time.Time) (int, error) {
+    var count int
+    query := `SELECT COUNT(*) FROM conversations WHERE created_at >= ?`
+    if err := r.db.get(ctx, "ConversationRepository.CountSince", &count, query, since.UTC()); err != nil {
+        return 0, fmt.Errorf("failed to count conversations since %s: %w", since.Format(time.RFC3339), err)
+    }
+    return count, nil
+}
+
+func (r *sqlConversationRepository) Create(ctx context.Context, conversation *models.Conversation) error {
+    if err := requirePerson(ctx, r.db, "ConversationRepository.Create", conversation.PersonID); err != nil {
+        return err
+    }
+    if err := requireType(ctx, r.db, "ConversationRepository.Create", "conversation_types", conversation.ConversationTypeID, "conversations", "conversation_type_id"); err != nil {
+        return err
+    }
+    row := conversationRow{Conversation: *conversation}
+    if err := sealFields(r.keys, &row.envelope, row.sealedFields()...); err != nil {
+        return err
+    }
     query := `
-        INSERT INTO conversations (person_id, conversation_type_id, initiator, notes)
-        VALUES (:person_id, :conversation_type_id, :initiator, :notes)
+        INSERT INTO conversations (person_id, conversation_type_id, initiator, notes, message_id, key_id, wrapped_key)
+        VALUES (:person_id, :conversation_type_id, :initiator, :notes, :message_id, :key_id, :wrapped_key)
         RETURNING id, created_at, updated_at
     `
-    rows, err := r.db.NamedQuery(query, conversation)
-    if err != nil {
+    if err := r.db.namedQueryRow(ctx, "ConversationRepository.Create", query, row, &conversation.ID, &conversation.CreatedAt, &conversation.UpdatedAt); err != nil {
         return fmt.Errorf("failed to create conversation: %w", err)
     }
-    defer rows.Close()
-    if rows.Next() {
-        if err := rows.Scan(&conversation.ID, &conversation.CreatedAt, &conversation.UpdatedAt); err != nil {
-            return fmt.Errorf("failed to scan created conversation: %w", err)
-        }
-    }
     return nil
 }
 
-func (r *ConversationRepository) Update(conversation *models.Conversation) error {
+func (r *sqlConversationRepository) Update(ctx context.Context, conversation *models.Conversation) error {
+    if err := requireRecord(ctx, r.db, "ConversationRepository.Update", "conversations", "conversation", conversation.ID); err != nil {
+        return err
+    }
+    if err := requireType(ctx, r.db, "ConversationRepository.Update", "conversation_types", conversation.ConversationTypeID, "conversations", "conversation_type_id"); err != nil {
+        return err
+    }
+    row := conversationRow{Conversation: *conversation}
+    if err := sealFields(r.keys, &row.envelope, row.sealedFields()...); err != nil {
+        return err
+    }
     query := `
         UPDATE conversations
-        SET conversation_type_id = :conversation_type_id, initiator = :initiator, notes = :notes, updated_at = NOW()
+        SET conversation_type_id = :conversation_type_id, initiator = :initiator, notes = :notes,
+            key_id = :key_id, wrapped_key = :wrapped_key, updated_at = CURRENT_TIMESTAMP
         WHERE id = :id
-        RETURNING updated_at
+        RETURNING person_id, created_at, updated_at
     `
-    rows, err := r.db.NamedQuery(query, conversation)
-    if err != nil {
-        return fmt.Errorf("failed to update conversation: %w", err)
-    }
-    defer rows.Close()
-    if rows.Next() {
-        if err := rows.Scan(&conversation.UpdatedAt); err != nil {
-            return fmt.Errorf("failed to scan updated conversation: %w", err)
+    if err := r.db.namedQueryRow(ctx, "ConversationRepository.Update", query, row, &conversation.PersonID, &conversation.CreatedAt, &conversation.UpdatedAt); err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return notFound("conversation", "id", conversation.ID)
         }
+        return fmt.Errorf("failed to update conversation: %w", err)
     }
     return nil
 }
 
-func (r *ConversationRepository) Delete(id int64) error {
-    query := `DELETE FROM conversations WHERE id = $1`
-    result, err := r.db.Exec(query, id)
+func (r *sqlConversationRepository) Delete(ctx context.Context, id int64) error {
+    userID, err := ownerID(ctx)
+    if err != nil {
+        return err
+    }
+    query := `DELETE FROM conversations WHERE id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)`
+    result, err := r.db.exec(ctx, "ConversationRepository.Delete", query, id, userID)
     if err != nil {
         return fmt.Errorf("failed to delete conversation: %w", err)
     }
@@ -110,8 +184,15 @@ func (r *ConversationRepository) Delete(id int64) error {
         return fmt.Errorf("failed to get rows affected: %w", err)
     }
     if rowsAffected == 0 {
-        return fmt.Errorf("conversation with id %d not found", id)
+        return notFound("conversation", "id", id)
     }
     return nil
 }
 
+func (r *sqlConversationRepository) Reencrypt(ctx context.Context, limit int) (int, error) {
+    return reencryptColumn(ctx, r.db, "ConversationRepository.Reencrypt", r.keys, "conversations", "notes", limit)
+}
+
+func (r *sqlConversationRepository) RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error {
+	return restoreTimestamps(ctx, r.db, "ConversationRepository.RestoreTimestamps", "conversations", "id", "conversation", id, createdAt, updatedAt)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lincentpega/pcrm/internal/encryption"
+)
+
+// envelope is the wrapped data key stored with an encrypted row. Rows
+// without a key ID are stored in plaintext.
+type envelope struct {
+	KeyID      *string `db:"key_id"`
+	WrappedKey *string `db:"wrapped_key"`
+}
+
+// sealedField is an encrypted column and the value read from or written to
+// it. A nil value is stored as NULL.
+type sealedField struct {
+	column string
+	value  *string
+}
+
+// sealFields encrypts the fields in place under a new data key wrapped by
+// the active key, or clears env when there is no active key.
+func sealFields(keys *encryption.Keyring, env *envelope, fields ...sealedField) error {
+	dataKey, err := keys.NewDataKey()
+	if err != nil {
+		return err
+	}
+	if dataKey == nil {
+		*env = envelope{}
+		return nil
+	}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		ciphertext, err := dataKey.Encrypt(field.column, *field.value)
+		if err != nil {
+			return err
+		}
+		*field.value = ciphertext
+	}
+	env.KeyID = &dataKey.KeyID
+	env.WrappedKey = &dataKey.WrappedKey
+	return nil
+}
+
+// openFields decrypts the fields of a row read with env in place.
+func openFields(keys *encryption.Keyring, env envelope, fields ...sealedField) error {
+	if env.KeyID == nil {
+		return nil
+	}
+	var wrappedKey string
+	if env.WrappedKey != nil {
+		wrappedKey = *env.WrappedKey
+	}
+	dataKey, err := keys.OpenDataKey(*env.KeyID, wrappedKey)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		plaintext, err := dataKey.Decrypt(field.column, *field.value)
+		if err != nil {
+			return err
+		}
+		*field.value = plaintext
+	}
+	return nil
+}
+
+// sealedRow is a single encrypted column of any table, read for
+// re-encryption.
+type sealedRow struct {
+	ID    int64   `db:"id"`
+	Value *string `db:"value"`
+	envelope
+}
+
+// reencryptColumn re-seals up to limit rows of table, across all users,
+// whose column is not encrypted under the active key, and returns how many
+// rows it changed. Without an active key the rows are decrypted.
+func reencryptColumn(ctx context.Context, db *Executor, name string, keys *encryption.Keyring, table, column string, limit int) (int, error) {
+	filter, args := "key_id IS NOT NULL", []any{}
+	if active := keys.ActiveKeyID(); active != "" {
+		filter, args = "(key_id IS NULL OR key_id <> ?)", []any{active}
+	}
+	var rows []sealedRow
+	query := fmt.Sprintf(`SELECT id, %s AS value, key_id, wrapped_key FROM %s WHERE %s ORDER BY id LIMIT ?`, column, table, filter)
+	if err := db.selectAll(ctx, name, &rows, query, append(args, limit)...); err != nil {
+		return 0, fmt.Errorf("failed to read %s for re-encryption: %w", table, err)
+	}
+	field := table + "." + column
+	for _, row := range rows {
+		if err := openFields(keys, row.envelope, sealedField{field, row.Value}); err != nil {
+			return 0, fmt.Errorf("failed to decrypt %s %d: %w", table, row.ID, err)
+		}
+		if err := sealFields(keys, &row.envelope, sealedField{field, row.Value}); err != nil {
+			return 0, fmt.Errorf("failed to encrypt %s %d: %w", table, row.ID, err)
+		}
+		update := fmt.Sprintf(`UPDATE %s SET %s = ?, key_id = ?, wrapped_key = ? WHERE id = ?`, table, column)
+		if _, err := db.exec(ctx, name, update, row.Value, row.KeyID, row.WrappedKey, row.ID); err != nil {
+			return 0, fmt.Errorf("failed to re-encrypt %s %d: %w", table, row.ID, err)
+		}
+	}
+	return len(rows), nil
+}
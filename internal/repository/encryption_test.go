@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/encryption"
+)
+
+func testEncryptionKey(id string, b byte) config.EncryptionKey {
+	return config.EncryptionKey{ID: id, Key: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, encryption.KeySize))}
+}
+
+func TestReencryptColumnInBatches(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	ctx := context.Background()
+	if _, err := executor.exec(ctx, "test", `INSERT INTO contact_types (id, user_id, name) VALUES (100, 1, 'Signal')`); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5 {
+		if _, err := executor.exec(ctx, "test", `INSERT INTO contacts (person_id, contact_type_id, content) VALUES (1, 100, ?)`, fmt.Sprintf("contact %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	k1, k2 := testEncryptionKey("k1", 1), testEncryptionKey("k2", 2)
+	steps := []struct {
+		name  string
+		cfg   config.EncryptionConfig
+		keyID string
+	}{
+		{name: "encrypt", cfg: config.EncryptionConfig{ActiveKey: "k1", Keys: []config.EncryptionKey{k1}}, keyID: "k1"},
+		{name: "rotate", cfg: config.EncryptionConfig{ActiveKey: "k2", Keys: []config.EncryptionKey{k1, k2}}, keyID: "k2"},
+		{name: "decrypt", cfg: config.EncryptionConfig{Keys: []config.EncryptionKey{k2}}},
+	}
+	for _, step := range steps {
+		keys, err := encryption.NewKeyring(step.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var batches []int
+		for {
+			changed, err := reencryptColumn(ctx, executor, "test", keys, "contacts", "content", 2)
+			if err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+			if changed == 0 {
+				break
+			}
+			batches = append(batches, changed)
+		}
+		if !slices.Equal(batches, []int{2, 2, 1}) {
+			t.Errorf("%s: batches = %v, want [2 2 1]", step.name, batches)
+		}
+		var rows []sealedRow
+		if err := executor.selectAll(ctx, "test", &rows, `SELECT id, content AS value, key_id, wrapped_key FROM contacts ORDER BY id`); err != nil {
+			t.Fatal(err)
+		}
+		contacts := NewContactRepository(executor, keys)
+		for i, row := range rows {
+			want := fmt.Sprintf("contact %d", i)
+			if step.keyID == "" && (row.KeyID != nil || row.WrappedKey != nil || *row.Value != want) {
+				t.Errorf("%s: row %d = %q under key %v, want %q in plaintext", step.name, row.ID, *row.Value, row.KeyID, want)
+			}
+			if step.keyID != "" && (row.KeyID == nil || *row.KeyID != step.keyID || *row.Value == want) {
+				t.Errorf("%s: row %d = %q under key %v, want it sealed with %s", step.name, row.ID, *row.Value, row.KeyID, step.keyID)
+			}
+			contact, err := contacts.GetByID(auth.WithUserID(ctx, 1), row.ID)
+			if err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+			if contact.Content != want {
+				t.Errorf("%s: content = %q, want %q", step.name, contact.Content, want)
+			}
+		}
+	}
+}
+
+func TestReencryptColumnWithUnknownKey(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	ctx := context.Background()
+	if _, err := executor.exec(ctx, "test", `INSERT INTO connection_sources (person_id, meeting_story) VALUES (1, 'Met at a conference')`); err != nil {
+		t.Fatal(err)
+	}
+	old, err := encryption.NewKeyring(config.EncryptionConfig{ActiveKey: "k1", Keys: []config.EncryptionKey{testEncryptionKey("k1", 1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reencryptColumn(ctx, executor, "test", old, "connection_sources", "meeting_story", 10); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := encryption.NewKeyring(config.EncryptionConfig{ActiveKey: "k2", Keys: []config.EncryptionKey{testEncryptionKey("k2", 2)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := reencryptColumn(ctx, executor, "test", keys, "connection_sources", "meeting_story", 10); err == nil {
+		t.Errorf("re-encrypted %d rows sealed with a key that is not configured", changed)
+	}
+}
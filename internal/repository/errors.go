@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+)
+
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqCheckViolation      = "23514"
+	pqNotNullViolation    = "23502"
+	pqDataExceptionClass  = "22"
+)
+
+// NotFoundError reports a missing record. Key and Value are left empty when
+// the record was looked up by a secret, which must not end up in messages.
+type NotFoundError struct {
+	Resource string
+	Key      string
+	Value    int64
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s not found", e.Resource)
+	}
+	return fmt.Sprintf("%s with %s %d not found", e.Resource, e.Key, e.Value)
+}
+
+type ConflictError struct {
+	Table      string
+	Constraint string
+	Field      string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s conflicts with an existing record on %s", e.Table, e.Constraint)
+}
+
+type ForeignKeyViolationError struct {
+	Table      string
+	Constraint string
+	Field      string
+}
+
+func (e *ForeignKeyViolationError) Error() string {
+	if e.Constraint == "" {
+		return "a referenced record is missing or protected"
+	}
+	return fmt.Sprintf("%s references a missing or protected record via %s", e.Table, e.Constraint)
+}
+
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func notFound(resource, key string, value int64) error {
+	return &NotFoundError{Resource: resource, Key: key, Value: value}
+}
+
+// translateError converts PostgreSQL and SQLite constraint violations of a
+// statement into typed domain errors so callers can react without knowing
+// driver specifics. arg holds the statement's named arguments, if any.
+func (e *Executor) translateError(ctx context.Context, err error, query string, arg any) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return translatePostgresError(err, pqErr)
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return e.translateSQLiteError(ctx, err, sqliteErr, query, arg)
+	}
+	return err
+}
+
+func translatePostgresError(err error, pqErr *pq.Error) error {
+	field := pqErr.Column
+	if field == "" {
+		field = fieldFromConstraint(pqErr.Table, pqErr.Constraint)
+	}
+	switch {
+	case pqErr.Code == pqUniqueViolation:
+		return &ConflictError{Table: pqErr.Table, Constraint: pqErr.Constraint, Field: field}
+	case pqErr.Code == pqForeignKeyViolation:
+		return &ForeignKeyViolationError{Table: pqErr.Table, Constraint: pqErr.Constraint, Field: field}
+	case pqErr.Code == pqCheckViolation:
+		return &ValidationError{Field: field, Message: "violates check constraint " + pqErr.Constraint}
+	case pqErr.Code == pqNotNullViolation:
+		return &ValidationError{Field: pqErr.Column, Message: "must not be null"}
+	case pqErr.Code.Class() == pqDataExceptionClass:
+		return &ValidationError{Field: pqErr.Column, Message: pqErr.Message}
+	}
+	return err
+}
+
+// fieldFromConstraint derives the column name from PostgreSQL's default
+// "<table>_<column>_<suffix>" constraint naming, and from the project's
+// "uk_<table>_<column>" convention, when the driver does not report the
+// column directly.
+func fieldFromConstraint(table, constraint string) string {
+	if column, ok := strings.CutPrefix(constraint, "uk_"+table+"_"); ok {
+		return column
+	}
+	name, ok := strings.CutPrefix(constraint, table+"_")
+	if !ok {
+		return ""
+	}
+	for _, suffix := range []string{"_fkey", "_key", "_check"} {
+		if column, ok := strings.CutSuffix(name, suffix); ok {
+			return column
+		}
+	}
+	return ""
+}
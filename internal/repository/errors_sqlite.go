@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var sqliteConstraintTarget = regexp.MustCompile(`(?:UNIQUE|NOT NULL|CHECK) constraint failed: (?:[\w.]+, )*([\w.]+)`)
+
+var (
+	sqliteInsertColumns = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+(\w+)\s*\(([^)]*)\)`)
+	sqliteUpdateColumns = regexp.MustCompile(`(?is)^\s*UPDATE\s+(\w+)\s+SET\s+(.*?)(?:\bWHERE\b|\bRETURNING\b|$)`)
+	sqliteAssignment    = regexp.MustCompile(`(\w+)\s*=`)
+)
+
+// sqliteSchemaQuery lists every table with its foreign keys, one row per
+// foreign key column and a single row without a column for other tables.
+const sqliteSchemaQuery = `
+	SELECT m.name AS table_name, f."from" AS column_name, f."table" AS parent_table, f."to" AS parent_column
+	FROM sqlite_master AS m
+	LEFT JOIN pragma_foreign_key_list(m.name) AS f
+	WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'
+	ORDER BY m.name, f.id, f.seq
+`
+
+type sqliteForeignKey struct {
+	Table        string         `db:"table_name"`
+	Column       sql.NullString `db:"column_name"`
+	ParentTable  sql.NullString `db:"parent_table"`
+	ParentColumn sql.NullString `db:"parent_column"`
+}
+
+// translateSQLiteError maps SQLite constraint failures to the same typed
+// errors as PostgreSQL. SQLite names the offending "table.column" for unique
+// and not-null failures, listing every column of a composite key, whose last
+// column is reported. It names the constraint for check failures but reports
+// nothing about failed foreign keys. The table of a check constraint and the
+// column of a foreign key are therefore looked up in the schema, against the
+// columns written by the failed statement.
+func (e *Executor) translateSQLiteError(ctx context.Context, err error, sqliteErr *sqlite.Error, query string, arg any) error {
+	var target string
+	if match := sqliteConstraintTarget.FindStringSubmatch(sqliteErr.Error()); match != nil {
+		target = match[1]
+	}
+	table, column, _ := strings.Cut(target, ".")
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return &ConflictError{Table: table, Constraint: target, Field: column}
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return e.sqliteForeignKeyViolation(ctx, query, arg)
+	case sqlite3.SQLITE_CONSTRAINT_CHECK:
+		return &ValidationError{Field: e.fieldFromCheckConstraint(ctx, target), Message: "violates check constraint " + target}
+	case sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+		return &ValidationError{Field: column, Message: "must not be null"}
+	}
+	return err
+}
+
+// sqliteSchema loads the tables and foreign keys of the database through the
+// statement's own handle, so that it also works inside a transaction on a
+// single connection.
+func (e *Executor) sqliteSchema(ctx context.Context) ([]sqliteForeignKey, error) {
+	var foreignKeys []sqliteForeignKey
+	if err := e.selectAll(ctx, "Executor.sqliteSchema", &foreignKeys, sqliteSchemaQuery); err != nil {
+		return nil, fmt.Errorf("failed to read sqlite schema: %w", err)
+	}
+	return foreignKeys, nil
+}
+
+// fieldFromCheckConstraint resolves the column of a check constraint named
+// after its table, preferring the longest table name that prefixes it.
+func (e *Executor) fieldFromCheckConstraint(ctx context.Context, constraint string) string {
+	foreignKeys, err := e.sqliteSchema(ctx)
+	if err != nil {
+		return ""
+	}
+	var table string
+	for _, foreignKey := range foreignKeys {
+		if len(foreignKey.Table) > len(table) && strings.HasPrefix(constraint, foreignKey.Table+"_") {
+			table = foreignKey.Table
+		}
+	}
+	if table == "" {
+		return ""
+	}
+	return fieldFromConstraint(table, constraint)
+}
+
+// sqliteForeignKeyViolation attributes a failed foreign key to the column of
+// the written table that references a missing row. When the statement writes
+// several foreign key columns, the named argument values are looked up in
+// their parent tables. The error names no column when none can be singled
+// out.
+func (e *Executor) sqliteForeignKeyViolation(ctx context.Context, query string, arg any) error {
+	table, columns := writtenColumns(query)
+	if table == "" {
+		return &ForeignKeyViolationError{}
+	}
+	foreignKeys, err := e.sqliteSchema(ctx)
+	if err != nil {
+		return &ForeignKeyViolationError{Table: table}
+	}
+	var candidates []sqliteForeignKey
+	for _, foreignKey := range foreignKeys {
+		if foreignKey.Table == table && foreignKey.Column.Valid && columns[foreignKey.Column.String] {
+			candidates = append(candidates, foreignKey)
+		}
+	}
+	if len(candidates) > 1 {
+		candidates = e.missingReferences(ctx, candidates, arg)
+	}
+	if len(candidates) != 1 {
+		return &ForeignKeyViolationError{Table: table}
+	}
+	column := candidates[0].Column.String
+	return &ForeignKeyViolationError{Table: table, Constraint: table + "_" + column + "_fkey", Field: column}
+}
+
+// missingReferences keeps the foreign keys whose named argument value has no
+// row in the parent table. It returns the candidates unchanged when the
+// values cannot be resolved.
+func (e *Executor) missingReferences(ctx context.Context, candidates []sqliteForeignKey, arg any) []sqliteForeignKey {
+	if arg == nil {
+		return candidates
+	}
+	var missing []sqliteForeignKey
+	for _, foreignKey := range candidates {
+		_, values, err := sqlx.Named(":"+foreignKey.Column.String, arg)
+		if err != nil || len(values) != 1 {
+			return candidates
+		}
+		if values[0] == nil {
+			continue
+		}
+		parentColumn := "rowid"
+		if foreignKey.ParentColumn.Valid {
+			parentColumn = foreignKey.ParentColumn.String
+		}
+		var exists bool
+		query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE %s = ?)`, foreignKey.ParentTable.String, parentColumn)
+		if err := e.get(ctx, "Executor.missingReferences", &exists, query, values[0]); err != nil {
+			return candidates
+		}
+		if !exists {
+			missing = append(missing, foreignKey)
+		}
+	}
+	return missing
+}
+
+// writtenColumns returns the table of an INSERT or UPDATE statement and the
+// columns it assigns.
+func writtenColumns(query string) (string, map[string]bool) {
+	columns := make(map[string]bool)
+	if match := sqliteInsertColumns.FindStringSubmatch(query); match != nil {
+		for column := range strings.SplitSeq(match[2], ",") {
+			columns[strings.TrimSpace(column)] = true
+		}
+		return match[1], columns
+	}
+	if match := sqliteUpdateColumns.FindStringSubmatch(query); match != nil {
+		for _, assignment := range sqliteAssignment.FindAllStringSubmatch(match[2], -1) {
+			columns[assignment[1]] = true
+		}
+		return match[1], columns
+	}
+	return "", columns
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/migrate"
+)
+
+// newSQLiteExecutor returns an executor for a migrated SQLite database with
+// one user who has one person, whose ids are both 1.
+func newSQLiteExecutor(t *testing.T) *Executor {
+	t.Helper()
+	ctx := context.Background()
+	db, err := config.NewDatabase(config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "pcrm.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	executor := NewExecutor(db, time.Second)
+	if _, err := executor.exec(ctx, "test", `INSERT INTO users (id, username, password_hash) VALUES (1, 'alice', '')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executor.exec(ctx, "test", `INSERT INTO people (id, user_id, first_name) VALUES (1, 1, 'Bob')`); err != nil {
+		t.Fatal(err)
+	}
+	return executor
+}
+
+func TestSQLiteForeignKeyViolationNamesColumn(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		query string
+		arg   map[string]any
+		table string
+		field string
+	}{
+		{
+			name:  "single foreign key",
+			query: `INSERT INTO person_tags (person_id, name) VALUES (:person_id, :name) RETURNING person_id`,
+			arg:   map[string]any{"person_id": 2, "name": "friend"},
+			table: "person_tags",
+			field: "person_id",
+		},
+		{
+			name:  "missing type",
+			query: `INSERT INTO contacts (person_id, contact_type_id, content) VALUES (:person_id, :contact_type_id, :content) RETURNING id`,
+			arg:   map[string]any{"person_id": 1, "contact_type_id": 999, "content": "bob@example.com"},
+			table: "contacts",
+			field: "contact_type_id",
+		},
+		{
+			name:  "missing person",
+			query: `INSERT INTO contacts (person_id, contact_type_id, content) VALUES (:person_id, :contact_type_id, :content) RETURNING id`,
+			arg:   map[string]any{"person_id": 2, "contact_type_id": 1, "content": "bob@example.com"},
+			table: "contacts",
+			field: "person_id",
+		},
+		{
+			name:  "update",
+			query: `UPDATE connection_sources SET introducer_person_id = :introducer_person_id WHERE person_id = :person_id RETURNING id`,
+			arg:   map[string]any{"person_id": 1, "introducer_person_id": 2},
+			table: "connection_sources",
+			field: "introducer_person_id",
+		},
+	}
+	if _, err := executor.exec(ctx, "test", `INSERT INTO connection_sources (person_id) VALUES (1)`); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id int64
+			err := executor.namedQueryRow(ctx, "test", tt.query, tt.arg, &id)
+			var fkErr *ForeignKeyViolationError
+			if !errors.As(err, &fkErr) {
+				t.Fatalf("err = %v, want a foreign key violation", err)
+			}
+			if fkErr.Table != tt.table || fkErr.Field != tt.field || fkErr.Constraint != tt.table+"_"+tt.field+"_fkey" {
+				t.Errorf("err = %+v, want table %s and field %s", fkErr, tt.table, tt.field)
+			}
+		})
+	}
+}
+
+func TestSQLiteForeignKeyViolationOnPositionalStatement(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	_, err := executor.exec(context.Background(), "test", `INSERT INTO person_tags (person_id, name) VALUES (?, ?)`, 2, "friend")
+	var fkErr *ForeignKeyViolationError
+	if !errors.As(err, &fkErr) || fkErr.Table != "person_tags" || fkErr.Field != "person_id" {
+		t.Errorf("err = %v, want a foreign key violation on person_tags.person_id", err)
+	}
+}
+
+func TestSQLiteCheckViolationNamesColumn(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	_, err := executor.exec(context.Background(), "test", `INSERT INTO birth_date_info (person_id, birth_month) VALUES (1, 13)`)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "birth_month" {
+		t.Errorf("err = %v, want a validation error on birth_month", err)
+	}
+}
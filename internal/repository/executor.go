@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/lincentpega/pcrm/internal/repository")
+
+// Executor runs repository statements against a database handle or an open
+// transaction and bounds each statement by the configured query timeout.
+// Statements are written with "?" placeholders and rebound to the driver's
+// bind variable syntax. Every statement is traced as a client span named after
+// the repository method that runs it.
+type Executor struct {
+	db           sqlx.ExtContext
+	queryTimeout time.Duration
+}
+
+func NewExecutor(db sqlx.ExtContext, queryTimeout time.Duration) *Executor {
+	return &Executor{db: db, queryTimeout: queryTimeout}
+}
+
+func (e *Executor) get(ctx context.Context, name string, dest any, query string, args ...any) (err error) {
+	ctx, span := e.startSpan(ctx, name, query)
+	defer func() { endSpan(span, err) }()
+	ctx, cancel := e.statementContext(ctx)
+	defer cancel()
+	return contextError(ctx, sqlx.GetContext(ctx, e.db, dest, e.db.Rebind(query), args...))
+}
+
+func (e *Executor) selectAll(ctx context.Context, name string, dest any, query string, args ...any) (err error) {
+	ctx, span := e.startSpan(ctx, name, query)
+	defer func() { endSpan(span, err) }()
+	ctx, cancel := e.statementContext(ctx)
+	defer cancel()
+	return contextError(ctx, sqlx.SelectContext(ctx, e.db, dest, e.db.Rebind(query), args...))
+}
+
+func (e *Executor) exec(ctx context.Context, name string, query string, args ...any) (_ sql.Result, err error) {
+	ctx, span := e.startSpan(ctx, name, query)
+	defer func() { endSpan(span, err) }()
+	ctx, cancel := e.statementContext(ctx)
+	defer cancel()
+	result, err := e.db.ExecContext(ctx, e.db.Rebind(query), args...)
+	if err != nil {
+		return nil, contextError(ctx, e.translateError(ctx, err, query, nil))
+	}
+	return result, nil
+}
+
+// namedQueryRow runs a named statement with a RETURNING clause and scans its
+// single result row into dest. Drivers may defer statement errors until the
+// row is read, so rows.Err is checked before reporting sql.ErrNoRows. The rows
+// are closed before the error is translated, which may query the schema on
+// the same connection.
+func (e *Executor) namedQueryRow(ctx context.Context, name string, query string, arg any, dest ...any) (err error) {
+	ctx, span := e.startSpan(ctx, name, query)
+	defer func() { endSpan(span, err) }()
+	ctx, cancel := e.statementContext(ctx)
+	defer cancel()
+	rows, err := sqlx.NamedQueryContext(ctx, e.db, query, arg)
+	if err != nil {
+		return contextError(ctx, e.translateError(ctx, err, query, arg))
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		err := rows.Err()
+		rows.Close()
+		if err != nil {
+			return contextError(ctx, e.translateError(ctx, err, query, arg))
+		}
+		return sql.ErrNoRows
+	}
+	return rows.Scan(dest...)
+}
+
+func (e *Executor) startSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		e.dbSystem(),
+		semconv.DBQueryText(query),
+	))
+}
+
+// endSpan records err on span unless it only reports a missing row, which
+// repositories turn into a not found result.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (e *Executor) dbSystem() attribute.KeyValue {
+	switch e.db.DriverName() {
+	case "postgres":
+		return semconv.DBSystemNamePostgreSQL
+	case "sqlite":
+		return semconv.DBSystemNameSQLite
+	}
+	return semconv.DBSystemNameKey.String(e.db.DriverName())
+}
+
+func (e *Executor) statementContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, e.queryTimeout)
+}
+
+// contextError attributes a failed statement to the cancellation of its
+// context, so callers can tell client disconnects and timeouts apart from
+// database failures with errors.Is.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ctx.Err(), err)
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/repository"
+)
+
+var tokenScopes = []string{models.TokenScopeRead, models.TokenScopeWrite}
+
+type apiTokenRepository struct {
+	run runner
+}
+
+func (r *apiTokenRepository) List(ctx context.Context) ([]models.APIToken, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []models.APIToken
+	err = r.run(ctx, func(t *tables) error {
+		for _, token := range t.apiTokens {
+			if token.UserID == userID {
+				tokens = append(tokens, cloneAPIToken(token))
+			}
+		}
+		slices.SortFunc(tokens, func(a, b models.APIToken) int {
+			return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+		})
+		return nil
+	})
+	return tokens, err
+}
+
+func (r *apiTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	var token *models.APIToken
+	err := r.run(ctx, func(t *tables) error {
+		for _, stored := range t.apiTokens {
+			if stored.TokenHash == tokenHash {
+				cloned := cloneAPIToken(stored)
+				token = &cloned
+				return nil
+			}
+		}
+		return &repository.NotFoundError{Resource: "api token"}
+	})
+	return token, err
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		token.UserID = userID
+		if !slices.Contains(tokenScopes, token.Scope) {
+			return checkViolation("api_tokens", "scope")
+		}
+		for _, stored := range t.apiTokens {
+			if stored.TokenHash == token.TokenHash {
+				return uniqueViolation("api_tokens", "token_hash")
+			}
+		}
+		token.ID = t.nextID("api_tokens")
+		token.CreatedAt = time.Now()
+		t.apiTokens[token.ID] = cloneAPIToken(*token)
+		return nil
+	})
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error {
+	return r.run(ctx, func(t *tables) error {
+		token, ok := t.apiTokens[id]
+		if !ok {
+			return nil
+		}
+		token.LastUsedAt = &lastUsedAt
+		t.apiTokens[id] = token
+		return nil
+	})
+}
+
+func (r *apiTokenRepository) Delete(ctx context.Context, id int64) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if stored, ok := t.apiTokens[id]; !ok || stored.UserID != userID {
+			return notFound("api token", "id", id)
+		}
+		delete(t.apiTokens, id)
+		return nil
+	})
+}
+
+func cloneAPIToken(token models.APIToken) models.APIToken {
+	token.ExpiresAt = clonePtr(token.ExpiresAt)
+	token.LastUsedAt = clonePtr(token.LastUsedAt)
+	return token
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type birthDateInfoRepository struct {
+	run runner
+}
+
+func (r *birthDateInfoRepository) GetByPersonID(ctx context.Context, personID int64) (*models.BirthDateInfo, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var birthDateInfo *models.BirthDateInfo
+	err = r.run(ctx, func(t *tables) error {
+		if stored, ok := t.birthDateInfo[personID]; ok && t.ownsPerson(userID, personID) {
+			cloned := cloneBirthDateInfo(stored)
+			birthDateInfo = &cloned
+		}
+		return nil
+	})
+	return birthDateInfo, err
+}
+
+func (r *birthDateInfoRepository) Create(ctx context.Context, birthDateInfo *models.BirthDateInfo) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, birthDateInfo.PersonID); err != nil {
+			return err
+		}
+		if err := checkBirthDateInfoRanges(*birthDateInfo); err != nil {
+			return err
+		}
+		if _, ok := t.birthDateInfo[birthDateInfo.PersonID]; ok {
+			return uniqueViolation("birth_date_info", "person_id")
+		}
+		return t.insertBirthDateInfo(birthDateInfo)
+	})
+}
+
+func (r *birthDateInfoRepository) Update(ctx context.Context, birthDateInfo *models.BirthDateInfo) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, birthDateInfo.PersonID); err != nil {
+			return err
+		}
+		stored, ok := t.birthDateInfo[birthDateInfo.PersonID]
+		if !ok {
+			return notFound("birth date info", "person id", birthDateInfo.PersonID)
+		}
+		if err := checkBirthDateInfoRanges(*birthDateInfo); err != nil {
+			return err
+		}
+		t.replaceBirthDateInfo(stored, birthDateInfo)
+		return nil
+	})
+}
+
+func (r *birthDateInfoRepository) Upsert(ctx context.Context, birthDateInfo *models.BirthDateInfo) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, birthDateInfo.PersonID); err != nil {
+			return err
+		}
+		if err := checkBirthDateInfoRanges(*birthDateInfo); err != nil {
+			return err
+		}
+		if stored, ok := t.birthDateInfo[birthDateInfo.PersonID]; ok {
+			t.replaceBirthDateInfo(stored, birthDateInfo)
+			return nil
+		}
+		return t.insertBirthDateInfo(birthDateInfo)
+	})
+}
+
+func (r *birthDateInfoRepository) Delete(ctx context.Context, personID int64) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if _, ok := t.birthDateInfo[personID]; !ok || !t.ownsPerson(userID, personID) {
+			return notFound("birth date info", "person id", personID)
+		}
+		delete(t.birthDateInfo, personID)
+		return nil
+	})
+}
+
+func (t *tables) insertBirthDateInfo(birthDateInfo *models.BirthDateInfo) error {
+	if _, ok := t.people[birthDateInfo.PersonID]; !ok {
+		return foreignKeyViolation("birth_date_info", "person_id")
+	}
+	birthDateInfo.ID = t.nextID("birth_date_info")
+	birthDateInfo.CreatedAt = time.Now()
+	birthDateInfo.UpdatedAt = birthDateInfo.CreatedAt
+	t.birthDateInfo[birthDateInfo.PersonID] = cloneBirthDateInfo(*birthDateInfo)
+	return nil
+}
+
+func (t *tables) replaceBirthDateInfo(stored models.BirthDateInfo, birthDateInfo *models.BirthDateInfo) {
+	birthDateInfo.ID = stored.ID
+	birthDateInfo.CreatedAt = stored.CreatedAt
+	birthDateInfo.UpdatedAt = time.Now()
+	t.birthDateInfo[birthDateInfo.PersonID] = cloneBirthDateInfo(*birthDateInfo)
+}
+
+func checkBirthDateInfoRanges(birthDateInfo models.BirthDateInfo) error {
+	if birthDateInfo.BirthMonth != nil && (*birthDateInfo.BirthMonth < 1 || *birthDateInfo.BirthMonth > 12) {
+		return checkViolation("birth_date_info", "birth_month")
+	}
+	if birthDateInfo.BirthDay != nil && (*birthDateInfo.BirthDay < 1 || *birthDateInfo.BirthDay > 31) {
+		return checkViolation("birth_date_info", "birth_day")
+	}
+	return nil
+}
+
+func cloneBirthDateInfo(birthDateInfo models.BirthDateInfo) models.BirthDateInfo {
+	birthDateInfo.BirthYear = clonePtr(birthDateInfo.BirthYear)
+	birthDateInfo.BirthMonth = clonePtr(birthDateInfo.BirthMonth)
+	birthDateInfo.BirthDay = clonePtr(birthDateInfo.BirthDay)
+	birthDateInfo.ApproximateAge = clonePtr(birthDateInfo.ApproximateAge)
+	birthDateInfo.ApproximateAgeUpdatedAt = clonePtr(birthDateInfo.ApproximateAgeUpdatedAt)
+	return birthDateInfo
+}
+
+func (r *birthDateInfoRepository) RestoreTimestamps(ctx context.Context, personID int64, createdAt, updatedAt time.Time) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.birthDateInfo[personID]
+		if !ok || !t.ownsPerson(userID, personID) {
+			return notFound("birth date info", "person id", personID)
+		}
+		stored.CreatedAt, stored.UpdatedAt = createdAt, updatedAt
+		t.birthDateInfo[personID] = stored
+		return nil
+	})
+}
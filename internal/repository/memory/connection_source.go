@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type connectionSourceRepository struct {
+	run runner
+}
+
+func (r *connectionSourceRepository) GetByPersonID(ctx context.Context, personID int64) (*models.ConnectionSource, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var connectionSource *models.ConnectionSource
+	err = r.run(ctx, func(t *tables) error {
+		if stored, ok := t.connectionSources[personID]; ok && t.ownsPerson(userID, personID) {
+			cloned := cloneConnectionSource(stored)
+			connectionSource = &cloned
+		}
+		return nil
+	})
+	return connectionSource, err
+}
+
+func (r *connectionSourceRepository) GetByIntroducerPersonID(ctx context.Context, introducerPersonID int64) ([]models.ConnectionSource, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var connectionSources []models.ConnectionSource
+	err = r.run(ctx, func(t *tables) error {
+		for personID, stored := range t.connectionSources {
+			if stored.IntroducerPersonID != nil && *stored.IntroducerPersonID == introducerPersonID && t.ownsPerson(userID, personID) {
+				connectionSources = append(connectionSources, cloneConnectionSource(stored))
+			}
+		}
+		slices.SortFunc(connectionSources, func(a, b models.ConnectionSource) int {
+			return cmp.Compare(a.PersonID, b.PersonID)
+		})
+		return nil
+	})
+	return connectionSources, err
+}
+
+func (r *connectionSourceRepository) Create(ctx context.Context, connectionSource *models.ConnectionSource) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, connectionSource.PersonID); err != nil {
+			return err
+		}
+		if _, ok := t.connectionSources[connectionSource.PersonID]; ok {
+			return uniqueViolation("connection_sources", "person_id")
+		}
+		return t.insertConnectionSource(connectionSource)
+	})
+}
+
+func (r *connectionSourceRepository) Update(ctx context.Context, connectionSource *models.ConnectionSource) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, connectionSource.PersonID); err != nil {
+			return err
+		}
+		stored, ok := t.connectionSources[connectionSource.PersonID]
+		if !ok {
+			return notFound("connection source", "person id", connectionSource.PersonID)
+		}
+		return t.replaceConnectionSource(stored, connectionSource)
+	})
+}
+
+func (r *connectionSourceRepository) Upsert(ctx context.Context, connectionSource *models.ConnectionSource) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, connectionSource.PersonID); err != nil {
+			return err
+		}
+		if stored, ok := t.connectionSources[connectionSource.PersonID]; ok {
+			return t.replaceConnectionSource(stored, connectionSource)
+		}
+		return t.insertConnectionSource(connectionSource)
+	})
+}
+
+func (r *connectionSourceRepository) Delete(ctx context.Context, personID int64) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if _, ok := t.connectionSources[personID]; !ok || !t.ownsPerson(userID, personID) {
+			return notFound("connection source", "person id", personID)
+		}
+		delete(t.connectionSources, personID)
+		return nil
+	})
+}
+
+func (t *tables) insertConnectionSource(connectionSource *models.ConnectionSource) error {
+	if err := t.checkConnectionSourceReferences(*connectionSource); err != nil {
+		return err
+	}
+	connectionSource.ID = t.nextID("connection_sources")
+	connectionSource.CreatedAt = time.Now()
+	connectionSource.UpdatedAt = connectionSource.CreatedAt
+	t.connectionSources[connectionSource.PersonID] = cloneConnectionSource(*connectionSource)
+	return nil
+}
+
+func (t *tables) replaceConnectionSource(stored models.ConnectionSource, connectionSource *models.ConnectionSource) error {
+	if err := t.checkConnectionSourceReferences(*connectionSource); err != nil {
+		return err
+	}
+	connectionSource.ID = stored.ID
+	connectionSource.CreatedAt = stored.CreatedAt
+	connectionSource.UpdatedAt = time.Now()
+	t.connectionSources[connectionSource.PersonID] = cloneConnectionSource(*connectionSource)
+	return nil
+}
+
+func (t *tables) checkConnectionSourceReferences(connectionSource models.ConnectionSource) error {
+	if _, ok := t.people[connectionSource.PersonID]; !ok {
+		return foreignKeyViolation("connection_sources", "person_id")
+	}
+	if connectionSource.IntroducerPersonID == nil {
+		return nil
+	}
+	introducer, ok := t.people[*connectionSource.IntroducerPersonID]
+	if !ok || introducer.UserID != t.people[connectionSource.PersonID].UserID {
+		return foreignKeyViolation("connection_sources", "introducer_person_id")
+	}
+	return nil
+}
+
+func cloneConnectionSource(connectionSource models.ConnectionSource) models.ConnectionSource {
+	connectionSource.MeetingStory = clonePtr(connectionSource.MeetingStory)
+	connectionSource.MeetingTimestamp = clonePtr(connectionSource.MeetingTimestamp)
+	connectionSource.WasIntroduced = clonePtr(connectionSource.WasIntroduced)
+	connectionSource.IntroducerPersonID = clonePtr(connectionSource.IntroducerPersonID)
+	connectionSource.IntroducerName = clonePtr(connectionSource.IntroducerName)
+	return connectionSource
+}
+
+// Reencrypt has nothing to do, as the store keeps no data at rest.
+func (r *connectionSourceRepository) Reencrypt(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+func (r *connectionSourceRepository) RestoreTimestamps(ctx context.Context, personID int64, createdAt, updatedAt time.Time) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.connectionSources[personID]
+		if !ok || !t.ownsPerson(userID, personID) {
+			return notFound("connection source", "person id", personID)
+		}
+		stored.CreatedAt, stored.UpdatedAt = createdAt, updatedAt
+		t.connectionSources[personID] = stored
+		return nil
+	})
+}
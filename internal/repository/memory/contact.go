@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type contactRepository struct {
+	run runner
+}
+
+func (r *contactRepository) GetContactTypes(ctx context.Context) ([]models.ContactType, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var types []models.ContactType
+	err = r.run(ctx, func(t *tables) error {
+		for contactType := range maps.Values(t.contactTypes) {
+			if t.typeVisible("contact_types", contactType.ID, userID) {
+				types = append(types, contactType)
+			}
+		}
+		slices.SortFunc(types, func(a, b models.ContactType) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		return nil
+	})
+	return types, err
+}
+
+func (r *contactRepository) CreateContactType(ctx context.Context, contactType *models.ContactType) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		for existing := range maps.Values(t.contactTypes) {
+			if existing.Name == contactType.Name && t.ownsType("contact_types", existing.ID, userID) {
+				return uniqueViolation("contact_types", "name")
+			}
+		}
+		contactType.ID = t.nextID("contact_types")
+		contactType.CreatedAt = time.Now()
+		t.contactTypes[contactType.ID] = *contactType
+		t.typeOwners[typeKey{"contact_types", contactType.ID}] = userID
+		return nil
+	})
+}
+
+func (r *contactRepository) GetByPersonID(ctx context.Context, personID int64) ([]models.Contact, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var contacts []models.Contact
+	err = r.run(ctx, func(t *tables) error {
+		if !t.ownsPerson(userID, personID) {
+			return nil
+		}
+		for _, contact := range t.contacts {
+			if contact.PersonID == personID {
+				contacts = append(contacts, t.contactWithType(contact))
+			}
+		}
+		slices.SortFunc(contacts, func(a, b models.Contact) int {
+			return cmp.Or(strings.Compare(a.ContactType.Name, b.ContactType.Name), b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+		})
+		return nil
+	})
+	return contacts, err
+}
+
+func (r *contactRepository) GetByContactTypeID(ctx context.Context, contactTypeID int64) ([]models.Contact, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var contacts []models.Contact
+	err = r.run(ctx, func(t *tables) error {
+		for _, contact := range t.contacts {
+			if contact.ContactTypeID == contactTypeID && t.ownsPerson(userID, contact.PersonID) {
+				contacts = append(contacts, t.contactWithType(contact))
+			}
+		}
+		slices.SortFunc(contacts, func(a, b models.Contact) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+		return nil
+	})
+	return contacts, err
+}
+
+func (r *contactRepository) GetByID(ctx context.Context, id int64) (*models.Contact, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var contact models.Contact
+	err = r.run(ctx, func(t *tables) error {
+		stored, ok := t.contacts[id]
+		if !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("contact", "id", id)
+		}
+		contact = t.contactWithType(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+func (r *contactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, contact.PersonID); err != nil {
+			return err
+		}
+		if err := t.checkContactReferences(*contact, userID); err != nil {
+			return err
+		}
+		contact.ID = t.nextID("contacts")
+		contact.CreatedAt = time.Now()
+		contact.UpdatedAt = contact.CreatedAt
+		t.contacts[contact.ID] = storedContact(*contact)
+		return nil
+	})
+}
+
+func (r *contactRepository) Update(ctx context.Context, contact *models.Contact) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.contacts[contact.ID]
+		if !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("contact", "id", contact.ID)
+		}
+		contact.PersonID = stored.PersonID
+		if err := t.checkContactReferences(*contact, userID); err != nil {
+			return err
+		}
+		contact.CreatedAt = stored.CreatedAt
+		contact.UpdatedAt = time.Now()
+		t.contacts[contact.ID] = storedContact(*contact)
+		return nil
+	})
+}
+
+func (r *contactRepository) Delete(ctx context.Context, id int64) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if stored, ok := t.contacts[id]; !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("contact", "id", id)
+		}
+		delete(t.contacts, id)
+		return nil
+	})
+}
+
+func (t *tables) checkContactReferences(contact models.Contact, userID int64) error {
+	if _, ok := t.people[contact.PersonID]; !ok {
+		return foreignKeyViolation("contacts", "person_id")
+	}
+	if _, ok := t.contactTypes[contact.ContactTypeID]; !ok || !t.typeVisible("contact_types", contact.ContactTypeID, userID) {
+		return foreignKeyViolation("contacts", "contact_type_id")
+	}
+	return nil
+}
+
+func (t *tables) contactWithType(contact models.Contact) models.Contact {
+	contact.ContactType = t.contactTypes[contact.ContactTypeID]
+	return contact
+}
+
+// storedContact drops the joined contact type, which is resolved on read like
+// the SQL join does.
+func storedContact(contact models.Contact) models.Contact {
+	contact.ContactType = models.ContactType{}
+	return contact
+}
+
+// Reencrypt has nothing to do, as the store keeps no data at rest.
+func (r *contactRepository) Reencrypt(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+func (r *contactRepository) RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.contacts[id]
+		if !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("contact", "id", id)
+		}
+		stored.CreatedAt, stored.UpdatedAt = createdAt, updatedAt
+		t.contacts[id] = stored
+		return nil
+	})
+}
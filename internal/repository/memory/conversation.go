@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+var conversationInitiators = []string{"owner", "person"}
+
+type conversationRepository struct {
+	run runner
+}
+
+func (r *conversationRepository) GetConversationTypes(ctx context.Context) ([]models.ConversationType, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var types []models.ConversationType
+	err = r.run(ctx, func(t *tables) error {
+		for conversationType := range maps.Values(t.conversationTypes) {
+			if t.typeVisible("conversation_types", conversationType.ID, userID) {
+				types = append(types, conversationType)
+			}
+		}
+		slices.SortFunc(types, func(a, b models.ConversationType) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		return nil
+	})
+	return types, err
+}
+
+func (r *conversationRepository) CreateConversationType(ctx context.Context, conversationType *models.ConversationType) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		for existing := range maps.Values(t.conversationTypes) {
+			if existing.Name == conversationType.Name && t.ownsType("conversation_types", existing.ID, userID) {
+				return uniqueViolation("conversation_types", "name")
+			}
+		}
+		conversationType.ID = t.nextID("conversation_types")
+		conversationType.CreatedAt = time.Now()
+		t.conversationTypes[conversationType.ID] = *conversationType
+		t.typeOwners[typeKey{"conversation_types", conversationType.ID}] = userID
+		return nil
+	})
+}
+
+func (r *conversationRepository) GetByPersonID(ctx context.Context, personID int64) ([]models.Conversation, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var conversations []models.Conversation
+	err = r.run(ctx, func(t *tables) error {
+		if !t.ownsPerson(userID, personID) {
+			return nil
+		}
+		for _, conversation := range t.conversations {
+			if conversation.PersonID == personID {
+				conversations = append(conversations, t.conversationWithType(conversation))
+			}
+		}
+		slices.SortFunc(conversations, func(a, b models.Conversation) int {
+			return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+		})
+		return nil
+	})
+	return conversations, err
+}
+
+func (r *conversationRepository) GetByID(ctx context.Context, id int64) (*models.Conversation, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var conversation models.Conversation
+	err = r.run(ctx, func(t *tables) error {
+		stored, ok := t.conversations[id]
+		if !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("conversation", "id", id)
+		}
+		conversation = t.conversationWithType(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *conversationRepository) CountSince(ctx context.Context, since time.Time) (int, error) {
+	var count int
+	err := r.run(ctx, func(t *tables) error {
+		for _, conversation := range t.conversations {
+			if !conversation.CreatedAt.Before(since) {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (r *conversationRepository) Create(ctx context.Context, conversation *models.Conversation) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, conversation.PersonID); err != nil {
+			return err
+		}
+		if err := t.checkConversation(*conversation); err != nil {
+			return err
+		}
+		for _, stored := range t.conversations {
+			if conversation.MessageID != nil && stored.MessageID != nil && stored.PersonID == conversation.PersonID && *stored.MessageID == *conversation.MessageID {
+				return uniqueViolation("conversations", "message_id")
+			}
+		}
+		conversation.ID = t.nextID("conversations")
+		conversation.CreatedAt = time.Now()
+		conversation.UpdatedAt = conversation.CreatedAt
+		t.conversations[conversation.ID] = storedConversation(*conversation)
+		return nil
+	})
+}
+
+func (r *conversationRepository) Update(ctx context.Context, conversation *models.Conversation) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.conversations[conversation.ID]
+		if !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("conversation", "id", conversation.ID)
+		}
+		conversation.PersonID = stored.PersonID
+		conversation.MessageID = stored.MessageID
+		if err := t.checkConversation(*conversation); err != nil {
+			return err
+		}
+		conversation.CreatedAt = stored.CreatedAt
+		conversation.UpdatedAt = time.Now()
+		t.conversations[conversation.ID] = storedConversation(*conversation)
+		return nil
+	})
+}
+
+func (r *conversationRepository) Delete(ctx context.Context, id int64) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if stored, ok := t.conversations[id]; !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("conversation", "id", id)
+		}
+		delete(t.conversations, id)
+		return nil
+	})
+}
+
+func (t *tables) checkConversation(conversation models.Conversation) error {
+	if !slices.Contains(conversationInitiators, conversation.Initiator) {
+		return checkViolation("conversations", "initiator")
+	}
+	if _, ok := t.people[conversation.PersonID]; !ok {
+		return foreignKeyViolation("conversations", "person_id")
+	}
+	if _, ok := t.conversationTypes[conversation.ConversationTypeID]; !ok {
+		return foreignKeyViolation("conversations", "conversation_type_id")
+	}
+	return nil
+}
+
+func (t *tables) conversationWithType(conversation models.Conversation) models.Conversation {
+	conversation.ConversationType = t.conversationTypes[conversation.ConversationTypeID]
+	return conversation
+}
+
+// storedConversation drops the joined conversation type, which is resolved on
+// read like the SQL join does.
+func storedConversation(conversation models.Conversation) models.Conversation {
+	conversation.ConversationType = models.ConversationType{}
+	conversation.MessageID = clonePtr(conversation.MessageID)
+	return conversation
+}
+
+// Reencrypt has nothing to do, as the store keeps no data at rest.
+func (r *conversationRepository) Reencrypt(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+func (r *conversationRepository) RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.conversations[id]
+		if !ok || !t.ownsPerson(userID, stored.PersonID) {
+			return notFound("conversation", "id", id)
+		}
+		stored.CreatedAt, stored.UpdatedAt = createdAt, updatedAt
+		t.conversations[id] = stored
+		return nil
+	})
+}
@@ -0,0 +1,19 @@
+package memory
+
+import "github.com/lincentpega/pcrm/internal/repository"
+
+func notFound(resource, key string, value int64) error {
+	return &repository.NotFoundError{Resource: resource, Key: key, Value: value}
+}
+
+func foreignKeyViolation(table, column string) error {
+	return &repository.ForeignKeyViolationError{Table: table, Constraint: table + "_" + column + "_fkey", Field: column}
+}
+
+func uniqueViolation(table, column string) error {
+	return &repository.ConflictError{Table: table, Constraint: "uk_" + table + "_" + column, Field: column}
+}
+
+func checkViolation(table, column string) error {
+	return &repository.ValidationError{Field: column, Message: "violates check constraint " + table + "_" + column + "_check"}
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/repository"
+)
+
+func owner(ctx context.Context) (int64, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return 0, repository.ErrNoUser
+	}
+	return userID, nil
+}
+
+func (t *tables) ownsPerson(userID, personID int64) bool {
+	person, ok := t.people[personID]
+	return ok && person.UserID == userID
+}
+
+// requirePerson hides other users' people behind the same error as missing
+// ones, like the SQL repositories.
+func (t *tables) requirePerson(userID, personID int64) error {
+	if !t.ownsPerson(userID, personID) {
+		return notFound("person", "id", personID)
+	}
+	return nil
+}
+
+type typeKey struct {
+	table string
+	id    int64
+}
+
+// typeVisible reports whether the type in table is shared or belongs to the
+// user, like the SQL type lookups.
+func (t *tables) typeVisible(table string, typeID, userID int64) bool {
+	typeOwner, owned := t.typeOwners[typeKey{table, typeID}]
+	return !owned || typeOwner == userID
+}
+
+// ownsType reports whether the type in table belongs to the user. Type names
+// are unique per user, like the SQL unique key on user_id and name.
+func (t *tables) ownsType(table string, typeID, userID int64) bool {
+	typeOwner, owned := t.typeOwners[typeKey{table, typeID}]
+	return owned && typeOwner == userID
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type personRepository struct {
+	run runner
+}
+
+func (r *personRepository) GetPaginated(ctx context.Context, page, limit int) ([]models.Person, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var people []models.Person
+	err = r.run(ctx, func(t *tables) error {
+		sorted := slices.SortedFunc(t.peopleOf(userID), newestPersonFirst)
+		offset := min((page-1)*limit, len(sorted))
+		for _, person := range sorted[offset:min(offset+limit, len(sorted))] {
+			people = append(people, clonePerson(person))
+		}
+		return nil
+	})
+	return people, err
+}
+
+func (r *personRepository) GetPageAfter(ctx context.Context, afterID int64, limit int) ([]models.Person, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var people []models.Person
+	err = r.run(ctx, func(t *tables) error {
+		sorted := slices.SortedFunc(t.peopleOf(userID), func(a, b models.Person) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+		for _, person := range sorted {
+			if person.ID > afterID && len(people) < limit {
+				people = append(people, clonePerson(person))
+			}
+		}
+		return nil
+	})
+	return people, err
+}
+
+func (r *personRepository) GetTotalCount(ctx context.Context) (int, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = r.run(ctx, func(t *tables) error {
+		for range t.peopleOf(userID) {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (r *personRepository) CountAll(ctx context.Context) (int, error) {
+	var count int
+	err := r.run(ctx, func(t *tables) error {
+		count = len(t.people)
+		return nil
+	})
+	return count, err
+}
+
+func (r *personRepository) GetByID(ctx context.Context, id int64) (*models.Person, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var person models.Person
+	err = r.run(ctx, func(t *tables) error {
+		stored, ok := t.people[id]
+		if !ok || stored.UserID != userID {
+			return notFound("person", "id", id)
+		}
+		person = clonePerson(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+func (r *personRepository) Create(ctx context.Context, person *models.Person) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		person.UserID = userID
+		person.ID = t.nextID("people")
+		person.CreatedAt = time.Now()
+		person.UpdatedAt = person.CreatedAt
+		t.people[person.ID] = clonePerson(*person)
+		return nil
+	})
+}
+
+func (r *personRepository) Update(ctx context.Context, person *models.Person) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.people[person.ID]
+		if !ok || stored.UserID != userID {
+			return notFound("person", "id", person.ID)
+		}
+		person.UserID = userID
+		person.CreatedAt = stored.CreatedAt
+		person.UpdatedAt = time.Now()
+		t.people[person.ID] = clonePerson(*person)
+		return nil
+	})
+}
+
+func (r *personRepository) Delete(ctx context.Context, id int64) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if !t.ownsPerson(userID, id) {
+			return notFound("person", "id", id)
+		}
+		t.deletePerson(id)
+		return nil
+	})
+}
+
+func (t *tables) peopleOf(userID int64) iter.Seq[models.Person] {
+	return func(yield func(models.Person) bool) {
+		for _, person := range t.people {
+			if person.UserID == userID && !yield(person) {
+				return
+			}
+		}
+	}
+}
+
+func newestPersonFirst(a, b models.Person) int {
+	return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+}
+
+func clonePerson(person models.Person) models.Person {
+	person.SecondName = clonePtr(person.SecondName)
+	person.MiddleName = clonePtr(person.MiddleName)
+	person.Company = clonePtr(person.Company)
+	person.JobTitle = clonePtr(person.JobTitle)
+	return person
+}
+
+func (r *personRepository) RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		stored, ok := t.people[id]
+		if !ok || stored.UserID != userID {
+			return notFound("person", "id", id)
+		}
+		stored.CreatedAt, stored.UpdatedAt = createdAt, updatedAt
+		t.people[id] = stored
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type sessionRepository struct {
+	run runner
+}
+
+func (r *sessionRepository) GetByHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	var session *models.Session
+	err := r.run(ctx, func(t *tables) error {
+		for _, stored := range t.sessions {
+			if stored.TokenHash == tokenHash {
+				session = &stored
+				return nil
+			}
+		}
+		return nil
+	})
+	return session, err
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return r.run(ctx, func(t *tables) error {
+		if _, ok := t.users[session.UserID]; !ok {
+			return foreignKeyViolation("sessions", "user_id")
+		}
+		for _, stored := range t.sessions {
+			if stored.TokenHash == session.TokenHash {
+				return uniqueViolation("sessions", "token_hash")
+			}
+		}
+		session.ID = t.nextID("sessions")
+		session.CreatedAt = time.Now()
+		t.sessions[session.ID] = *session
+		return nil
+	})
+}
+
+func (r *sessionRepository) Delete(ctx context.Context, tokenHash string) error {
+	return r.run(ctx, func(t *tables) error {
+		for id, stored := range t.sessions {
+			if stored.TokenHash == tokenHash {
+				delete(t.sessions, id)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *sessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return r.run(ctx, func(t *tables) error {
+		for id, stored := range t.sessions {
+			if stored.Expired(now) {
+				delete(t.sessions, id)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,212 @@
+// Package memory provides thread-safe in-memory repositories that mirror the
+// constraints of the SQL schema, so the services and the HTTP API can be
+// exercised without a database.
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+var contactTypeNames = []string{"Email", "Phone", "Address", "Website", "Social Media"}
+
+var conversationTypeNames = []string{"Phone Call", "Video Call", "In-Person Meeting", "Text Message", "Email", "Social Media"}
+
+// Store is an in-memory services.UnitOfWork seeded with the same contact and
+// conversation types as the migrations. Transactions are serialized, and
+// Within must not use Store.Repositories while fn runs. Nothing is written
+// to disk, so encrypted fields are kept in plaintext.
+type Store struct {
+	mu   sync.Mutex
+	data *tables
+}
+
+func NewStore() *Store {
+	data := newTables()
+	now := time.Now()
+	for _, name := range contactTypeNames {
+		id := data.nextID("contact_types")
+		data.contactTypes[id] = models.ContactType{ID: id, Name: name, CreatedAt: now}
+	}
+	for _, name := range conversationTypeNames {
+		id := data.nextID("conversation_types")
+		data.conversationTypes[id] = models.ConversationType{ID: id, Name: name, CreatedAt: now}
+	}
+	return &Store{data: data}
+}
+
+// Repositories returns repositories that apply each call atomically on its
+// own.
+func (s *Store) Repositories() services.Repositories {
+	return newRepositories(s.autocommit)
+}
+
+func (s *Store) Within(ctx context.Context, fn func(repos services.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := s.data.clone()
+	if err := fn(newRepositories(tx.run)); err != nil {
+		return err
+	}
+	s.data = tx
+	return nil
+}
+
+// WithinSnapshot runs fn on a copy of the tables that is discarded
+// afterwards, so nothing fn writes is kept.
+func (s *Store) WithinSnapshot(ctx context.Context, fn func(repos services.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(newRepositories(s.data.clone().run))
+}
+
+func (s *Store) autocommit(ctx context.Context, fn func(t *tables) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := s.data.clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.data = tx
+	return nil
+}
+
+// runner applies fn to the tables visible to a repository, either as its own
+// transaction or as part of an enclosing one.
+type runner func(ctx context.Context, fn func(t *tables) error) error
+
+func newRepositories(run runner) services.Repositories {
+	return services.Repositories{
+		People:            &personRepository{run: run},
+		Contacts:          &contactRepository{run: run},
+		ConnectionSources: &connectionSourceRepository{run: run},
+		BirthDateInfo:     &birthDateInfoRepository{run: run},
+		Conversations:     &conversationRepository{run: run},
+		Tags:              &tagRepository{run: run},
+		APITokens:         &apiTokenRepository{run: run},
+		Users:             &userRepository{run: run},
+		Sessions:          &sessionRepository{run: run},
+		UserIdentities:    &userIdentityRepository{run: run},
+	}
+}
+
+// tables holds one snapshot of every table. Rows are stored by value with
+// their pointer fields copied, so snapshots can share rows safely.
+type tables struct {
+	sequences         map[string]int64
+	people            map[int64]models.Person
+	contactTypes      map[int64]models.ContactType
+	contacts          map[int64]models.Contact
+	conversationTypes map[int64]models.ConversationType
+	// typeOwners maps the user-owned types of both catalogues, keyed by
+	// table and id, to their user; types missing from it are shared.
+	typeOwners        map[typeKey]int64
+	conversations     map[int64]models.Conversation
+	connectionSources map[int64]models.ConnectionSource
+	birthDateInfo     map[int64]models.BirthDateInfo
+	// tags holds the sorted tags of each person; slices are replaced, never
+	// modified, so snapshots can share them.
+	tags           map[int64][]string
+	apiTokens      map[int64]models.APIToken
+	users          map[int64]models.User
+	sessions       map[int64]models.Session
+	userIdentities map[int64]models.UserIdentity
+}
+
+func newTables() *tables {
+	return &tables{
+		sequences:         map[string]int64{},
+		people:            map[int64]models.Person{},
+		contactTypes:      map[int64]models.ContactType{},
+		contacts:          map[int64]models.Contact{},
+		conversationTypes: map[int64]models.ConversationType{},
+		typeOwners:        map[typeKey]int64{},
+		conversations:     map[int64]models.Conversation{},
+		connectionSources: map[int64]models.ConnectionSource{},
+		birthDateInfo:     map[int64]models.BirthDateInfo{},
+		tags:              map[int64][]string{},
+		apiTokens:         map[int64]models.APIToken{},
+		users:             map[int64]models.User{},
+		sessions:          map[int64]models.Session{},
+		userIdentities:    map[int64]models.UserIdentity{},
+	}
+}
+
+func (t *tables) clone() *tables {
+	return &tables{
+		sequences:         maps.Clone(t.sequences),
+		people:            maps.Clone(t.people),
+		contactTypes:      maps.Clone(t.contactTypes),
+		contacts:          maps.Clone(t.contacts),
+		conversationTypes: maps.Clone(t.conversationTypes),
+		typeOwners:        maps.Clone(t.typeOwners),
+		conversations:     maps.Clone(t.conversations),
+		connectionSources: maps.Clone(t.connectionSources),
+		birthDateInfo:     maps.Clone(t.birthDateInfo),
+		tags:              maps.Clone(t.tags),
+		apiTokens:         maps.Clone(t.apiTokens),
+		users:             maps.Clone(t.users),
+		sessions:          maps.Clone(t.sessions),
+		userIdentities:    maps.Clone(t.userIdentities),
+	}
+}
+
+func (t *tables) run(ctx context.Context, fn func(t *tables) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn(t)
+}
+
+func (t *tables) nextID(table string) int64 {
+	t.sequences[table]++
+	return t.sequences[table]
+}
+
+// deletePerson removes the person together with their dependent rows and
+// clears references to them as an introducer, as the schema's ON DELETE
+// rules do.
+func (t *tables) deletePerson(id int64) {
+	delete(t.people, id)
+	delete(t.connectionSources, id)
+	delete(t.birthDateInfo, id)
+	delete(t.tags, id)
+	for contactID, contact := range t.contacts {
+		if contact.PersonID == id {
+			delete(t.contacts, contactID)
+		}
+	}
+	for conversationID, conversation := range t.conversations {
+		if conversation.PersonID == id {
+			delete(t.conversations, conversationID)
+		}
+	}
+	for personID, connectionSource := range t.connectionSources {
+		if connectionSource.IntroducerPersonID != nil && *connectionSource.IntroducerPersonID == id {
+			connectionSource.IntroducerPersonID = nil
+			t.connectionSources[personID] = connectionSource
+		}
+	}
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
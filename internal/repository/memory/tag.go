@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"slices"
+)
+
+type tagRepository struct {
+	run runner
+}
+
+func (r *tagRepository) GetByPersonID(ctx context.Context, personID int64) ([]string, error) {
+	userID, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	err = r.run(ctx, func(t *tables) error {
+		if t.ownsPerson(userID, personID) {
+			tags = append(tags, t.tags[personID]...)
+		}
+		return nil
+	})
+	return tags, err
+}
+
+func (r *tagRepository) Replace(ctx context.Context, personID int64, tags []string) error {
+	userID, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, func(t *tables) error {
+		if err := t.requirePerson(userID, personID); err != nil {
+			return err
+		}
+		sorted := slices.Sorted(slices.Values(tags))
+		if len(slices.Compact(slices.Clone(sorted))) != len(sorted) {
+			return uniqueViolation("person_tags", "name")
+		}
+		if len(sorted) == 0 {
+			delete(t.tags, personID)
+			return nil
+		}
+		t.tags[personID] = sorted
+		return nil
+	})
+}
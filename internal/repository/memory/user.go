@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type userRepository struct {
+	run runner
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	var user *models.User
+	err := r.run(ctx, func(t *tables) error {
+		if stored, ok := t.users[id]; ok {
+			user = &stored
+		}
+		return nil
+	})
+	return user, err
+}
+
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user *models.User
+	err := r.run(ctx, func(t *tables) error {
+		for _, stored := range t.users {
+			if stored.Username == username {
+				user = &stored
+				return nil
+			}
+		}
+		return nil
+	})
+	return user, err
+}
+
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return r.run(ctx, func(t *tables) error {
+		for _, stored := range t.users {
+			if stored.Username == user.Username {
+				return uniqueViolation("users", "username")
+			}
+		}
+		user.ID = t.nextID("users")
+		user.CreatedAt = time.Now()
+		user.UpdatedAt = user.CreatedAt
+		t.users[user.ID] = *user
+		return nil
+	})
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	return r.run(ctx, func(t *tables) error {
+		user, ok := t.users[id]
+		if !ok {
+			return notFound("user", "id", id)
+		}
+		user.PasswordHash = passwordHash
+		user.UpdatedAt = time.Now()
+		t.users[id] = user
+		return nil
+	})
+}
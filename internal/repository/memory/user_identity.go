@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type userIdentityRepository struct {
+	run runner
+}
+
+func (r *userIdentityRepository) GetBySubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	var identity *models.UserIdentity
+	err := r.run(ctx, func(t *tables) error {
+		for _, stored := range t.userIdentities {
+			if stored.Issuer == issuer && stored.Subject == subject {
+				identity = &stored
+				return nil
+			}
+		}
+		return nil
+	})
+	return identity, err
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return r.run(ctx, func(t *tables) error {
+		if _, ok := t.users[identity.UserID]; !ok {
+			return foreignKeyViolation("user_identities", "user_id")
+		}
+		for _, stored := range t.userIdentities {
+			if stored.Issuer == identity.Issuer && stored.Subject == identity.Subject {
+				return uniqueViolation("user_identities", "issuer_subject")
+			}
+		}
+		identity.ID = t.nextID("user_identities")
+		identity.CreatedAt = time.Now()
+		t.userIdentities[identity.ID] = *identity
+		return nil
+	})
+}
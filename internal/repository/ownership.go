@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+)
+
+// ErrNoUser is returned by repositories that act for a user when the context
+// does not carry one.
+var ErrNoUser = errors.New("no user in context")
+
+func ownerID(ctx context.Context) (int64, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return 0, ErrNoUser
+	}
+	return userID, nil
+}
+
+// requirePerson fails with a not found error unless the person belongs to the
+// user in ctx. Ownership never changes, so checking it before a write is as
+// safe as checking it in the write itself.
+func requirePerson(ctx context.Context, db *Executor, name string, personID int64) error {
+	owned, err := ownsPerson(ctx, db, name, personID)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return notFound("person", "id", personID)
+	}
+	return nil
+}
+
+// requireReference fails with a foreign key violation on table.column unless
+// the referenced person belongs to the user in ctx, hiding other users'
+// people behind the same error as missing ones.
+func requireReference(ctx context.Context, db *Executor, name, table, column string, personID int64) error {
+	owned, err := ownsPerson(ctx, db, name, personID)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return &ForeignKeyViolationError{Table: table, Constraint: table + "_" + column + "_fkey", Field: column}
+	}
+	return nil
+}
+
+// requireType fails with a foreign key violation on table.column unless the
+// type in typeTable is shared or belongs to the user in ctx.
+func requireType(ctx context.Context, db *Executor, name, typeTable string, typeID int64, table, column string) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+	var visible bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = ? AND (user_id IS NULL OR user_id = ?))`, typeTable)
+	if err := db.get(ctx, name, &visible, query, typeID, userID); err != nil {
+		return fmt.Errorf("failed to check %s %d: %w", typeTable, typeID, err)
+	}
+	if !visible {
+		return &ForeignKeyViolationError{Table: table, Constraint: table + "_" + column + "_fkey", Field: column}
+	}
+	return nil
+}
+
+// requireRecord fails with a not found error unless the row in table with the
+// given id belongs to one of the user's people.
+func requireRecord(ctx context.Context, db *Executor, name, table, entity string, id int64) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+	var owned bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s t JOIN people p ON p.id = t.person_id WHERE t.id = ? AND p.user_id = ?)`, table)
+	if err := db.get(ctx, name, &owned, query, id, userID); err != nil {
+		return fmt.Errorf("failed to check %s %d: %w", entity, id, err)
+	}
+	if !owned {
+		return notFound(entity, "id", id)
+	}
+	return nil
+}
+
+// restoreTimestamps sets created_at and updated_at of the row in table whose
+// column equals key, failing with a not found error unless it belongs to the
+// user in ctx.
+func restoreTimestamps(ctx context.Context, db *Executor, name, table, column, entity string, key int64, createdAt, updatedAt time.Time) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+	owned := "person_id IN (SELECT id FROM people WHERE user_id = ?)"
+	if table == "people" {
+		owned = "user_id = ?"
+	}
+	query := fmt.Sprintf(`UPDATE %s SET created_at = ?, updated_at = ? WHERE %s = ? AND %s`, table, column, owned)
+	result, err := db.exec(ctx, name, query, createdAt.UTC(), updatedAt.UTC(), key, userID)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s timestamps: %w", entity, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound(entity, strings.ReplaceAll(column, "_", " "), key)
+	}
+	return nil
+}
+
+func ownsPerson(ctx context.Context, db *Executor, name string, personID int64) (bool, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return false, err
+	}
+	var owned bool
+	query := `SELECT EXISTS (SELECT 1 FROM people WHERE id = ? AND user_id = ?)`
+	if err := db.get(ctx, name, &owned, query, personID, userID); err != nil {
+		return false, fmt.Errorf("failed to check person %d: %w", personID, err)
+	}
+	return owned, nil
+}
@@ -1,62 +1,112 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/lincentpega/pcrm/internal/models"
 )
 
-type PersonRepository struct {
-	db *sqlx.DB
+type sqlPersonRepository struct {
+	db *Executor
 }
 
-func NewPersonRepository(db *sqlx.DB) *PersonRepository {
-	return &PersonRepository{db: db}
+func NewPersonRepository(db *Executor) PersonRepository {
+	return &sqlPersonRepository{db: db}
 }
 
-func (r *PersonRepository) GetPaginated(page, limit int) ([]models.Person, error) {
+func (r *sqlPersonRepository) GetPaginated(ctx context.Context, page, limit int) ([]models.Person, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var people []models.Person
 	offset := (page - 1) * limit
 	
 	query := `
-		SELECT id, first_name, second_name, middle_name, created_at, updated_at
+		SELECT id, user_id, first_name, second_name, middle_name, company, job_title, created_at, updated_at
 		FROM people
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
 	`
 	
-	if err := r.db.Select(&people, query, limit, offset); err != nil {
+	if err := r.db.selectAll(ctx, "PersonRepository.GetPaginated", &people, query, userID, limit, offset); err != nil {
 		return nil, fmt.Errorf("failed to get paginated people: %w", err)
 	}
 	
 	return people, nil
 }
 
-func (r *PersonRepository) GetTotalCount() (int, error) {
+func (r *sqlPersonRepository) GetPageAfter(ctx context.Context, afterID int64, limit int) ([]models.Person, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var people []models.Person
+	query := `
+		SELECT id, user_id, first_name, second_name, middle_name, company, job_title, created_at, updated_at
+		FROM people
+		WHERE user_id = ? AND id > ?
+		ORDER BY id
+		LIMIT ?
+	`
+
+	if err := r.db.selectAll(ctx, "PersonRepository.GetPageAfter", &people, query, userID, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get people after %d: %w", afterID, err)
+	}
+
+	return people, nil
+}
+
+func (r *sqlPersonRepository) GetTotalCount(ctx context.Context) (int, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	var count int
-	query := `SELECT COUNT(*) FROM people`
+	query := `SELECT COUNT(*) FROM people WHERE user_id = ?`
 	
-	if err := r.db.Get(&count, query); err != nil {
+	if err := r.db.get(ctx, "PersonRepository.GetTotalCount", &count, query, userID); err != nil {
 		return 0, fmt.Errorf("failed to get people count: %w", err)
 	}
 	
 	return count, nil
 }
 
-func (r *PersonRepository) GetByID(id int64) (*models.Person, error) {
+func (r *sqlPersonRepository) CountAll(ctx context.Context) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM people`
+
+	if err := r.db.get(ctx, "PersonRepository.CountAll", &count, query); err != nil {
+		return 0, fmt.Errorf("failed to count people: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *sqlPersonRepository) GetByID(ctx context.Context, id int64) (*models.Person, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var person models.Person
 	query := `
-		SELECT id, first_name, second_name, middle_name, created_at, updated_at
+		SELECT id, user_id, first_name, second_name, middle_name, company, job_title, created_at, updated_at
 		FROM people
-		WHERE id = $1
+		WHERE id = ? AND user_id = ?
 	`
 	
-	if err := r.db.Get(&person, query, id); err != nil {
+	if err := r.db.get(ctx, "PersonRepository.GetByID", &person, query, id, userID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
+			return nil, notFound("person", "id", id)
 		}
 		return nil, fmt.Errorf("failed to get person by id %d: %w", id, err)
 	}
@@ -64,56 +114,61 @@ func (r *PersonRepository) GetByID(id int64) (*models.Person, error) {
 	return &person, nil
 }
 
-func (r *PersonRepository) Create(person *models.Person) error {
+func (r *sqlPersonRepository) Create(ctx context.Context, person *models.Person) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+	person.UserID = userID
+
 	query := `
-		INSERT INTO people (first_name, second_name, middle_name)
-		VALUES (:first_name, :second_name, :middle_name)
+		INSERT INTO people (user_id, first_name, second_name, middle_name, company, job_title)
+		VALUES (:user_id, :first_name, :second_name, :middle_name, :company, :job_title)
 		RETURNING id, created_at, updated_at
 	`
 	
-	rows, err := r.db.NamedQuery(query, person)
-	if err != nil {
+	if err := r.db.namedQueryRow(ctx, "PersonRepository.Create", query, person, &person.ID, &person.CreatedAt, &person.UpdatedAt); err != nil {
 		return fmt.Errorf("failed to create person: %w", err)
 	}
-	defer rows.Close()
-	
-	if rows.Next() {
-		if err := rows.Scan(&person.ID, &person.CreatedAt, &person.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan created person: %w", err)
-		}
-	}
 	
 	return nil
 }
 
-func (r *PersonRepository) Update(person *models.Person) error {
+func (r *sqlPersonRepository) Update(ctx context.Context, person *models.Person) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
+	person.UserID = userID
+
 	query := `
 		UPDATE people 
 		SET first_name = :first_name, second_name = :second_name, middle_name = :middle_name,
-		    updated_at = NOW()
-		WHERE id = :id
-		RETURNING updated_at
+		    company = :company, job_title = :job_title,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = :id AND user_id = :user_id
+		RETURNING created_at, updated_at
 	`
 	
-	rows, err := r.db.NamedQuery(query, person)
-	if err != nil {
-		return fmt.Errorf("failed to update person: %w", err)
-	}
-	defer rows.Close()
-	
-	if rows.Next() {
-		if err := rows.Scan(&person.UpdatedAt); err != nil {
-			return fmt.Errorf("failed to scan updated person: %w", err)
+	if err := r.db.namedQueryRow(ctx, "PersonRepository.Update", query, person, &person.CreatedAt, &person.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound("person", "id", person.ID)
 		}
+		return fmt.Errorf("failed to update person: %w", err)
 	}
 	
 	return nil
 }
 
-func (r *PersonRepository) Delete(id int64) error {
-	query := `DELETE FROM people WHERE id = $1`
+func (r *sqlPersonRepository) Delete(ctx context.Context, id int64) error {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return err
+	}
 	
-	result, err := r.db.Exec(query, id)
+	query := `DELETE FROM people WHERE id = ? AND user_id = ?`
+
+	result, err := r.db.exec(ctx, "PersonRepository.Delete", query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete person: %w", err)
 	}
@@ -124,9 +179,12 @@ func (r *PersonRepository) Delete(id int64) error {
 	}
 	
 	if rowsAffected == 0 {
-		return fmt.Errorf("person with id %d not found", id)
+		return notFound("person", "id", id)
 	}
 	
 	return nil
 }
 
+func (r *sqlPersonRepository) RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error {
+	return restoreTimestamps(ctx, r.db, "PersonRepository.RestoreTimestamps", "people", "id", "person", id, createdAt, updatedAt)
+}
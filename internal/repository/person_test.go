@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+)
+
+// addPeopleCreatedTogether adds n people to user 1 and gives them and the
+// person newSQLiteExecutor adds one creation time.
+func addPeopleCreatedTogether(t *testing.T, executor *Executor, n int) {
+	t.Helper()
+	ctx := context.Background()
+	for i := range n {
+		if _, err := executor.exec(ctx, "test", `INSERT INTO people (user_id, first_name) VALUES (1, ?)`, fmt.Sprintf("Person %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := executor.exec(ctx, "test", `UPDATE people SET created_at = '2024-01-01 00:00:00'`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPersonPagesWithSharedCreationTime(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	addPeopleCreatedTogether(t, executor, 6)
+	people := NewPersonRepository(executor)
+	ctx := auth.WithUserID(context.Background(), 1)
+	seen := map[int64]bool{}
+	var previous int64
+	for page := 1; page <= 4; page++ {
+		paged, err := people.GetPaginated(ctx, page, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, person := range paged {
+			if seen[person.ID] {
+				t.Errorf("person %d is on more than one page", person.ID)
+			}
+			if previous != 0 && person.ID >= previous {
+				t.Errorf("person %d follows person %d, want descending ids for equal creation times", person.ID, previous)
+			}
+			seen[person.ID] = true
+			previous = person.ID
+		}
+	}
+	if len(seen) != 7 {
+		t.Errorf("pages hold %d people, want 7", len(seen))
+	}
+}
+
+func TestPersonGetPageAfter(t *testing.T) {
+	executor := newSQLiteExecutor(t)
+	addPeopleCreatedTogether(t, executor, 4)
+	people := NewPersonRepository(executor)
+	ctx := auth.WithUserID(context.Background(), 1)
+	var ids []int64
+	for afterID := int64(0); ; {
+		page, err := people.GetPageAfter(ctx, afterID, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, person := range page {
+			ids = append(ids, person.ID)
+		}
+		if len(page) < 2 {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5]" {
+		t.Errorf("ids = %v, want [1 2 3 4 5]", ids)
+	}
+	other, err := people.GetPageAfter(auth.WithUserID(context.Background(), 2), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 0 {
+		t.Errorf("another user reads %d people, want 0", len(other))
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// The repositories for people, their records and API tokens act for the user
+// in the context (see auth.WithUserID): they only return and change that
+// user's data and fail with ErrNoUser when the context has no user. Methods
+// documented as spanning all users are the exception. RestoreTimestamps sets
+// the creation and update time of a record restored from a backup, which are
+// otherwise set by the database.
+
+// PersonRepository persists people. Deleting a person removes every record
+// that belongs to them and clears references to them as an introducer.
+// GetPaginated pages through the user's people newest first, while
+// GetPageAfter returns up to limit of them with an id above afterID, ordered
+// by id, for reading them all. CountAll counts the people of all users.
+type PersonRepository interface {
+	GetPaginated(ctx context.Context, page, limit int) ([]models.Person, error)
+	GetPageAfter(ctx context.Context, afterID int64, limit int) ([]models.Person, error)
+	GetTotalCount(ctx context.Context) (int, error)
+	CountAll(ctx context.Context) (int, error)
+	GetByID(ctx context.Context, id int64) (*models.Person, error)
+	Create(ctx context.Context, person *models.Person) error
+	Update(ctx context.Context, person *models.Person) error
+	Delete(ctx context.Context, id int64) error
+	RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error
+}
+
+// ContactRepository persists contacts and reads the contact type catalogue,
+// which holds shared types and the user's own ones; CreateContactType adds
+// one of the latter, with a name unique among the user's types. GetByContactTypeID
+// returns all of the user's contacts of a type, ordered by id. Contact content
+// is encrypted at rest. Reencrypt re-seals, across all users, up to limit
+// contacts not encrypted under the active key and returns how many changed.
+type ContactRepository interface {
+	GetContactTypes(ctx context.Context) ([]models.ContactType, error)
+	CreateContactType(ctx context.Context, contactType *models.ContactType) error
+	GetByPersonID(ctx context.Context, personID int64) ([]models.Contact, error)
+	GetByContactTypeID(ctx context.Context, contactTypeID int64) ([]models.Contact, error)
+	GetByID(ctx context.Context, id int64) (*models.Contact, error)
+	Create(ctx context.Context, contact *models.Contact) error
+	Update(ctx context.Context, contact *models.Contact) error
+	Delete(ctx context.Context, id int64) error
+	RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error
+	Reencrypt(ctx context.Context, limit int) (int, error)
+}
+
+// ConversationRepository persists conversations and reads the conversation
+// type catalogue, which holds shared types and the user's own ones;
+// CreateConversationType adds one of the latter, with a name unique among
+// the user's types.
+// Notes are encrypted at rest. CountSince counts and Reencrypt re-seals the
+// conversations of all users.
+type ConversationRepository interface {
+	GetConversationTypes(ctx context.Context) ([]models.ConversationType, error)
+	CreateConversationType(ctx context.Context, conversationType *models.ConversationType) error
+	GetByPersonID(ctx context.Context, personID int64) ([]models.Conversation, error)
+	GetByID(ctx context.Context, id int64) (*models.Conversation, error)
+	CountSince(ctx context.Context, since time.Time) (int, error)
+	Create(ctx context.Context, conversation *models.Conversation) error
+	Update(ctx context.Context, conversation *models.Conversation) error
+	Delete(ctx context.Context, id int64) error
+	RestoreTimestamps(ctx context.Context, id int64, createdAt, updatedAt time.Time) error
+	Reencrypt(ctx context.Context, limit int) (int, error)
+}
+
+// ConnectionSourceRepository persists the single connection source a person
+// may have. GetByPersonID returns nil when the person has none, and
+// GetByIntroducerPersonID returns the connection sources of the people the
+// given person introduced, ordered by person id. Meeting stories are
+// encrypted at rest, and Reencrypt re-seals those of all users.
+type ConnectionSourceRepository interface {
+	GetByPersonID(ctx context.Context, personID int64) (*models.ConnectionSource, error)
+	GetByIntroducerPersonID(ctx context.Context, introducerPersonID int64) ([]models.ConnectionSource, error)
+	Create(ctx context.Context, connectionSource *models.ConnectionSource) error
+	Update(ctx context.Context, connectionSource *models.ConnectionSource) error
+	Upsert(ctx context.Context, connectionSource *models.ConnectionSource) error
+	Delete(ctx context.Context, personID int64) error
+	RestoreTimestamps(ctx context.Context, personID int64, createdAt, updatedAt time.Time) error
+	Reencrypt(ctx context.Context, limit int) (int, error)
+}
+
+// TagRepository persists the free-form labels of a person. Tags are
+// returned sorted by name, and Replace sets the complete list, which must
+// not contain duplicates.
+type TagRepository interface {
+	GetByPersonID(ctx context.Context, personID int64) ([]string, error)
+	Replace(ctx context.Context, personID int64, tags []string) error
+}
+
+// APITokenRepository persists personal access tokens, looked up by the hash
+// of their secret. GetByHash and UpdateLastUsed authenticate requests and so
+// span all users; GetByHash returns a *NotFoundError when no token has that
+// hash.
+type APITokenRepository interface {
+	List(ctx context.Context) ([]models.APIToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*models.APIToken, error)
+	Create(ctx context.Context, token *models.APIToken) error
+	UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// UserRepository persists accounts. Lookups return nil when no user matches.
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	UpdatePassword(ctx context.Context, id int64, passwordHash string) error
+}
+
+// SessionRepository persists browser logins, looked up by the hash of their
+// cookie value. GetByHash returns nil when no session has that hash.
+type SessionRepository interface {
+	GetByHash(ctx context.Context, tokenHash string) (*models.Session, error)
+	Create(ctx context.Context, session *models.Session) error
+	Delete(ctx context.Context, tokenHash string) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// UserIdentityRepository persists links between users and OpenID Connect
+// subjects. GetBySubject returns nil when the subject is not linked.
+type UserIdentityRepository interface {
+	GetBySubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error)
+	Create(ctx context.Context, identity *models.UserIdentity) error
+}
+
+// BirthDateInfoRepository persists the single birth date record a person may
+// have. GetByPersonID returns nil when the person has none.
+type BirthDateInfoRepository interface {
+	GetByPersonID(ctx context.Context, personID int64) (*models.BirthDateInfo, error)
+	Create(ctx context.Context, birthDateInfo *models.BirthDateInfo) error
+	Update(ctx context.Context, birthDateInfo *models.BirthDateInfo) error
+	Upsert(ctx context.Context, birthDateInfo *models.BirthDateInfo) error
+	Delete(ctx context.Context, personID int64) error
+	RestoreTimestamps(ctx context.Context, personID int64, createdAt, updatedAt time.Time) error
+}
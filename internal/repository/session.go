@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type sqlSessionRepository struct {
+	db *Executor
+}
+
+func NewSessionRepository(db *Executor) SessionRepository {
+	return &sqlSessionRepository{db: db}
+}
+
+func (r *sqlSessionRepository) GetByHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	var session models.Session
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at
+		FROM sessions
+		WHERE token_hash = ?
+	`
+
+	if err := r.db.get(ctx, "SessionRepository.GetByHash", &session, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *sqlSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (user_id, token_hash, expires_at)
+		VALUES (:user_id, :token_hash, :expires_at)
+		RETURNING id, created_at
+	`
+
+	if err := r.db.namedQueryRow(ctx, "SessionRepository.Create", query, session, &session.ID, &session.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqlSessionRepository) Delete(ctx context.Context, tokenHash string) error {
+	query := `DELETE FROM sessions WHERE token_hash = ?`
+
+	if _, err := r.db.exec(ctx, "SessionRepository.Delete", query, tokenHash); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqlSessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	query := `DELETE FROM sessions WHERE expires_at <= ?`
+
+	if _, err := r.db.exec(ctx, "SessionRepository.DeleteExpired", query, now.UTC()); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+type sqlTagRepository struct {
+	db *Executor
+}
+
+func NewTagRepository(db *Executor) TagRepository {
+	return &sqlTagRepository{db: db}
+}
+
+func (r *sqlTagRepository) GetByPersonID(ctx context.Context, personID int64) ([]string, error) {
+	userID, err := ownerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	query := `
+		SELECT name
+		FROM person_tags
+		WHERE person_id = ? AND person_id IN (SELECT id FROM people WHERE user_id = ?)
+		ORDER BY name
+	`
+	if err := r.db.selectAll(ctx, "TagRepository.GetByPersonID", &tags, query, personID, userID); err != nil {
+		return nil, fmt.Errorf("failed to get tags for person %d: %w", personID, err)
+	}
+	return tags, nil
+}
+
+func (r *sqlTagRepository) Replace(ctx context.Context, personID int64, tags []string) error {
+	if err := requirePerson(ctx, r.db, "TagRepository.Replace", personID); err != nil {
+		return err
+	}
+	if _, err := r.db.exec(ctx, "TagRepository.Replace", `DELETE FROM person_tags WHERE person_id = ?`, personID); err != nil {
+		return fmt.Errorf("failed to clear tags of person %d: %w", personID, err)
+	}
+	for _, tag := range tags {
+		if _, err := r.db.exec(ctx, "TagRepository.Replace", `INSERT INTO person_tags (person_id, name) VALUES (?, ?)`, personID, tag); err != nil {
+			return fmt.Errorf("failed to tag person %d: %w", personID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type sqlUserRepository struct {
+	db *Executor
+}
+
+func NewUserRepository(db *Executor) UserRepository {
+	return &sqlUserRepository{db: db}
+}
+
+func (r *sqlUserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	var user models.User
+	query := `
+		SELECT id, username, password_hash, created_at, updated_at
+		FROM users
+		WHERE id = ?
+	`
+
+	if err := r.db.get(ctx, "UserRepository.GetByID", &user, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user by id %d: %w", id, err)
+	}
+
+	return &user, nil
+}
+
+func (r *sqlUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	query := `
+		SELECT id, username, password_hash, created_at, updated_at
+		FROM users
+		WHERE username = ?
+	`
+
+	if err := r.db.get(ctx, "UserRepository.GetByUsername", &user, query, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user %q: %w", username, err)
+	}
+
+	return &user, nil
+}
+
+func (r *sqlUserRepository) Create(ctx context.Context, user *models.User) error {
+	query := `
+		INSERT INTO users (username, password_hash)
+		VALUES (:username, :password_hash)
+		RETURNING id, created_at, updated_at
+	`
+
+	if err := r.db.namedQueryRow(ctx, "UserRepository.Create", query, user, &user.ID, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqlUserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	query := `UPDATE users SET password_hash = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	result, err := r.db.exec(ctx, "UserRepository.UpdatePassword", query, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound("user", "id", id)
+	}
+
+	return nil
+}
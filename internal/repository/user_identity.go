@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type sqlUserIdentityRepository struct {
+	db *Executor
+}
+
+func NewUserIdentityRepository(db *Executor) UserIdentityRepository {
+	return &sqlUserIdentityRepository{db: db}
+}
+
+func (r *sqlUserIdentityRepository) GetBySubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	query := `
+		SELECT id, user_id, issuer, subject, created_at
+		FROM user_identities
+		WHERE issuer = ? AND subject = ?
+	`
+
+	if err := r.db.get(ctx, "UserIdentityRepository.GetBySubject", &identity, query, issuer, subject); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+func (r *sqlUserIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, issuer, subject)
+		VALUES (:user_id, :issuer, :subject)
+		RETURNING id, created_at
+	`
+
+	if err := r.db.namedQueryRow(ctx, "UserIdentityRepository.Create", query, identity, &identity.ID, &identity.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// exportPageSize is the number of people read at a time while exporting.
+const exportPageSize = 100
+
+// errDryRun rolls back an import that was only checked.
+var errDryRun = errors.New("dry run")
+
+// ArchiveService exports all records of the user as an archive and imports
+// archives.
+type ArchiveService struct {
+	uow UnitOfWork
+}
+
+func NewArchiveService(uow UnitOfWork) *ArchiveService {
+	return &ArchiveService{uow: uow}
+}
+
+// Export reads every record of the user from a single snapshot. People are
+// ordered by id and their records follow the same order.
+func (s *ArchiveService) Export(ctx context.Context) (*models.Archive, error) {
+	archive := &models.Archive{Tags: make(map[int64][]string)}
+	err := s.uow.WithinSnapshot(ctx, func(repos Repositories) error {
+		var err error
+		if archive.ContactTypes, err = repos.Contacts.GetContactTypes(ctx); err != nil {
+			return err
+		}
+		if archive.ConversationTypes, err = repos.Conversations.GetConversationTypes(ctx); err != nil {
+			return err
+		}
+		if archive.People, err = allPeople(ctx, repos); err != nil {
+			return err
+		}
+		for _, person := range archive.People {
+			profile, err := loadPersonProfile(ctx, repos, person)
+			if err != nil {
+				return err
+			}
+			archive.Contacts = append(archive.Contacts, profile.Contacts...)
+			if profile.ConnectionSource != nil {
+				archive.ConnectionSources = append(archive.ConnectionSources, *profile.ConnectionSource)
+			}
+			if profile.BirthDateInfo != nil {
+				archive.BirthDateInfo = append(archive.BirthDateInfo, *profile.BirthDateInfo)
+			}
+			archive.Conversations = append(archive.Conversations, profile.Conversations...)
+			if len(profile.Tags) > 0 {
+				archive.Tags[person.ID] = profile.Tags
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// allPeople reads every person of the user, ordered by id. It pages by id
+// rather than by offset, so that people created at the same time are neither
+// skipped nor read twice.
+func allPeople(ctx context.Context, repos Repositories) ([]models.Person, error) {
+	var all []models.Person
+	var afterID int64
+	for {
+		people, err := repos.People.GetPageAfter(ctx, afterID, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, people...)
+		if len(people) < exportPageSize {
+			return all, nil
+		}
+		afterID = people[len(people)-1].ID
+	}
+}
+
+// loadPersonProfile reads the records that describe the person.
+func loadPersonProfile(ctx context.Context, repos Repositories, person models.Person) (models.PersonProfile, error) {
+	profile := models.PersonProfile{Person: person}
+	var err error
+	if profile.Contacts, err = repos.Contacts.GetByPersonID(ctx, person.ID); err != nil {
+		return profile, err
+	}
+	if profile.ConnectionSource, err = repos.ConnectionSources.GetByPersonID(ctx, person.ID); err != nil {
+		return profile, err
+	}
+	if profile.BirthDateInfo, err = repos.BirthDateInfo.GetByPersonID(ctx, person.ID); err != nil {
+		return profile, err
+	}
+	if profile.Conversations, err = repos.Conversations.GetByPersonID(ctx, person.ID); err != nil {
+		return profile, err
+	}
+	if profile.Tags, err = repos.Tags.GetByPersonID(ctx, person.ID); err != nil {
+		return profile, err
+	}
+	return profile, nil
+}
+
+// Import stores every record of the archive for the user in one transaction,
+// next to the records the user already has. Records get new ids and
+// references between them are remapped; types are matched by name and
+// created when the user has no type of that name. A dry run performs the
+// import and rolls it back, so it reports the same result and errors.
+func (s *ArchiveService) Import(ctx context.Context, archive *models.Archive, dryRun bool) (*models.ImportResult, error) {
+	var result *models.ImportResult
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		var err error
+		if result, err = importArchive(ctx, repos, archive); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		result.DryRun = true
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func importArchive(ctx context.Context, repos Repositories, archive *models.Archive) (*models.ImportResult, error) {
+	result := &models.ImportResult{PersonIDs: make(map[int64]int64, len(archive.People))}
+	contactTypeIDs, err := importContactTypes(ctx, repos, archive.ContactTypes, result)
+	if err != nil {
+		return nil, err
+	}
+	conversationTypeIDs, err := importConversationTypes(ctx, repos, archive.ConversationTypes, result)
+	if err != nil {
+		return nil, err
+	}
+	for _, person := range archive.People {
+		archiveID, createdAt, updatedAt := person.ID, person.CreatedAt, person.UpdatedAt
+		if err := repos.People.Create(ctx, &person); err != nil {
+			return nil, err
+		}
+		if err := restoreTimestamps(ctx, repos.People.RestoreTimestamps, person.ID, createdAt, updatedAt); err != nil {
+			return nil, err
+		}
+		if tags := archive.Tags[archiveID]; len(tags) > 0 {
+			if err := repos.Tags.Replace(ctx, person.ID, tags); err != nil {
+				return nil, err
+			}
+		}
+		result.PersonIDs[archiveID] = person.ID
+		result.People++
+	}
+	for _, contact := range archive.Contacts {
+		createdAt, updatedAt := contact.CreatedAt, contact.UpdatedAt
+		contact.PersonID = result.PersonIDs[contact.PersonID]
+		contact.ContactTypeID = contactTypeIDs[contact.ContactTypeID]
+		if err := repos.Contacts.Create(ctx, &contact); err != nil {
+			return nil, err
+		}
+		if err := restoreTimestamps(ctx, repos.Contacts.RestoreTimestamps, contact.ID, createdAt, updatedAt); err != nil {
+			return nil, err
+		}
+		result.Contacts++
+	}
+	for _, connectionSource := range archive.ConnectionSources {
+		createdAt, updatedAt := connectionSource.CreatedAt, connectionSource.UpdatedAt
+		connectionSource.PersonID = result.PersonIDs[connectionSource.PersonID]
+		if connectionSource.IntroducerPersonID != nil {
+			introducerID := result.PersonIDs[*connectionSource.IntroducerPersonID]
+			connectionSource.IntroducerPersonID = &introducerID
+		}
+		if err := repos.ConnectionSources.Create(ctx, &connectionSource); err != nil {
+			return nil, err
+		}
+		if err := restoreTimestamps(ctx, repos.ConnectionSources.RestoreTimestamps, connectionSource.PersonID, createdAt, updatedAt); err != nil {
+			return nil, err
+		}
+		result.ConnectionSources++
+	}
+	for _, birthDateInfo := range archive.BirthDateInfo {
+		createdAt, updatedAt := birthDateInfo.CreatedAt, birthDateInfo.UpdatedAt
+		birthDateInfo.PersonID = result.PersonIDs[birthDateInfo.PersonID]
+		if err := repos.BirthDateInfo.Create(ctx, &birthDateInfo); err != nil {
+			return nil, err
+		}
+		if err := restoreTimestamps(ctx, repos.BirthDateInfo.RestoreTimestamps, birthDateInfo.PersonID, createdAt, updatedAt); err != nil {
+			return nil, err
+		}
+		result.BirthDateInfo++
+	}
+	for _, conversation := range archive.Conversations {
+		createdAt, updatedAt := conversation.CreatedAt, conversation.UpdatedAt
+		conversation.PersonID = result.PersonIDs[conversation.PersonID]
+		conversation.ConversationTypeID = conversationTypeIDs[conversation.ConversationTypeID]
+		if err := repos.Conversations.Create(ctx, &conversation); err != nil {
+			return nil, err
+		}
+		if err := restoreTimestamps(ctx, repos.Conversations.RestoreTimestamps, conversation.ID, createdAt, updatedAt); err != nil {
+			return nil, err
+		}
+		result.Conversations++
+	}
+	return result, nil
+}
+
+// importContactTypes maps the archive id of every contact type to the id of
+// the user's type of the same name, creating the types that are missing.
+func importContactTypes(ctx context.Context, repos Repositories, types []models.ContactType, result *models.ImportResult) (map[int64]int64, error) {
+	existing, err := repos.Contacts.GetContactTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]int64, len(existing))
+	for _, contactType := range existing {
+		byName[contactType.Name] = contactType.ID
+	}
+	ids := make(map[int64]int64, len(types))
+	for _, contactType := range types {
+		id, ok := byName[contactType.Name]
+		if !ok {
+			created := models.ContactType{Name: contactType.Name}
+			if err := repos.Contacts.CreateContactType(ctx, &created); err != nil {
+				return nil, err
+			}
+			id = created.ID
+			result.ContactTypes++
+		}
+		ids[contactType.ID] = id
+	}
+	return ids, nil
+}
+
+// importConversationTypes is importContactTypes for conversation types.
+func importConversationTypes(ctx context.Context, repos Repositories, types []models.ConversationType, result *models.ImportResult) (map[int64]int64, error) {
+	existing, err := repos.Conversations.GetConversationTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]int64, len(existing))
+	for _, conversationType := range existing {
+		byName[conversationType.Name] = conversationType.ID
+	}
+	ids := make(map[int64]int64, len(types))
+	for _, conversationType := range types {
+		id, ok := byName[conversationType.Name]
+		if !ok {
+			created := models.ConversationType{Name: conversationType.Name}
+			if err := repos.Conversations.CreateConversationType(ctx, &created); err != nil {
+				return nil, err
+			}
+			id = created.ID
+			result.ConversationTypes++
+		}
+		ids[conversationType.ID] = id
+	}
+	return ids, nil
+}
+
+// restoreTimestamps sets the archived creation and update time of a record
+// stored under key. Records archived without a creation time keep the time
+// of the import.
+func restoreTimestamps(ctx context.Context, restore func(ctx context.Context, key int64, createdAt, updatedAt time.Time) error, key int64, createdAt, updatedAt time.Time) error {
+	if createdAt.IsZero() {
+		return nil
+	}
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+	return restore(ctx, key, createdAt.UTC(), updatedAt.UTC())
+}
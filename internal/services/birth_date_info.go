@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type BirthDateInfoService struct {
+	uow UnitOfWork
+}
+
+func NewBirthDateInfoService(uow UnitOfWork) *BirthDateInfoService {
+	return &BirthDateInfoService{uow: uow}
+}
+
+// GetBirthDateInfo returns nil without an error when the person exists but
+// has no birth date info recorded.
+func (s *BirthDateInfoService) GetBirthDateInfo(ctx context.Context, personID int64) (*models.BirthDateInfo, error) {
+	var birthDateInfo *models.BirthDateInfo
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		var err error
+		birthDateInfo, err = repos.BirthDateInfo.GetByPersonID(ctx, personID)
+		return err
+	})
+	return birthDateInfo, err
+}
+
+// UpsertBirthDateInfo creates or replaces the person's birth date info
+// and reports whether a new record was created.
+func (s *BirthDateInfoService) UpsertBirthDateInfo(ctx context.Context, birthDateInfo *models.BirthDateInfo) (bool, error) {
+	var created bool
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, birthDateInfo.PersonID); err != nil {
+			return err
+		}
+		existing, err := repos.BirthDateInfo.GetByPersonID(ctx, birthDateInfo.PersonID)
+		if err != nil {
+			return err
+		}
+		created = existing == nil
+		return repos.BirthDateInfo.Upsert(ctx, birthDateInfo)
+	})
+	return created, err
+}
+
+func (s *BirthDateInfoService) DeleteBirthDateInfo(ctx context.Context, personID int64) error {
+	return s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		return repos.BirthDateInfo.Delete(ctx, personID)
+	})
+}
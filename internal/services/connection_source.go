@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type ConnectionSourceService struct {
+	uow UnitOfWork
+}
+
+func NewConnectionSourceService(uow UnitOfWork) *ConnectionSourceService {
+	return &ConnectionSourceService{uow: uow}
+}
+
+// GetConnectionSource returns nil without an error when the person exists but
+// has no connection source recorded.
+func (s *ConnectionSourceService) GetConnectionSource(ctx context.Context, personID int64) (*models.ConnectionSource, error) {
+	var connectionSource *models.ConnectionSource
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		var err error
+		connectionSource, err = repos.ConnectionSources.GetByPersonID(ctx, personID)
+		return err
+	})
+	return connectionSource, err
+}
+
+// UpsertConnectionSource creates or replaces the person's connection source
+// and reports whether a new record was created.
+func (s *ConnectionSourceService) UpsertConnectionSource(ctx context.Context, connectionSource *models.ConnectionSource) (bool, error) {
+	var created bool
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, connectionSource.PersonID); err != nil {
+			return err
+		}
+		existing, err := repos.ConnectionSources.GetByPersonID(ctx, connectionSource.PersonID)
+		if err != nil {
+			return err
+		}
+		created = existing == nil
+		return repos.ConnectionSources.Upsert(ctx, connectionSource)
+	})
+	return created, err
+}
+
+func (s *ConnectionSourceService) DeleteConnectionSource(ctx context.Context, personID int64) error {
+	return s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		return repos.ConnectionSources.Delete(ctx, personID)
+	})
+}
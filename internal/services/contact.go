@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type ContactService struct {
+	uow UnitOfWork
+}
+
+func NewContactService(uow UnitOfWork) *ContactService {
+	return &ContactService{uow: uow}
+}
+
+func (s *ContactService) ListContactTypes(ctx context.Context) ([]models.ContactType, error) {
+	return s.uow.Repositories().Contacts.GetContactTypes(ctx)
+}
+
+func (s *ContactService) ListPersonContacts(ctx context.Context, personID int64) ([]models.Contact, error) {
+	var contacts []models.Contact
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		var err error
+		contacts, err = repos.Contacts.GetByPersonID(ctx, personID)
+		return err
+	})
+	return contacts, err
+}
+
+func (s *ContactService) GetContact(ctx context.Context, id int64) (*models.Contact, error) {
+	return s.uow.Repositories().Contacts.GetByID(ctx, id)
+}
+
+// CreateContact stores the contact for an existing person and returns it
+// with its contact type resolved.
+func (s *ContactService) CreateContact(ctx context.Context, contact *models.Contact) (*models.Contact, error) {
+	var created *models.Contact
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, contact.PersonID); err != nil {
+			return err
+		}
+		var err error
+		created, err = createContact(ctx, repos, contact.PersonID, contact)
+		return err
+	})
+	return created, err
+}
+
+// UpdateContact changes the contact type and content and returns the contact
+// with its contact type resolved.
+func (s *ContactService) UpdateContact(ctx context.Context, contact *models.Contact) (*models.Contact, error) {
+	var updated *models.Contact
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if err := repos.Contacts.Update(ctx, contact); err != nil {
+			return err
+		}
+		var err error
+		updated, err = repos.Contacts.GetByID(ctx, contact.ID)
+		return err
+	})
+	return updated, err
+}
+
+func (s *ContactService) DeleteContact(ctx context.Context, id int64) error {
+	return s.uow.Repositories().Contacts.Delete(ctx, id)
+}
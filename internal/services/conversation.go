@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type ConversationService struct {
+	uow UnitOfWork
+}
+
+func NewConversationService(uow UnitOfWork) *ConversationService {
+	return &ConversationService{uow: uow}
+}
+
+func (s *ConversationService) ListConversationTypes(ctx context.Context) ([]models.ConversationType, error) {
+	return s.uow.Repositories().Conversations.GetConversationTypes(ctx)
+}
+
+func (s *ConversationService) ListPersonConversations(ctx context.Context, personID int64) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		var err error
+		conversations, err = repos.Conversations.GetByPersonID(ctx, personID)
+		return err
+	})
+	return conversations, err
+}
+
+func (s *ConversationService) GetConversation(ctx context.Context, id int64) (*models.Conversation, error) {
+	return s.uow.Repositories().Conversations.GetByID(ctx, id)
+}
+
+// CreateConversation logs the conversation for an existing person and returns
+// it with its conversation type resolved.
+func (s *ConversationService) CreateConversation(ctx context.Context, conversation *models.Conversation) (*models.Conversation, error) {
+	var created *models.Conversation
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, conversation.PersonID); err != nil {
+			return err
+		}
+		var err error
+		created, err = createConversation(ctx, repos, conversation.PersonID, conversation)
+		return err
+	})
+	return created, err
+}
+
+// UpdateConversation changes the conversation and returns it with its
+// conversation type resolved.
+func (s *ConversationService) UpdateConversation(ctx context.Context, conversation *models.Conversation) (*models.Conversation, error) {
+	var updated *models.Conversation
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if err := repos.Conversations.Update(ctx, conversation); err != nil {
+			return err
+		}
+		var err error
+		updated, err = repos.Conversations.GetByID(ctx, conversation.ID)
+		return err
+	})
+	return updated, err
+}
+
+func (s *ConversationService) DeleteConversation(ctx context.Context, id int64) error {
+	return s.uow.Repositories().Conversations.Delete(ctx, id)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// DossierService gathers what is known about people for exporting them as
+// notes.
+type DossierService struct {
+	uow UnitOfWork
+}
+
+func NewDossierService(uow UnitOfWork) *DossierService {
+	return &DossierService{uow: uow}
+}
+
+// GetDossier reads the dossier of the person along with every person of the
+// user, whom the notes of the dossiers are named after, from a single
+// snapshot.
+func (s *DossierService) GetDossier(ctx context.Context, personID int64) (*models.Dossier, []models.Person, error) {
+	var dossier models.Dossier
+	var people []models.Person
+	err := s.uow.WithinSnapshot(ctx, func(repos Repositories) error {
+		person, err := repos.People.GetByID(ctx, personID)
+		if err != nil {
+			return err
+		}
+		if people, err = allPeople(ctx, repos); err != nil {
+			return err
+		}
+		dossier, err = loadDossier(ctx, repos, *person, peopleByID(people))
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &dossier, people, nil
+}
+
+// ListDossiers reads the dossier of every person of the user from a single
+// snapshot, ordered by person id, along with the people themselves.
+func (s *DossierService) ListDossiers(ctx context.Context) ([]models.Dossier, []models.Person, error) {
+	var dossiers []models.Dossier
+	var people []models.Person
+	err := s.uow.WithinSnapshot(ctx, func(repos Repositories) error {
+		var err error
+		if people, err = allPeople(ctx, repos); err != nil {
+			return err
+		}
+		byID := peopleByID(people)
+		dossiers = make([]models.Dossier, len(people))
+		for i, person := range people {
+			if dossiers[i], err = loadDossier(ctx, repos, person, byID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return dossiers, people, nil
+}
+
+func loadDossier(ctx context.Context, repos Repositories, person models.Person, people map[int64]models.Person) (models.Dossier, error) {
+	profile, err := loadPersonProfile(ctx, repos, person)
+	if err != nil {
+		return models.Dossier{}, err
+	}
+	dossier := models.Dossier{Profile: profile}
+	if source := profile.ConnectionSource; source != nil && source.IntroducerPersonID != nil {
+		if introducer, ok := people[*source.IntroducerPersonID]; ok {
+			dossier.Introducer = &introducer
+		}
+	}
+	introduced, err := repos.ConnectionSources.GetByIntroducerPersonID(ctx, person.ID)
+	if err != nil {
+		return models.Dossier{}, err
+	}
+	for _, source := range introduced {
+		if introducedPerson, ok := people[source.PersonID]; ok {
+			dossier.Introduced = append(dossier.Introduced, introducedPerson)
+		}
+	}
+	if dossier.Introducer != nil {
+		alongside, err := repos.ConnectionSources.GetByIntroducerPersonID(ctx, dossier.Introducer.ID)
+		if err != nil {
+			return models.Dossier{}, err
+		}
+		for _, source := range alongside {
+			if other, ok := people[source.PersonID]; ok && other.ID != person.ID {
+				dossier.IntroducedAlongside = append(dossier.IntroducedAlongside, other)
+			}
+		}
+	}
+	dossier.Colleagues = colleaguesOf(person, people)
+	dossier.FollowUps = followUpsOf(profile.Conversations)
+	return dossier, nil
+}
+
+// colleaguesOf lists the other people whose company matches that of the
+// person, ignoring case, ordered by id.
+func colleaguesOf(person models.Person, people map[int64]models.Person) []models.Person {
+	company := trimmedValue(person.Company)
+	if company == "" {
+		return nil
+	}
+	var colleagues []models.Person
+	for _, other := range people {
+		if other.ID != person.ID && strings.EqualFold(trimmedValue(other.Company), company) {
+			colleagues = append(colleagues, other)
+		}
+	}
+	slices.SortFunc(colleagues, func(a, b models.Person) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return colleagues
+}
+
+// followUpsOf picks the conversations the person started after the user
+// last reached out, most recent first, as those still wait for an answer.
+func followUpsOf(conversations []models.Conversation) []models.Conversation {
+	var answered time.Time
+	for _, conversation := range conversations {
+		if conversation.Initiator == "owner" && conversation.CreatedAt.After(answered) {
+			answered = conversation.CreatedAt
+		}
+	}
+	var followUps []models.Conversation
+	for _, conversation := range conversations {
+		if conversation.Initiator != "owner" && conversation.CreatedAt.After(answered) {
+			followUps = append(followUps, conversation)
+		}
+	}
+	slices.SortStableFunc(followUps, func(a, b models.Conversation) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return followUps
+}
+
+func trimmedValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
+
+func peopleByID(people []models.Person) map[int64]models.Person {
+	byID := make(map[int64]models.Person, len(people))
+	for _, person := range people {
+		byID[person.ID] = person
+	}
+	return byID
+}
@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/encryption"
+	"github.com/lincentpega/pcrm/internal/exporters"
+	"github.com/lincentpega/pcrm/internal/migrate"
+	"github.com/lincentpega/pcrm/internal/services"
+)
+
+func TestListDossiersWithSharedCreationTime(t *testing.T) {
+	ctx := context.Background()
+	db, err := config.NewDatabase(config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "pcrm.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := encryption.NewKeyring(config.EncryptionConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uow := services.NewUnitOfWork(db, time.Second, keys)
+	user, err := services.NewUserService(uow, time.Hour).EnsureUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const people = 250
+	for i := range people {
+		if _, err := db.Exec(`INSERT INTO people (user_id, first_name, created_at) VALUES (?, ?, '2024-01-01 00:00:00')`, user.ID, fmt.Sprintf("Person %d", i)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`INSERT INTO connection_sources (person_id, introducer_person_id) VALUES (last_insert_rowid(), 1)`); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dossiers, all, err := services.NewDossierService(uow).ListDossiers(auth.WithUserID(ctx, user.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dossiers) != people || len(all) != people {
+		t.Fatalf("read %d dossiers of %d people, want %d", len(dossiers), len(all), people)
+	}
+	var buf bytes.Buffer
+	if err := exporters.WriteDossierZip(&buf, dossiers, exporters.NoteNames(all)); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, file := range archive.File {
+		if seen[file.Name] {
+			t.Errorf("zip has %s twice", file.Name)
+		}
+		seen[file.Name] = true
+		note, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(note)
+		note.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), "Introduced by [[Person 0]].") {
+			t.Errorf("%s does not link its introducer:\n%s", file.Name, content)
+		}
+	}
+	if len(seen) != people {
+		t.Errorf("zip has %d notes, want %d", len(seen), people)
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+)
+
+// reencryptBatchSize bounds the records re-encrypted per transaction.
+const reencryptBatchSize = 100
+
+type EncryptionService struct {
+	uow UnitOfWork
+}
+
+func NewEncryptionService(uow UnitOfWork) *EncryptionService {
+	return &EncryptionService{uow: uow}
+}
+
+// RotateKeys re-encrypts the contacts, meeting stories and conversation
+// notes of all users that are not sealed with the active key, or decrypts
+// them when no key is active, and returns how many records changed. Each
+// batch is committed on its own, so an interrupted rotation can be resumed.
+func (s *EncryptionService) RotateKeys(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		changed := 0
+		err := s.uow.Within(ctx, func(repos Repositories) error {
+			for _, reencrypt := range []func(context.Context, int) (int, error){
+				repos.Contacts.Reencrypt,
+				repos.ConnectionSources.Reencrypt,
+				repos.Conversations.Reencrypt,
+			} {
+				n, err := reencrypt(ctx, reencryptBatchSize)
+				if err != nil {
+					return err
+				}
+				changed += n
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		if changed == 0 {
+			return total, nil
+		}
+		total += changed
+	}
+}
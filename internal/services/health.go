@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/lincentpega/pcrm/internal/migrate"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+var errShuttingDown = errors.New("server is shutting down")
+
+// HealthService reports whether the server can take traffic: the database
+// answers within the timeout, its schema is at the version this binary
+// embeds, and shutdown has not begun.
+type HealthService struct {
+	db           *sqlx.DB
+	migrator     *migrate.Migrator
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewHealthService(db *sqlx.DB, migrator *migrate.Migrator, timeout time.Duration) *HealthService {
+	return &HealthService{db: db, migrator: migrator, timeout: timeout}
+}
+
+// StartShutdown makes every following readiness check fail, so load
+// balancers stop routing new requests while in-flight ones finish.
+func (s *HealthService) StartShutdown() {
+	s.shuttingDown.Store(true)
+}
+
+// Readiness runs every check and reports each outcome.
+func (s *HealthService) Readiness(ctx context.Context) []models.HealthCheck {
+	if s.shuttingDown.Load() {
+		return []models.HealthCheck{{Name: "shutdown", Err: errShuttingDown}}
+	}
+	ctx, cancel := s.checkContext(ctx)
+	defer cancel()
+	if err := s.db.PingContext(ctx); err != nil {
+		return []models.HealthCheck{
+			{Name: "database", Err: fmt.Errorf("database is unreachable: %w", err)},
+			{Name: "migrations", Err: errors.New("schema version is unknown while the database is unreachable")},
+		}
+	}
+	return []models.HealthCheck{
+		{Name: "database"},
+		{Name: "migrations", Err: s.checkSchema(ctx)},
+	}
+}
+
+// SchemaVersion returns the schema version recorded in the database.
+func (s *HealthService) SchemaVersion(ctx context.Context) (migrate.State, error) {
+	ctx, cancel := s.checkContext(ctx)
+	defer cancel()
+	return s.migrator.Version(ctx)
+}
+
+func (s *HealthService) checkSchema(ctx context.Context) error {
+	state, err := s.migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if state.Dirty {
+		return fmt.Errorf("schema is dirty at version %d", state.Version)
+	}
+	if latest := s.migrator.Latest(); state.Version != latest {
+		return fmt.Errorf("schema is at version %d, expected %d", state.Version, latest)
+	}
+	return nil
+}
+
+func (s *HealthService) checkContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.timeout)
+}
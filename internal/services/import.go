@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/repository"
+)
+
+// ImportService stores the people and conversations read from import files.
+type ImportService struct {
+	uow UnitOfWork
+}
+
+func NewImportService(uow UnitOfWork) *ImportService {
+	return &ImportService{uow: uow}
+}
+
+// ContactTypeIDs maps the names of the contact types visible to the user to
+// their ids.
+func (s *ImportService) ContactTypeIDs(ctx context.Context) (map[string]int64, error) {
+	contactTypes, err := s.uow.Repositories().Contacts.GetContactTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int64, len(contactTypes))
+	for _, contactType := range contactTypes {
+		ids[contactType.Name] = contactType.ID
+	}
+	return ids, nil
+}
+
+// ConversationTypeIDs maps the names of the conversation types visible to
+// the user to their ids.
+func (s *ImportService) ConversationTypeIDs(ctx context.Context) (map[string]int64, error) {
+	conversationTypes, err := s.uow.Repositories().Conversations.GetConversationTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int64, len(conversationTypes))
+	for _, conversationType := range conversationTypes {
+		ids[conversationType.Name] = conversationType.ID
+	}
+	return ids, nil
+}
+
+// ImportProfiles creates every profile as CreatePersonProfile does, all in
+// one transaction, and reports what it did with each. Nil profiles stand for
+// rows that could not be read and are counted as invalid. A dry run creates
+// the profiles and rolls back, so it fails on the same errors without
+// storing anything.
+func (s *ImportService) ImportProfiles(ctx context.Context, profiles []*models.PersonProfile, dryRun bool) (*models.ProfileImport, error) {
+	return s.importProfiles(ctx, profiles, nil, dryRun)
+}
+
+// identityTypeNames name the contact types that identify a person: an
+// address book entry sharing an email or profile URL with a person already
+// stored is the same person. They are seeded by the migrations.
+var identityTypeNames = []string{emailTypeName, "Social Media", "Website"}
+
+// ImportAddressBook imports the profiles read from an address book as
+// ImportProfiles does, but skips a profile when one of its emails, social
+// media profiles or websites matches one that the user already has or that
+// an earlier profile brought.
+func (s *ImportService) ImportAddressBook(ctx context.Context, profiles []*models.PersonProfile, dryRun bool) (*models.ProfileImport, error) {
+	contactTypes, err := s.ContactTypeIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	identityTypes := make([]int64, len(identityTypeNames))
+	for i, name := range identityTypeNames {
+		id, ok := contactTypes[name]
+		if !ok {
+			return nil, &repository.ValidationError{Message: fmt.Sprintf("contact type %q does not exist", name)}
+		}
+		identityTypes[i] = id
+	}
+	return s.importProfiles(ctx, profiles, identityTypes, dryRun)
+}
+
+// importProfiles creates the profiles, skipping those with a contact of the
+// identityTypes that matches a contact of those types the user already has
+// or that an earlier profile brought.
+func (s *ImportService) importProfiles(ctx context.Context, profiles []*models.PersonProfile, identityTypes []int64, dryRun bool) (*models.ProfileImport, error) {
+	summary := &models.ProfileImport{Profiles: make([]models.ProfileImportResult, len(profiles))}
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		known := make(map[string]bool)
+		for _, contactTypeID := range identityTypes {
+			contacts, err := repos.Contacts.GetByContactTypeID(ctx, contactTypeID)
+			if err != nil {
+				return err
+			}
+			for _, contact := range contacts {
+				known[identityKey(contact.Content)] = true
+			}
+		}
+		for i, profile := range profiles {
+			if profile == nil {
+				summary.Profiles[i].Invalid = true
+				summary.Invalid++
+				continue
+			}
+			var keys []string
+			for _, contact := range profile.Contacts {
+				if slices.Contains(identityTypes, contact.ContactTypeID) {
+					keys = append(keys, identityKey(contact.Content))
+				}
+			}
+			if slices.ContainsFunc(keys, func(key string) bool { return known[key] }) {
+				summary.Profiles[i].Skipped = true
+				summary.Skipped++
+				continue
+			}
+			if err := createPersonProfile(ctx, repos, profile); err != nil {
+				return err
+			}
+			if !dryRun {
+				summary.Profiles[i].PersonID = profile.Person.ID
+			}
+			summary.Imported++
+			for _, key := range keys {
+				known[key] = true
+			}
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// textMessageTypeName names the conversation type chats are logged as. It
+// is seeded by the migrations.
+const textMessageTypeName = "Text Message"
+
+// ImportConversations logs a "Text Message" conversation with an existing
+// person for every day of the chat, all in one transaction, and reports what
+// it did with each day. Days are counted in the location of the SentAt of
+// the messages. A conversation is dated by the first and last message of its
+// day, and was initiated by whoever sent the first one. A day is skipped
+// when the person already has a text message conversation on it, so
+// importing a chat again only adds the days that are new. A dry run logs the
+// conversations and rolls back.
+func (s *ImportService) ImportConversations(ctx context.Context, personID int64, chat *models.Chat, dryRun bool) (*models.ChatImport, error) {
+	conversationTypes, err := s.ConversationTypeIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conversationTypeID, ok := conversationTypes[textMessageTypeName]
+	if !ok {
+		return nil, &repository.ValidationError{Message: fmt.Sprintf("conversation type %q does not exist", textMessageTypeName)}
+	}
+	days := chatDays(chat)
+	summary := &models.ChatImport{Days: make([]models.ChatDayImport, len(days))}
+	err = s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		existing, err := repos.Conversations.GetByPersonID(ctx, personID)
+		if err != nil {
+			return err
+		}
+		for i, day := range days {
+			summary.Days[i].ChatDay = day
+			loc := day.FirstMessageAt.Location()
+			date := day.FirstMessageAt.Format(time.DateOnly)
+			if slices.ContainsFunc(existing, func(stored models.Conversation) bool {
+				return stored.ConversationTypeID == conversationTypeID && stored.CreatedAt.In(loc).Format(time.DateOnly) == date
+			}) {
+				summary.Days[i].Skipped = true
+				summary.Skipped++
+				continue
+			}
+			created, err := createConversation(ctx, repos, personID, &models.Conversation{
+				ConversationTypeID: conversationTypeID,
+				Initiator:          day.Initiator,
+				Notes:              day.Notes,
+			})
+			if err != nil {
+				return err
+			}
+			if err := repos.Conversations.RestoreTimestamps(ctx, created.ID, day.FirstMessageAt.UTC(), day.LastMessageAt.UTC()); err != nil {
+				return err
+			}
+			created.CreatedAt, created.UpdatedAt = day.FirstMessageAt, day.LastMessageAt
+			existing = append(existing, *created)
+			if !dryRun {
+				summary.Days[i].ConversationID = created.ID
+			}
+			summary.Imported++
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// chatDays groups the messages of the chat by the calendar day they were
+// sent on, in the location of their SentAt, oldest day first.
+func chatDays(chat *models.Chat) []models.ChatDay {
+	messages := slices.Clone(chat.Messages)
+	slices.SortStableFunc(messages, func(a, b models.ChatMessage) int {
+		return a.SentAt.Compare(b.SentAt)
+	})
+	var days []models.ChatDay
+	for _, message := range messages {
+		if len(days) == 0 || days[len(days)-1].FirstMessageAt.Format(time.DateOnly) != message.SentAt.Format(time.DateOnly) {
+			initiator := "person"
+			if message.FromOwner {
+				initiator = "owner"
+			}
+			days = append(days, models.ChatDay{FirstMessageAt: message.SentAt, Initiator: initiator})
+		}
+		day := &days[len(days)-1]
+		day.LastMessageAt = message.SentAt
+		if message.FromOwner {
+			day.OwnerMessages++
+		} else {
+			day.PersonMessages++
+		}
+	}
+	for i := range days {
+		days[i].Notes = chatDayNotes(chat, &days[i])
+	}
+	return days
+}
+
+// chatDayNotes describes the day as e.g. "Telegram chat, 12 messages: 5 from
+// me, 7 from Bob".
+func chatDayNotes(chat *models.Chat, day *models.ChatDay) string {
+	total := day.OwnerMessages + day.PersonMessages
+	unit := "messages"
+	if total == 1 {
+		unit = "message"
+	}
+	return fmt.Sprintf("%s chat, %d %s: %d from me, %d from %s", chat.App, total, unit, day.OwnerMessages, day.PersonMessages, chat.PersonName)
+}
+
+// emailNoSubject stands in for the notes of an email without a subject.
+const emailNoSubject = "(no subject)"
+
+// emailTypeName names both the contact type of email addresses and the
+// conversation type emails are logged as. Both are seeded by the migrations.
+const emailTypeName = "Email"
+
+// ImportEmails logs every email as an "Email" conversation with each person
+// who has the address it was sent from or to as an "Email" contact, all in
+// one transaction. The subject becomes the notes and the date the time of the
+// conversation. The person initiated it when the sender is one of the people,
+// and the user otherwise. An email is logged once per person: people who
+// already have a conversation with its Message-ID are reported instead. A dry
+// run logs the emails and rolls back.
+func (s *ImportService) ImportEmails(ctx context.Context, emails []models.Email, dryRun bool) ([]models.EmailImport, error) {
+	contactTypes, err := s.ContactTypeIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	emailTypeID, ok := contactTypes[emailTypeName]
+	if !ok {
+		return nil, &repository.ValidationError{Message: fmt.Sprintf("contact type %q does not exist", emailTypeName)}
+	}
+	conversationTypes, err := s.ConversationTypeIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conversationTypeID, ok := conversationTypes[emailTypeName]
+	if !ok {
+		return nil, &repository.ValidationError{Message: fmt.Sprintf("conversation type %q does not exist", emailTypeName)}
+	}
+	imports := make([]models.EmailImport, len(emails))
+	err = s.uow.Within(ctx, func(repos Repositories) error {
+		contacts, err := repos.Contacts.GetByContactTypeID(ctx, emailTypeID)
+		if err != nil {
+			return err
+		}
+		people := make(map[string][]int64)
+		for _, contact := range contacts {
+			address := strings.ToLower(strings.TrimSpace(contact.Content))
+			if !slices.Contains(people[address], contact.PersonID) {
+				people[address] = append(people[address], contact.PersonID)
+			}
+		}
+		logged := make(map[int64]map[string]bool)
+		for i, email := range emails {
+			initiator := "owner"
+			if len(people[email.From]) > 0 {
+				initiator = "person"
+			}
+			var personIDs []int64
+			for _, address := range append([]string{email.From}, email.Recipients...) {
+				for _, personID := range people[address] {
+					if !slices.Contains(personIDs, personID) {
+						personIDs = append(personIDs, personID)
+					}
+				}
+			}
+			for _, personID := range personIDs {
+				if logged[personID] == nil {
+					if logged[personID], err = loggedMessageIDs(ctx, repos, personID); err != nil {
+						return err
+					}
+				}
+				if logged[personID][email.MessageID] {
+					imports[i].DuplicatePersonIDs = append(imports[i].DuplicatePersonIDs, personID)
+					continue
+				}
+				conversation, err := logEmail(ctx, repos, personID, conversationTypeID, initiator, email)
+				if err != nil {
+					return err
+				}
+				logged[personID][email.MessageID] = true
+				imports[i].Conversations = append(imports[i].Conversations, *conversation)
+			}
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return imports, nil
+}
+
+// loggedMessageIDs returns the Message-IDs of the emails already logged for
+// the person.
+func loggedMessageIDs(ctx context.Context, repos Repositories, personID int64) (map[string]bool, error) {
+	conversations, err := repos.Conversations.GetByPersonID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	messageIDs := make(map[string]bool)
+	for _, conversation := range conversations {
+		if conversation.MessageID != nil {
+			messageIDs[*conversation.MessageID] = true
+		}
+	}
+	return messageIDs, nil
+}
+
+func logEmail(ctx context.Context, repos Repositories, personID, conversationTypeID int64, initiator string, email models.Email) (*models.Conversation, error) {
+	notes := email.Subject
+	if notes == "" {
+		notes = emailNoSubject
+	}
+	conversation, err := createConversation(ctx, repos, personID, &models.Conversation{
+		ConversationTypeID: conversationTypeID,
+		Initiator:          initiator,
+		Notes:              notes,
+		MessageID:          &email.MessageID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := repos.Conversations.RestoreTimestamps(ctx, conversation.ID, email.Date.UTC(), email.Date.UTC()); err != nil {
+		return nil, err
+	}
+	conversation.CreatedAt, conversation.UpdatedAt = email.Date.UTC(), email.Date.UTC()
+	return conversation, nil
+}
+
+// identityKey normalizes an email or profile URL for comparison, ignoring
+// case, the URL scheme, a leading "www." and trailing slashes.
+func identityKey(content string) string {
+	key := strings.ToLower(strings.TrimSpace(content))
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+	key = strings.TrimPrefix(key, "www.")
+	return strings.TrimRight(key, "/")
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/lincentpega/pcrm/internal/config"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+// OIDCLogin is a login in progress. The browser keeps it between the
+// redirect to the provider and the callback, which must present the same
+// state.
+type OIDCLogin struct {
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// OIDCService signs users in with an OpenID Connect provider using the
+// authorization code flow with PKCE. The provider is discovered on first
+// use, so the server starts while it is unreachable.
+type OIDCService struct {
+	cfg      config.OIDCConfig
+	uow      UnitOfWork
+	users    *UserService
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func NewOIDCService(cfg config.OIDCConfig, uow UnitOfWork, users *UserService) *OIDCService {
+	return &OIDCService{cfg: cfg, uow: uow, users: users}
+}
+
+// Begin starts a login and returns the provider URL to send the browser to.
+func (s *OIDCService) Begin(ctx context.Context) (string, OIDCLogin, error) {
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return "", OIDCLogin{}, err
+	}
+	login := OIDCLogin{State: rand.Text(), Nonce: rand.Text(), Verifier: oauth2.GenerateVerifier()}
+	authURL := s.oauth2Config(provider).AuthCodeURL(login.State, oauth2.S256ChallengeOption(login.Verifier), oidc.Nonce(login.Nonce))
+	return authURL, login, nil
+}
+
+// Complete redeems the authorization code of the callback for an ID token,
+// verifies it against the provider's keys and starts a session for the user
+// linked to its subject, returning the session and its cookie secret.
+func (s *OIDCService) Complete(ctx context.Context, login OIDCLogin, state, code string) (*models.Session, string, error) {
+	if login.State == "" || subtle.ConstantTimeCompare([]byte(state), []byte(login.State)) != 1 {
+		return nil, "", &AuthenticationError{Message: "login state does not match, start the login again"}
+	}
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := s.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(login.Verifier))
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			return nil, "", &AuthenticationError{Message: "the provider rejected the authorization code"}
+		}
+		return nil, "", fmt.Errorf("failed to redeem authorization code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, "", &AuthenticationError{Message: "the provider returned no id token"}
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: s.cfg.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, "", &AuthenticationError{Message: "invalid id token: " + err.Error()}
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(login.Nonce)) != 1 {
+		return nil, "", &AuthenticationError{Message: "id token nonce does not match"}
+	}
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, "", &AuthenticationError{Message: "invalid id token claims"}
+	}
+	userID, err := s.userFor(ctx, idToken.Issuer, idToken.Subject, claims)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.users.StartSession(ctx, userID)
+}
+
+// Link lets the named user log in as the given subject of the provider.
+func (s *OIDCService) Link(ctx context.Context, username, subject string) error {
+	if s.cfg.Issuer == "" {
+		return errors.New("auth.oidc.issuer is not configured")
+	}
+	user, err := s.users.GetUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	identity := &models.UserIdentity{UserID: user.ID, Issuer: s.cfg.Issuer, Subject: subject}
+	return s.uow.Repositories().UserIdentities.Create(ctx, identity)
+}
+
+// userFor returns the user linked to the subject, creating one named after
+// the username claim when allowed.
+func (s *OIDCService) userFor(ctx context.Context, issuer, subject string, claims map[string]any) (int64, error) {
+	var userID int64
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		identity, err := repos.UserIdentities.GetBySubject(ctx, issuer, subject)
+		if err != nil {
+			return err
+		}
+		if identity != nil {
+			userID = identity.UserID
+			return nil
+		}
+		if !s.cfg.CreateUsers {
+			return &PermissionError{Message: "no user is linked to this account"}
+		}
+		username, _ := claims[s.cfg.UsernameClaim].(string)
+		if username == "" {
+			return &AuthenticationError{Message: fmt.Sprintf("id token has no %s claim", s.cfg.UsernameClaim)}
+		}
+		user := &models.User{Username: username}
+		if err := repos.Users.Create(ctx, user); err != nil {
+			return err
+		}
+		userID = user.ID
+		return repos.UserIdentities.Create(ctx, &models.UserIdentity{UserID: user.ID, Issuer: issuer, Subject: subject})
+	})
+	return userID, err
+}
+
+func (s *OIDCService) discover(ctx context.Context) (*oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil {
+		provider, err := oidc.NewProvider(ctx, s.cfg.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover oidc provider %s: %w", s.cfg.Issuer, err)
+		}
+		s.provider = provider
+	}
+	return s.provider, nil
+}
+
+func (s *OIDCService) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.cfg.ClientID,
+		ClientSecret: s.cfg.ClientSecret,
+		RedirectURL:  s.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       s.cfg.Scopes,
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+type PersonService struct {
+	uow UnitOfWork
+}
+
+func NewPersonService(uow UnitOfWork) *PersonService {
+	return &PersonService{uow: uow}
+}
+
+// ListPeople returns the requested page of people together with the total
+// number of people, both read from the same snapshot.
+func (s *PersonService) ListPeople(ctx context.Context, page, limit int) ([]models.Person, int, error) {
+	var people []models.Person
+	var totalCount int
+	err := s.uow.WithinSnapshot(ctx, func(repos Repositories) error {
+		var err error
+		if people, err = repos.People.GetPaginated(ctx, page, limit); err != nil {
+			return err
+		}
+		totalCount, err = repos.People.GetTotalCount(ctx)
+		return err
+	})
+	return people, totalCount, err
+}
+
+func (s *PersonService) GetPerson(ctx context.Context, id int64) (*models.Person, error) {
+	return s.uow.Repositories().People.GetByID(ctx, id)
+}
+
+// CreatePersonProfile stores the person and every provided sub-resource in a
+// single transaction, binding them to the new person and resolving contact
+// and conversation types in the profile.
+func (s *PersonService) CreatePersonProfile(ctx context.Context, profile *models.PersonProfile) error {
+	return s.uow.Within(ctx, func(repos Repositories) error {
+		return createPersonProfile(ctx, repos, profile)
+	})
+}
+
+func createPersonProfile(ctx context.Context, repos Repositories, profile *models.PersonProfile) error {
+	if err := repos.People.Create(ctx, &profile.Person); err != nil {
+		return err
+	}
+	personID := profile.Person.ID
+	for i := range profile.Contacts {
+		created, err := createContact(ctx, repos, personID, &profile.Contacts[i])
+		if err != nil {
+			return err
+		}
+		profile.Contacts[i] = *created
+	}
+	if profile.ConnectionSource != nil {
+		profile.ConnectionSource.PersonID = personID
+		if err := repos.ConnectionSources.Create(ctx, profile.ConnectionSource); err != nil {
+			return err
+		}
+	}
+	if profile.BirthDateInfo != nil {
+		profile.BirthDateInfo.PersonID = personID
+		if err := repos.BirthDateInfo.Create(ctx, profile.BirthDateInfo); err != nil {
+			return err
+		}
+	}
+	for i := range profile.Conversations {
+		created, err := createConversation(ctx, repos, personID, &profile.Conversations[i])
+		if err != nil {
+			return err
+		}
+		profile.Conversations[i] = *created
+	}
+	if len(profile.Tags) > 0 {
+		if err := repos.Tags.Replace(ctx, personID, profile.Tags); err != nil {
+			return err
+		}
+	}
+	var err error
+	profile.Tags, err = repos.Tags.GetByPersonID(ctx, personID)
+	return err
+}
+
+func createContact(ctx context.Context, repos Repositories, personID int64, contact *models.Contact) (*models.Contact, error) {
+	contact.PersonID = personID
+	if err := repos.Contacts.Create(ctx, contact); err != nil {
+		return nil, err
+	}
+	return repos.Contacts.GetByID(ctx, contact.ID)
+}
+
+func createConversation(ctx context.Context, repos Repositories, personID int64, conversation *models.Conversation) (*models.Conversation, error) {
+	conversation.PersonID = personID
+	if err := repos.Conversations.Create(ctx, conversation); err != nil {
+		return nil, err
+	}
+	return repos.Conversations.GetByID(ctx, conversation.ID)
+}
+
+func (s *PersonService) UpdatePerson(ctx context.Context, person *models.Person) error {
+	return s.uow.Repositories().People.Update(ctx, person)
+}
+
+// DeletePerson removes the person; contacts, conversations, birth date info
+// and connection source rows are removed by the database cascade.
+func (s *PersonService) DeletePerson(ctx context.Context, id int64) error {
+	return s.uow.Repositories().People.Delete(ctx, id)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+type StatsService struct {
+	uow UnitOfWork
+}
+
+func NewStatsService(uow UnitOfWork) *StatsService {
+	return &StatsService{uow: uow}
+}
+
+// CountPeople counts the people of all users.
+func (s *StatsService) CountPeople(ctx context.Context) (int, error) {
+	return s.uow.Repositories().People.CountAll(ctx)
+}
+
+// CountConversationsThisWeek counts the conversations of all users logged
+// since Monday 00:00 in now's location.
+func (s *StatsService) CountConversationsThisWeek(ctx context.Context, now time.Time) (int, error) {
+	return s.uow.Repositories().Conversations.CountSince(ctx, startOfWeek(now))
+}
+
+func startOfWeek(now time.Time) time.Time {
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	return time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, now.Location())
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+)
+
+type TagService struct {
+	uow UnitOfWork
+}
+
+func NewTagService(uow UnitOfWork) *TagService {
+	return &TagService{uow: uow}
+}
+
+func (s *TagService) GetTags(ctx context.Context, personID int64) ([]string, error) {
+	var tags []string
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if _, err := repos.People.GetByID(ctx, personID); err != nil {
+			return err
+		}
+		var err error
+		tags, err = repos.Tags.GetByPersonID(ctx, personID)
+		return err
+	})
+	return tags, err
+}
+
+// ReplaceTags sets the complete list of the person's tags and returns it in
+// stored order.
+func (s *TagService) ReplaceTags(ctx context.Context, personID int64, tags []string) ([]string, error) {
+	var stored []string
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		if err := repos.Tags.Replace(ctx, personID, tags); err != nil {
+			return err
+		}
+		var err error
+		stored, err = repos.Tags.GetByPersonID(ctx, personID)
+		return err
+	})
+	return stored, err
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/lincentpega/pcrm/internal/encryption"
+	"github.com/lincentpega/pcrm/internal/repository"
+)
+
+// Repositories groups every repository bound to the same database handle, so
+// operations run through it share a single transaction when one is active.
+type Repositories struct {
+	People            repository.PersonRepository
+	Contacts          repository.ContactRepository
+	ConnectionSources repository.ConnectionSourceRepository
+	BirthDateInfo     repository.BirthDateInfoRepository
+	Conversations     repository.ConversationRepository
+	Tags              repository.TagRepository
+	APITokens         repository.APITokenRepository
+	Users             repository.UserRepository
+	Sessions          repository.SessionRepository
+	UserIdentities    repository.UserIdentityRepository
+}
+
+// NewRepositories binds every repository to db, bounding each statement by
+// queryTimeout when it is positive and encrypting sensitive fields with keys.
+func NewRepositories(db sqlx.ExtContext, queryTimeout time.Duration, keys *encryption.Keyring) Repositories {
+	executor := repository.NewExecutor(db, queryTimeout)
+	return Repositories{
+		People:            repository.NewPersonRepository(executor),
+		Contacts:          repository.NewContactRepository(executor, keys),
+		ConnectionSources: repository.NewConnectionSourceRepository(executor, keys),
+		BirthDateInfo:     repository.NewBirthDateInfoRepository(executor),
+		Conversations:     repository.NewConversationRepository(executor, keys),
+		Tags:              repository.NewTagRepository(executor),
+		APITokens:         repository.NewAPITokenRepository(executor),
+		Users:             repository.NewUserRepository(executor),
+		Sessions:          repository.NewSessionRepository(executor),
+		UserIdentities:    repository.NewUserIdentityRepository(executor),
+	}
+}
+
+// UnitOfWork hands out repositories and runs groups of repository calls
+// atomically.
+type UnitOfWork interface {
+	// Repositories returns repositories that apply each call on its own.
+	Repositories() Repositories
+	// Within runs fn atomically, committing when fn succeeds and discarding
+	// its changes when it returns an error or panics.
+	Within(ctx context.Context, fn func(repos Repositories) error) error
+	// WithinSnapshot runs fn in a read-only transaction whose reads all see
+	// the same committed state.
+	WithinSnapshot(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type sqlUnitOfWork struct {
+	db           *sqlx.DB
+	queryTimeout time.Duration
+	keys         *encryption.Keyring
+}
+
+// NewUnitOfWork returns a unit of work backed by database transactions. A
+// nil keys stores sensitive fields in plaintext.
+func NewUnitOfWork(db *sqlx.DB, queryTimeout time.Duration, keys *encryption.Keyring) UnitOfWork {
+	return &sqlUnitOfWork{db: db, queryTimeout: queryTimeout, keys: keys}
+}
+
+func (u *sqlUnitOfWork) Repositories() Repositories {
+	return NewRepositories(u.db, u.queryTimeout, u.keys)
+}
+
+func (u *sqlUnitOfWork) Within(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.within(ctx, nil, fn)
+}
+
+// WithinSnapshot runs fn at REPEATABLE READ on PostgreSQL. SQLite
+// transactions always read a single snapshot.
+func (u *sqlUnitOfWork) WithinSnapshot(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.within(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+func (u *sqlUnitOfWork) within(ctx context.Context, opts *sql.TxOptions, fn func(repos Repositories) error) error {
+	tx, err := u.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err := fn(NewRepositories(tx, u.queryTimeout, u.keys)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
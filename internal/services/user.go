@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/auth"
+	"github.com/lincentpega/pcrm/internal/models"
+	"github.com/lincentpega/pcrm/internal/repository"
+)
+
+// sessionPrefix marks pcrm session cookie values.
+const sessionPrefix = "pcrm_s_"
+
+var errInvalidCredentials = &AuthenticationError{Message: "invalid username or password"}
+
+// dummyPasswordHash is checked against when a login names an unknown or
+// passwordless user, so the response time does not reveal which it was.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	return auth.HashPassword(rand.Text())
+})
+
+type UserService struct {
+	uow        UnitOfWork
+	sessionTTL time.Duration
+}
+
+func NewUserService(uow UnitOfWork, sessionTTL time.Duration) *UserService {
+	return &UserService{uow: uow, sessionTTL: sessionTTL}
+}
+
+// Register creates an account with the given password.
+func (s *UserService) Register(ctx context.Context, username, password string) (*models.User, error) {
+	user := &models.User{Username: username, PasswordHash: auth.HashPassword(password)}
+	if err := s.uow.Repositories().Users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// EnsureUser returns the named user, creating it without a password when it
+// does not exist yet.
+func (s *UserService) EnsureUser(ctx context.Context, username string) (*models.User, error) {
+	var user *models.User
+	err := s.uow.Within(ctx, func(repos Repositories) error {
+		existing, err := repos.Users.GetByUsername(ctx, username)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			user = existing
+			return nil
+		}
+		user = &models.User{Username: username}
+		return repos.Users.Create(ctx, user)
+	})
+	return user, err
+}
+
+func (s *UserService) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	user, err := s.uow.Repositories().Users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &repository.NotFoundError{Resource: "user", Key: "id", Value: id}
+	}
+	return user, nil
+}
+
+// GetUserByUsername returns the named user or a not found error.
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, err := s.uow.Repositories().Users.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %q does not exist", username)
+	}
+	return user, nil
+}
+
+func (s *UserService) SetPassword(ctx context.Context, id int64, password string) error {
+	return s.uow.Repositories().Users.UpdatePassword(ctx, id, auth.HashPassword(password))
+}
+
+// Login checks the credentials and starts a session, returning it together
+// with the secret for the session cookie, which cannot be recovered later.
+func (s *UserService) Login(ctx context.Context, username, password string) (*models.Session, string, error) {
+	repos := s.uow.Repositories()
+	user, err := repos.Users.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, "", err
+	}
+	if user == nil || user.PasswordHash == "" {
+		auth.CheckPassword(dummyPasswordHash(), password)
+		return nil, "", errInvalidCredentials
+	}
+	ok, err := auth.CheckPassword(user.PasswordHash, password)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", errInvalidCredentials
+	}
+	return s.StartSession(ctx, user.ID)
+}
+
+// StartSession starts a session for an authenticated user, returning it
+// together with the secret for the session cookie.
+func (s *UserService) StartSession(ctx context.Context, userID int64) (*models.Session, string, error) {
+	repos := s.uow.Repositories()
+	now := time.Now()
+	if err := repos.Sessions.DeleteExpired(ctx, now); err != nil {
+		return nil, "", err
+	}
+	secret := sessionPrefix + rand.Text()
+	session := &models.Session{UserID: userID, TokenHash: hashToken(secret), ExpiresAt: now.Add(s.sessionTTL)}
+	if err := repos.Sessions.Create(ctx, session); err != nil {
+		return nil, "", err
+	}
+	return session, secret, nil
+}
+
+// Logout ends the session with the given secret, if there is one.
+func (s *UserService) Logout(ctx context.Context, secret string) error {
+	return s.uow.Repositories().Sessions.Delete(ctx, hashToken(secret))
+}
+
+// AuthenticateSession returns the unexpired session with the given secret.
+func (s *UserService) AuthenticateSession(ctx context.Context, secret string) (*models.Session, error) {
+	session, err := s.uow.Repositories().Sessions.GetByHash(ctx, hashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, &AuthenticationError{Message: "invalid session"}
+	}
+	if session.Expired(time.Now()) {
+		return nil, &AuthenticationError{Message: "session has expired"}
+	}
+	return session, nil
+}
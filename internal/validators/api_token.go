@@ -0,0 +1,27 @@
+package validators
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+	"github.com/lincentpega/pcrm/internal/models"
+)
+
+const MaxTokenNameLength = 100
+
+func ValidateAPITokenRequest(req *dto.APITokenRequest, now time.Time) error {
+	var result Result
+	if strings.TrimSpace(req.Name) == "" {
+		result.Add("name", CodeRequired, "name is required")
+	}
+	result.requireMaxLength("name", &req.Name, MaxTokenNameLength)
+	if !slices.Contains([]string{models.TokenScopeRead, models.TokenScopeWrite}, req.Scope) {
+		result.Add("scope", CodeInvalid, "must be read or write")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		result.Add("expiresAt", CodeOutOfRange, "must be in the future")
+	}
+	return result.Err()
+}
@@ -0,0 +1,128 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+// maxTypeNameLength is the length of the name column of contact and
+// conversation types.
+const maxTypeNameLength = 100
+
+// ValidateArchive checks every record with the validator of the matching
+// request and checks that ids are unique and that every reference points to
+// a record in the archive.
+func ValidateArchive(archive *dto.Archive) error {
+	var result Result
+	if archive.Version != dto.ArchiveVersion {
+		result.Add("version", CodeInvalid, fmt.Sprintf("must be %d", dto.ArchiveVersion))
+		return result.Err()
+	}
+	contactTypes := validateArchiveTypes(&result, "contactTypes", archive.ContactTypes)
+	conversationTypes := validateArchiveTypes(&result, "conversationTypes", archive.ConversationTypes)
+	people := make(map[int64]bool)
+	for i := range archive.People {
+		field := fmt.Sprintf("people[%d]", i)
+		person := &archive.People[i]
+		requireUniqueID(&result, field, person.ID, people)
+		result.Merge(field, ValidatePersonUpsertRequest(&person.PersonUpsertRequest))
+		result.Merge(field, ValidateTags(&person.Tags))
+	}
+	contacts := make(map[int64]bool)
+	for i := range archive.Contacts {
+		field := fmt.Sprintf("contacts[%d]", i)
+		contact := &archive.Contacts[i]
+		requireUniqueID(&result, field, contact.ID, contacts)
+		requireReference(&result, field+".personId", contact.PersonID, people)
+		result.Merge(field, ValidateContactRequest(&contact.ContactRequest))
+		if contact.ContactTypeID > 0 {
+			requireReference(&result, field+".contactTypeId", contact.ContactTypeID, contactTypes)
+		}
+	}
+	connectionSources := make(map[int64]bool)
+	for i := range archive.ConnectionSources {
+		field := fmt.Sprintf("connectionSources[%d]", i)
+		connectionSource := &archive.ConnectionSources[i]
+		requireUniquePerson(&result, field, connectionSource.PersonID, connectionSources)
+		requireReference(&result, field+".personId", connectionSource.PersonID, people)
+		result.Merge(field, ValidateConnectionSourceRequest(&connectionSource.ConnectionSourceRequest))
+		if id := connectionSource.IntroducerPersonID; id != nil && *id > 0 {
+			requireReference(&result, field+".introducerPersonId", *id, people)
+		}
+	}
+	birthDateInfo := make(map[int64]bool)
+	for i := range archive.BirthDateInfo {
+		field := fmt.Sprintf("birthDateInfo[%d]", i)
+		info := &archive.BirthDateInfo[i]
+		requireUniquePerson(&result, field, info.PersonID, birthDateInfo)
+		requireReference(&result, field+".personId", info.PersonID, people)
+		result.Merge(field, ValidateBirthDateInfoRequest(&info.BirthDateInfoRequest))
+	}
+	conversations := make(map[int64]bool)
+	messageIDs := make(map[int64]map[string]bool)
+	for i := range archive.Conversations {
+		field := fmt.Sprintf("conversations[%d]", i)
+		conversation := &archive.Conversations[i]
+		requireUniqueID(&result, field, conversation.ID, conversations)
+		requireReference(&result, field+".personId", conversation.PersonID, people)
+		result.Merge(field, ValidateConversationRequest(&conversation.ConversationRequest))
+		if conversation.ConversationTypeID > 0 {
+			requireReference(&result, field+".conversationTypeId", conversation.ConversationTypeID, conversationTypes)
+		}
+		if conversation.MessageID != nil {
+			result.requireMaxLength(field+".messageId", conversation.MessageID, MaxMessageIDLength)
+			if messageIDs[conversation.PersonID] == nil {
+				messageIDs[conversation.PersonID] = make(map[string]bool)
+			}
+			if messageIDs[conversation.PersonID][*conversation.MessageID] {
+				result.Add(field+".messageId", CodeConflict, "duplicate message id for the person")
+			}
+			messageIDs[conversation.PersonID][*conversation.MessageID] = true
+		}
+	}
+	return result.Err()
+}
+
+func validateArchiveTypes(result *Result, field string, types []dto.ArchiveType) map[int64]bool {
+	ids := make(map[int64]bool)
+	names := make(map[string]bool)
+	for i, archiveType := range types {
+		typeField := fmt.Sprintf("%s[%d]", field, i)
+		requireUniqueID(result, typeField, archiveType.ID, ids)
+		name := strings.TrimSpace(archiveType.Name)
+		switch {
+		case name == "":
+			result.Add(typeField+".name", CodeRequired, "name is required")
+		case names[name]:
+			result.Add(typeField+".name", CodeConflict, "is used by another type")
+		}
+		names[name] = true
+		result.requireMaxLength(typeField+".name", &name, maxTypeNameLength)
+	}
+	return ids
+}
+
+func requireUniqueID(result *Result, field string, id int64, seen map[int64]bool) {
+	switch {
+	case id <= 0:
+		result.Add(field+".id", CodeRequired, "must be a positive integer")
+	case seen[id]:
+		result.Add(field+".id", CodeConflict, "is used by another record")
+	}
+	seen[id] = true
+}
+
+func requireUniquePerson(result *Result, field string, personID int64, seen map[int64]bool) {
+	if seen[personID] {
+		result.Add(field+".personId", CodeConflict, "person already has a record of this kind")
+	}
+	seen[personID] = true
+}
+
+func requireReference(result *Result, field string, id int64, ids map[int64]bool) {
+	if !ids[id] {
+		result.Add(field, CodeInvalid, "does not refer to a record in the archive")
+	}
+}
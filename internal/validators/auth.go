@@ -0,0 +1,50 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+const (
+	MaxUsernameLength = 100
+	MinPasswordLength = 8
+	MaxPasswordLength = 1024
+)
+
+var validUsername = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ValidateCredentialsRequest checks the username and password of a new
+// account or a password change.
+func ValidateCredentialsRequest(req *dto.CredentialsRequest) error {
+	var result Result
+	switch {
+	case req.Username == "":
+		result.Add("username", CodeRequired, "username is required")
+	case !validUsername.MatchString(req.Username):
+		result.Add("username", CodeInvalid, "may only contain letters, digits, '.', '_' and '-'")
+	}
+	result.requireMaxLength("username", &req.Username, MaxUsernameLength)
+	switch length := utf8.RuneCountInString(req.Password); {
+	case length == 0:
+		result.Add("password", CodeRequired, "password is required")
+	case length < MinPasswordLength:
+		result.Add("password", CodeInvalid, fmt.Sprintf("must be at least %d characters", MinPasswordLength))
+	}
+	result.requireMaxLength("password", &req.Password, MaxPasswordLength)
+	return result.Err()
+}
+
+func ValidateLoginRequest(req *dto.CredentialsRequest) error {
+	var result Result
+	if req.Username == "" {
+		result.Add("username", CodeRequired, "username is required")
+	}
+	if req.Password == "" {
+		result.Add("password", CodeRequired, "password is required")
+	}
+	result.requireMaxLength("password", &req.Password, MaxPasswordLength)
+	return result.Err()
+}
@@ -1,25 +1,34 @@
 package validators
 
 import (
-	"errors"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lincentpega/pcrm/internal/dto"
 )
 
-func ValidatePersonID(idStr string) (int64, error) {
+func ValidateID(field, idStr string) (int64, error) {
 	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		return 0, errors.New("invalid person ID")
+	if err != nil || id <= 0 {
+		var result Result
+		result.Add(field, CodeInvalid, "must be a positive integer")
+		return 0, result.Err()
 	}
 	return id, nil
 }
 
 func ValidatePersonUpsertRequest(req *dto.PersonUpsertRequest) error {
-	if req.FirstName == "" {
-		return errors.New("first name is required")
-	}
-	return nil
+	var result Result
+	if strings.TrimSpace(req.FirstName) == "" {
+		result.Add("firstName", CodeRequired, "first name is required")
+	}
+	result.requireMaxLength("firstName", &req.FirstName, MaxNameLength)
+	result.requireMaxLength("secondName", req.SecondName, MaxNameLength)
+	result.requireMaxLength("middleName", req.MiddleName, MaxNameLength)
+	result.requireMaxLength("company", req.Company, MaxNameLength)
+	result.requireMaxLength("jobTitle", req.JobTitle, MaxNameLength)
+	return result.Err()
 }
 
 func ParsePaginationParams(pageStr, limitStr string) (int, int) {
@@ -41,119 +50,56 @@ func ParsePaginationParams(pageStr, limitStr string) (int, int) {
 	return page, limit
 }
 
+// ValidateBirthDateInfoRequest accepts an exact date (year+month+day), a
+// partial date (month+day), an approximate age alone, or no fields at all to
+// clear the record.
 func ValidateBirthDateInfoRequest(req *dto.BirthDateInfoRequest) error {
+	var result Result
 	hasYear := req.BirthYear != nil
 	hasMonth := req.BirthMonth != nil
 	hasDay := req.BirthDay != nil
-	hasApproximateAge := req.ApproximateAge != nil
-
-	// Scenario 1: Exact date (year + month + day, no approximate age)
-	if hasYear && hasMonth && hasDay && !hasApproximateAge {
-		return validateExactDate(*req.BirthYear, *req.BirthMonth, *req.BirthDay)
+	if req.ApproximateAge != nil && (hasYear || hasMonth || hasDay) {
+		result.Add("approximateAge", CodeConflict, "approximate age cannot be combined with date fields")
 	}
-
-	// Scenario 2: Partial date (month + day only, no year or approximate age)
-	if !hasYear && hasMonth && hasDay && !hasApproximateAge {
-		return validatePartialDate(*req.BirthMonth, *req.BirthDay)
+	if (hasYear || hasDay) && !hasMonth {
+		result.Add("birthMonth", CodeRequired, "birth month is required when year or day is given")
 	}
-
-	// Scenario 3: Approximate age only (no date fields)
-	if !hasYear && !hasMonth && !hasDay && hasApproximateAge {
-		return validateApproximateAge(*req.ApproximateAge)
+	if (hasYear || hasMonth) && !hasDay {
+		result.Add("birthDay", CodeRequired, "birth day is required when year or month is given")
 	}
-
-	// Scenario 4: Clear all (all fields nil)
-	if !hasYear && !hasMonth && !hasDay && !hasApproximateAge {
-		return nil // Valid - clearing birth date info
+	if hasYear {
+		result.requireRange("birthYear", *req.BirthYear, 1900, 2100)
 	}
-
-	// Invalid combinations - provide specific error messages
-	if hasApproximateAge && (hasYear || hasMonth || hasDay) {
-		return errors.New("approximate age cannot be combined with date fields - use either approximate age alone or date fields without approximate age")
+	if hasMonth {
+		result.requireRange("birthMonth", *req.BirthMonth, 1, 12)
 	}
-
-	if hasYear && !hasMonth {
-		return errors.New("birth year requires both month and day - provide complete date (year+month+day) or partial date (month+day only)")
+	if hasDay {
+		result.requireRange("birthDay", *req.BirthDay, 1, 31)
 	}
-
-	if hasYear && hasMonth && !hasDay {
-		return errors.New("birth year and month require day - provide complete date (year+month+day)")
+	if req.ApproximateAge != nil {
+		result.requireRange("approximateAge", *req.ApproximateAge, 0, 150)
 	}
-
-	if hasDay && !hasMonth {
-		return errors.New("birth day requires month - provide either partial date (month+day) or complete date (year+month+day)")
+	if result.Valid() && hasMonth && hasDay && !isExistingDay(birthYearOrLeapYear(req.BirthYear), *req.BirthMonth, *req.BirthDay) {
+		result.Add("birthDay", CodeInvalid, "day does not exist in the given month")
 	}
-
-	// Fallback for any other invalid combinations
-	return errors.New("invalid birth date combination - use exact date (year+month+day), partial date (month+day), approximate age only, or clear all fields")
+	return result.Err()
 }
 
-func validateExactDate(year, month, day int) error {
-	if year < 1900 || year > 2100 {
-		return errors.New("birth year must be between 1900 and 2100")
-	}
-	if month < 1 || month > 12 {
-		return errors.New("birth month must be between 1 and 12")
-	}
-	if day < 1 || day > 31 {
-		return errors.New("birth day must be between 1 and 31")
-	}
-
-	// Check if the date is valid (handles leap years, month lengths)
-	_, err := parseDate(year, month, day)
-	if err != nil {
-		return errors.New("invalid date")
+// birthYearOrLeapYear falls back to a leap year so that February 29 is
+// accepted for partial dates.
+func birthYearOrLeapYear(year *int) int {
+	if year == nil {
+		return 2024
 	}
-
-	return nil
+	return *year
 }
 
-func validatePartialDate(month, day int) error {
-	if month < 1 || month > 12 {
-		return errors.New("birth month must be between 1 and 12")
-	}
-	if day < 1 || day > 31 {
-		return errors.New("birth day must be between 1 and 31")
-	}
-
-	// Check if month/day combination is valid (use leap year for February validation)
-	_, err := parseDate(2024, month, day) // Use leap year to be permissive
-	if err != nil {
-		return errors.New("invalid month/day combination")
-	}
-
-	return nil
-}
-
-func validateApproximateAge(age int) error {
-	if age < 0 || age > 150 {
-		return errors.New("approximate age must be between 0 and 150")
-	}
-	return nil
-}
-
-func parseDate(year, month, day int) (bool, error) {
-	// Check basic ranges
-	if month < 1 || month > 12 {
-		return false, errors.New("invalid month")
-	}
-	if day < 1 {
-		return false, errors.New("invalid day")
-	}
-
-	// Days in each month
+func isExistingDay(year, month, day int) bool {
 	daysInMonth := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
-	
-	// Check for leap year
 	if isLeapYear(year) {
 		daysInMonth[1] = 29
 	}
-
-	if day > daysInMonth[month-1] {
-		return false, errors.New("day exceeds month limit")
-	}
-
-	return true, nil
+	return day <= daysInMonth[month-1]
 }
 
 func isLeapYear(year int) bool {
@@ -161,23 +107,50 @@ func isLeapYear(year int) bool {
 }
 
 func ValidateConnectionSourceRequest(req *dto.ConnectionSourceRequest) error {
+	var result Result
 	if req.IntroducerPersonID != nil && req.IntroducerName != nil {
-		return errors.New("cannot specify both introducer_person_id and introducer_name")
+		result.Add("introducerName", CodeConflict, "cannot be combined with introducerPersonId")
 	}
-	
-	if req.WasIntroduced != nil && *req.WasIntroduced {
-		if req.IntroducerPersonID == nil && req.IntroducerName == nil {
-			return errors.New("when was_introduced is true, either introducer_person_id or introducer_name must be provided")
-		}
+	if req.WasIntroduced != nil && *req.WasIntroduced && req.IntroducerPersonID == nil && req.IntroducerName == nil {
+		result.Add("wasIntroduced", CodeRequired, "requires introducerPersonId or introducerName")
 	}
-	
 	if req.IntroducerPersonID != nil && *req.IntroducerPersonID <= 0 {
-		return errors.New("introducer_person_id must be positive")
+		result.Add("introducerPersonId", CodeOutOfRange, "must be positive")
+	}
+	if req.IntroducerName != nil && strings.TrimSpace(*req.IntroducerName) == "" {
+		result.Add("introducerName", CodeRequired, "cannot be empty")
 	}
+	result.requireMaxLength("introducerName", req.IntroducerName, MaxNameLength)
+	return result.Err()
+}
 	
-	if req.IntroducerName != nil && *req.IntroducerName == "" {
-		return errors.New("introducer_name cannot be empty")
+// ParseBoolParam reads an optional boolean query parameter, which defaults to
+// false.
+func ParseBoolParam(field, value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		var result Result
+		result.Add(field, CodeInvalid, "must be true or false")
+		return false, result.Err()
 	}
+	return parsed, nil
+}
 	
-	return nil
-}
\ No newline at end of file
+// ParseTimezoneParam reads an optional IANA timezone query parameter, such
+// as Europe/Berlin, which defaults to UTC.
+func ParseTimezoneParam(field, value string) (*time.Location, error) {
+	if value == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		var result Result
+		result.Add(field, CodeInvalid, "must be an IANA timezone such as Europe/Berlin")
+		return nil, result.Err()
+	}
+	return loc, nil
+}
+	
\ No newline at end of file
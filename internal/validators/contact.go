@@ -0,0 +1,18 @@
+package validators
+
+import (
+	"strings"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func ValidateContactRequest(req *dto.ContactRequest) error {
+	var result Result
+	if req.ContactTypeID <= 0 {
+		result.Add("contactTypeId", CodeRequired, "contact type id is required")
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		result.Add("content", CodeRequired, "content is required")
+	}
+	return result.Err()
+}
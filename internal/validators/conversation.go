@@ -1,34 +1,32 @@
 package validators
 
 import (
-    "errors"
-    "strconv"
     "strings"
 
     "github.com/lincentpega/pcrm/internal/dto"
 )
 
-func ValidateConversationID(idStr string) (int64, error) {
-    id, err := strconv.ParseInt(idStr, 10, 64)
-    if err != nil {
-        return 0, errors.New("invalid conversation ID")
-    }
-    return id, nil
-}
+// MaxMessageIDLength is the length of the message_id column of
+// conversations, the longest line an email header may have.
+const MaxMessageIDLength = 998
 
 func ValidateConversationRequest(req *dto.ConversationRequest) error {
+    var result Result
     if req.ConversationTypeID <= 0 {
-        return errors.New("conversation type id is required")
-    }
-    if req.Initiator == "" {
-        return errors.New("initiator is required")
+        result.Add("conversationTypeId", CodeRequired, "conversation type id is required")
     }
     v := strings.ToLower(req.Initiator)
-    if v != "owner" && v != "person" {
-        return errors.New("initiator must be 'owner' or 'person'")
+    switch {
+    case req.Initiator == "":
+        result.Add("initiator", CodeRequired, "initiator is required")
+    case v != "owner" && v != "person":
+        result.Add("initiator", CodeInvalid, "initiator must be 'owner' or 'person'")
     }
     if strings.TrimSpace(req.Notes) == "" {
-        return errors.New("notes is required")
+        result.Add("notes", CodeRequired, "notes is required")
+    }
+    if !result.Valid() {
+        return result.Err()
     }
     req.Initiator = v
     return nil
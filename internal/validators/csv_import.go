@@ -0,0 +1,58 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+var csvFields = []string{
+	dto.CSVFieldFirstName, dto.CSVFieldSecondName, dto.CSVFieldMiddleName, dto.CSVFieldCompany, dto.CSVFieldJobTitle, dto.CSVFieldContact,
+	dto.CSVFieldBirthDate, dto.CSVFieldTag, dto.CSVFieldMeetingStory,
+}
+
+// repeatableCSVFields may be mapped from several columns; every other field
+// from at most one.
+var repeatableCSVFields = []string{dto.CSVFieldContact, dto.CSVFieldTag}
+
+// ValidateCSVMapping checks that a mapping names known fields, maps the first
+// name and gives every contact column a contact type.
+func ValidateCSVMapping(mapping *dto.CSVMapping) error {
+	var result Result
+	if mapping.Delimiter != "" {
+		delimiter, size := utf8.DecodeRuneInString(mapping.Delimiter)
+		if size != len(mapping.Delimiter) || delimiter == utf8.RuneError || strings.ContainsRune("\"\r\n", delimiter) {
+			result.Add("delimiter", CodeInvalid, "must be a single character other than a quote or line break")
+		}
+	}
+	if len(mapping.Columns) == 0 {
+		result.Add("columns", CodeRequired, "at least one column must be mapped")
+	}
+	mapped := make(map[string]bool)
+	for i, column := range mapping.Columns {
+		field := fmt.Sprintf("columns[%d]", i)
+		if strings.TrimSpace(column.Column) == "" {
+			result.Add(field+".column", CodeRequired, "column is required")
+		}
+		switch {
+		case !slices.Contains(csvFields, column.Field):
+			result.Add(field+".field", CodeInvalid, "must be one of "+strings.Join(csvFields, ", "))
+		case mapped[column.Field] && !slices.Contains(repeatableCSVFields, column.Field):
+			result.Add(field+".field", CodeConflict, "is already mapped from another column")
+		}
+		mapped[column.Field] = true
+		switch {
+		case column.Field == dto.CSVFieldContact && strings.TrimSpace(column.ContactType) == "":
+			result.Add(field+".contactType", CodeRequired, "contact type is required for contact columns")
+		case column.Field != dto.CSVFieldContact && column.ContactType != "":
+			result.Add(field+".contactType", CodeConflict, "only applies to contact columns")
+		}
+	}
+	if len(mapping.Columns) > 0 && !mapped[dto.CSVFieldFirstName] {
+		result.Add("columns", CodeRequired, "a column must be mapped to firstName")
+	}
+	return result.Err()
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"fmt"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+func ValidatePersonCreateRequest(req *dto.PersonCreateRequest) error {
+	var result Result
+	result.Merge("", ValidatePersonUpsertRequest(&req.PersonUpsertRequest))
+	for i := range req.Contacts {
+		result.Merge(fmt.Sprintf("contacts[%d]", i), ValidateContactRequest(&req.Contacts[i]))
+	}
+	if req.ConnectionSource != nil {
+		result.Merge("connectionSource", ValidateConnectionSourceRequest(req.ConnectionSource))
+	}
+	if req.BirthDateInfo != nil {
+		result.Merge("birthDateInfo", ValidateBirthDateInfoRequest(req.BirthDateInfo))
+	}
+	if req.Conversation != nil {
+		result.Merge("conversation", ValidateConversationRequest(req.Conversation))
+	}
+	result.Merge("", ValidateTags(&req.Tags))
+	return result.Err()
+}
@@ -0,0 +1,92 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	CodeRequired     = "required"
+	CodeOutOfRange   = "out_of_range"
+	CodeTooLong      = "too_long"
+	CodeInvalid      = "invalid"
+	CodeConflict     = "conflict"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+	CodeMalformed    = "malformed"
+)
+
+const MaxNameLength = 255
+
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// ValidationErrors is the error returned by validators when at least one
+// field is invalid; it lists every violation found, not only the first one.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Result accumulates field violations while a request is being validated.
+type Result struct {
+	errors ValidationErrors
+}
+
+func (r *Result) Add(field, code, message string) {
+	r.errors = append(r.errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Merge adds the violations of a nested validator under the given field
+// prefix, e.g. "contacts[0]"; errors that are not ValidationErrors are ignored.
+func (r *Result) Merge(prefix string, err error) {
+	fieldErrs, ok := err.(ValidationErrors)
+	if !ok {
+		return
+	}
+	for _, fieldErr := range fieldErrs {
+		r.Add(nestedField(prefix, fieldErr.Field), fieldErr.Code, fieldErr.Message)
+	}
+}
+
+func (r *Result) Valid() bool {
+	return len(r.errors) == 0
+}
+
+func (r *Result) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return r.errors
+}
+
+func (r *Result) requireMaxLength(field string, value *string, max int) {
+	if value != nil && utf8.RuneCountInString(*value) > max {
+		r.Add(field, CodeTooLong, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+func (r *Result) requireRange(field string, value, min, max int) {
+	if value < min || value > max {
+		r.Add(field, CodeOutOfRange, fmt.Sprintf("must be between %d and %d", min, max))
+	}
+}
+
+func nestedField(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	if field == "" {
+		return prefix
+	}
+	return prefix + "." + field
+}
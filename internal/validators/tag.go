@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lincentpega/pcrm/internal/dto"
+)
+
+// MaxTagLength is the length of the name column of person tags.
+const MaxTagLength = 100
+
+// ValidateTags checks the tags of a person. It trims them and drops
+// duplicates in place, so the list can be stored as is.
+func ValidateTags(tags *[]string) error {
+	var result Result
+	normalized := make([]string, 0, len(*tags))
+	for i, tag := range *tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			result.Add(field, CodeRequired, "tag cannot be empty")
+			continue
+		}
+		result.requireMaxLength(field, &tag, MaxTagLength)
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	if !result.Valid() {
+		return result.Err()
+	}
+	*tags = normalized
+	return nil
+}
+
+func ValidateTagsRequest(req *dto.TagsRequest) error {
+	if req.Tags == nil {
+		req.Tags = []string{}
+	}
+	return ValidateTags(&req.Tags)
+}
@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL schema migrations, one directory per
+// database driver.
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS
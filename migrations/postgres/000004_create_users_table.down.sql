@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS uk_conversation_types_shared_name;
+ALTER TABLE conversation_types DROP CONSTRAINT IF EXISTS uk_conversation_types_name;
+ALTER TABLE conversation_types DROP COLUMN IF EXISTS user_id;
+ALTER TABLE conversation_types ADD CONSTRAINT conversation_types_name_key UNIQUE (name);
+DROP INDEX IF EXISTS uk_contact_types_shared_name;
+ALTER TABLE contact_types DROP CONSTRAINT IF EXISTS uk_contact_types_name;
+ALTER TABLE contact_types DROP COLUMN IF EXISTS user_id;
+ALTER TABLE contact_types ADD CONSTRAINT contact_types_name_key UNIQUE (name);
+DROP INDEX IF EXISTS idx_api_tokens_user_id;
+ALTER TABLE api_tokens DROP COLUMN IF EXISTS user_id;
+DROP INDEX IF EXISTS idx_people_user_id;
+ALTER TABLE people DROP COLUMN IF EXISTS user_id;
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
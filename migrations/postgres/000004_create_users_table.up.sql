@@ -0,0 +1,43 @@
+CREATE TABLE users (
+    id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+-- Existing data is adopted by an "owner" account without a password.
+INSERT INTO users (username, password_hash)
+SELECT 'owner', '' WHERE EXISTS (SELECT 1 FROM people) OR EXISTS (SELECT 1 FROM api_tokens);
+
+ALTER TABLE people ADD COLUMN user_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
+UPDATE people SET user_id = (SELECT id FROM users WHERE username = 'owner');
+ALTER TABLE people ALTER COLUMN user_id SET NOT NULL;
+CREATE INDEX idx_people_user_id ON people(user_id);
+
+ALTER TABLE api_tokens ADD COLUMN user_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
+UPDATE api_tokens SET user_id = (SELECT id FROM users WHERE username = 'owner');
+ALTER TABLE api_tokens ALTER COLUMN user_id SET NOT NULL;
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+-- Types without a user are shared by everyone. Type names are unique per
+-- user and among the shared types.
+ALTER TABLE contact_types ADD COLUMN user_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE contact_types DROP CONSTRAINT contact_types_name_key;
+ALTER TABLE contact_types ADD CONSTRAINT uk_contact_types_name UNIQUE (user_id, name);
+CREATE UNIQUE INDEX uk_contact_types_shared_name ON contact_types(name) WHERE user_id IS NULL;
+
+ALTER TABLE conversation_types ADD COLUMN user_id BIGINT REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE conversation_types DROP CONSTRAINT conversation_types_name_key;
+ALTER TABLE conversation_types ADD CONSTRAINT uk_conversation_types_name UNIQUE (user_id, name);
+CREATE UNIQUE INDEX uk_conversation_types_shared_name ON conversation_types(name) WHERE user_id IS NULL;
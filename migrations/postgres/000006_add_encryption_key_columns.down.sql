@@ -0,0 +1,6 @@
+ALTER TABLE conversations DROP COLUMN IF EXISTS wrapped_key;
+ALTER TABLE conversations DROP COLUMN IF EXISTS key_id;
+ALTER TABLE connection_sources DROP COLUMN IF EXISTS wrapped_key;
+ALTER TABLE connection_sources DROP COLUMN IF EXISTS key_id;
+ALTER TABLE contacts DROP COLUMN IF EXISTS wrapped_key;
+ALTER TABLE contacts DROP COLUMN IF EXISTS key_id;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_person_tags_name;
+DROP TABLE IF EXISTS person_tags;
@@ -0,0 +1,8 @@
+CREATE TABLE person_tags (
+    person_id BIGINT NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (person_id, name)
+);
+
+CREATE INDEX idx_person_tags_name ON person_tags(name);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS birth_date_info;
+DROP INDEX IF EXISTS idx_connection_sources_introducer_person_id;
+DROP TABLE IF EXISTS connection_sources;
+DROP INDEX IF EXISTS idx_contacts_contact_type_id;
+DROP INDEX IF EXISTS idx_contacts_person_id;
+DROP TABLE IF EXISTS contacts;
+DROP TABLE IF EXISTS contact_types;
+DROP TABLE IF EXISTS people;
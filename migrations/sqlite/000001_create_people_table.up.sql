@@ -0,0 +1,61 @@
+CREATE TABLE people (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    first_name VARCHAR(255) NOT NULL,
+    second_name VARCHAR(255),
+    middle_name VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE contact_types (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO contact_types (name) VALUES
+    ('Email'),
+    ('Phone'),
+    ('Address'),
+    ('Website'),
+    ('Social Media');
+
+CREATE TABLE contacts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    contact_type_id INTEGER NOT NULL REFERENCES contact_types(id) ON DELETE RESTRICT,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_contacts_person_id ON contacts(person_id);
+CREATE INDEX idx_contacts_contact_type_id ON contacts(contact_type_id);
+
+CREATE TABLE connection_sources (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    meeting_story TEXT,
+    meeting_timestamp TIMESTAMP,
+    was_introduced BOOLEAN,
+    introducer_person_id INTEGER REFERENCES people(id) ON DELETE SET NULL,
+    introducer_name VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uk_connection_sources_person_id UNIQUE (person_id)
+);
+
+CREATE INDEX idx_connection_sources_introducer_person_id ON connection_sources(introducer_person_id);
+
+CREATE TABLE birth_date_info (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    birth_year INTEGER,
+    birth_month INTEGER CONSTRAINT birth_date_info_birth_month_check CHECK (birth_month >= 1 AND birth_month <= 12),
+    birth_day INTEGER CONSTRAINT birth_date_info_birth_day_check CHECK (birth_day >= 1 AND birth_day <= 31),
+    approximate_age INTEGER,
+    approximate_age_updated_at DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uk_birth_date_info_person_id UNIQUE (person_id)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_conversations_conversation_type_id;
+DROP INDEX IF EXISTS idx_conversations_person_id;
+DROP TABLE IF EXISTS conversations;
+DROP TABLE IF EXISTS conversation_types;
+
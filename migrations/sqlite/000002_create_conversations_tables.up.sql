@@ -0,0 +1,26 @@
+CREATE TABLE conversation_types (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO conversation_types (name) VALUES
+    ('Phone Call'),
+    ('Video Call'),
+    ('In-Person Meeting'),
+    ('Text Message'),
+    ('Email'),
+    ('Social Media');
+
+CREATE TABLE conversations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    conversation_type_id INTEGER NOT NULL REFERENCES conversation_types(id) ON DELETE RESTRICT,
+    initiator VARCHAR(16) NOT NULL CONSTRAINT conversations_initiator_check CHECK (initiator IN ('owner','person')),
+    notes TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_conversations_person_id ON conversations(person_id);
+CREATE INDEX idx_conversations_conversation_type_id ON conversations(conversation_type_id);
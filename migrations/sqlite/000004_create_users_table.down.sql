@@ -0,0 +1,59 @@
+-- The tables that gained user_id are rebuilt without it, as SQLite cannot
+-- drop a foreign key column. The records of every user are kept, so type
+-- names used by several users must be made unique first.
+CREATE TABLE conversation_types_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO conversation_types_old (id, name, created_at) SELECT id, name, created_at FROM conversation_types;
+DELETE FROM sqlite_sequence WHERE name = 'conversation_types_old';
+UPDATE sqlite_sequence SET name = 'conversation_types_old' WHERE name = 'conversation_types';
+DROP TABLE conversation_types;
+ALTER TABLE conversation_types_old RENAME TO conversation_types;
+
+CREATE TABLE contact_types_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO contact_types_old (id, name, created_at) SELECT id, name, created_at FROM contact_types;
+DELETE FROM sqlite_sequence WHERE name = 'contact_types_old';
+UPDATE sqlite_sequence SET name = 'contact_types_old' WHERE name = 'contact_types';
+DROP TABLE contact_types;
+ALTER TABLE contact_types_old RENAME TO contact_types;
+
+CREATE TABLE api_tokens_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    scope VARCHAR(16) NOT NULL CONSTRAINT api_tokens_scope_check CHECK (scope IN ('read','write')),
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO api_tokens_old (id, name, token_hash, scope, expires_at, last_used_at, created_at)
+SELECT id, name, token_hash, scope, expires_at, last_used_at, created_at FROM api_tokens;
+DELETE FROM sqlite_sequence WHERE name = 'api_tokens_old';
+UPDATE sqlite_sequence SET name = 'api_tokens_old' WHERE name = 'api_tokens';
+DROP TABLE api_tokens;
+ALTER TABLE api_tokens_old RENAME TO api_tokens;
+
+CREATE TABLE people_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    first_name VARCHAR(255) NOT NULL,
+    second_name VARCHAR(255),
+    middle_name VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO people_old (id, first_name, second_name, middle_name, created_at, updated_at)
+SELECT id, first_name, second_name, middle_name, created_at, updated_at FROM people;
+DELETE FROM sqlite_sequence WHERE name = 'people_old';
+UPDATE sqlite_sequence SET name = 'people_old' WHERE name = 'people';
+DROP TABLE people;
+ALTER TABLE people_old RENAME TO people;
+
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
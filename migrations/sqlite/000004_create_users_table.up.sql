@@ -0,0 +1,92 @@
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+-- Existing data is adopted by an "owner" account without a password.
+INSERT INTO users (username, password_hash)
+SELECT 'owner', '' WHERE EXISTS (SELECT 1 FROM people) OR EXISTS (SELECT 1 FROM api_tokens);
+
+-- SQLite cannot add a NOT NULL column with a foreign key or drop a UNIQUE
+-- constraint, so the tables that gain user_id are rebuilt. Each rebuild hands
+-- the AUTOINCREMENT counter of the old table to the new one before dropping
+-- it, so ids of deleted rows are not reused.
+CREATE TABLE people_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    first_name VARCHAR(255) NOT NULL,
+    second_name VARCHAR(255),
+    middle_name VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE
+);
+INSERT INTO people_new (id, first_name, second_name, middle_name, created_at, updated_at, user_id)
+SELECT id, first_name, second_name, middle_name, created_at, updated_at, (SELECT id FROM users WHERE username = 'owner')
+FROM people;
+DELETE FROM sqlite_sequence WHERE name = 'people_new';
+UPDATE sqlite_sequence SET name = 'people_new' WHERE name = 'people';
+DROP TABLE people;
+ALTER TABLE people_new RENAME TO people;
+CREATE INDEX idx_people_user_id ON people(user_id);
+
+CREATE TABLE api_tokens_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    scope VARCHAR(16) NOT NULL CONSTRAINT api_tokens_scope_check CHECK (scope IN ('read','write')),
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE
+);
+INSERT INTO api_tokens_new (id, name, token_hash, scope, expires_at, last_used_at, created_at, user_id)
+SELECT id, name, token_hash, scope, expires_at, last_used_at, created_at, (SELECT id FROM users WHERE username = 'owner')
+FROM api_tokens;
+DELETE FROM sqlite_sequence WHERE name = 'api_tokens_new';
+UPDATE sqlite_sequence SET name = 'api_tokens_new' WHERE name = 'api_tokens';
+DROP TABLE api_tokens;
+ALTER TABLE api_tokens_new RENAME TO api_tokens;
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+-- Types without a user are shared by everyone. Type names are unique per
+-- user and among the shared types.
+CREATE TABLE contact_types_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uk_contact_types_name UNIQUE (user_id, name)
+);
+INSERT INTO contact_types_new (id, name, created_at) SELECT id, name, created_at FROM contact_types;
+DELETE FROM sqlite_sequence WHERE name = 'contact_types_new';
+UPDATE sqlite_sequence SET name = 'contact_types_new' WHERE name = 'contact_types';
+DROP TABLE contact_types;
+ALTER TABLE contact_types_new RENAME TO contact_types;
+CREATE UNIQUE INDEX uk_contact_types_shared_name ON contact_types(name) WHERE user_id IS NULL;
+
+CREATE TABLE conversation_types_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uk_conversation_types_name UNIQUE (user_id, name)
+);
+INSERT INTO conversation_types_new (id, name, created_at) SELECT id, name, created_at FROM conversation_types;
+DELETE FROM sqlite_sequence WHERE name = 'conversation_types_new';
+UPDATE sqlite_sequence SET name = 'conversation_types_new' WHERE name = 'conversation_types';
+DROP TABLE conversation_types;
+ALTER TABLE conversation_types_new RENAME TO conversation_types;
+CREATE UNIQUE INDEX uk_conversation_types_shared_name ON conversation_types(name) WHERE user_id IS NULL;
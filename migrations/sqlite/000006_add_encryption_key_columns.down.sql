@@ -0,0 +1,6 @@
+ALTER TABLE conversations DROP COLUMN wrapped_key;
+ALTER TABLE conversations DROP COLUMN key_id;
+ALTER TABLE connection_sources DROP COLUMN wrapped_key;
+ALTER TABLE connection_sources DROP COLUMN key_id;
+ALTER TABLE contacts DROP COLUMN wrapped_key;
+ALTER TABLE contacts DROP COLUMN key_id;
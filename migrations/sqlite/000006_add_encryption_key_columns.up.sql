@@ -0,0 +1,6 @@
+ALTER TABLE contacts ADD COLUMN key_id VARCHAR(64);
+ALTER TABLE contacts ADD COLUMN wrapped_key TEXT;
+ALTER TABLE connection_sources ADD COLUMN key_id VARCHAR(64);
+ALTER TABLE connection_sources ADD COLUMN wrapped_key TEXT;
+ALTER TABLE conversations ADD COLUMN key_id VARCHAR(64);
+ALTER TABLE conversations ADD COLUMN wrapped_key TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE people DROP COLUMN job_title;
+ALTER TABLE people DROP COLUMN company;
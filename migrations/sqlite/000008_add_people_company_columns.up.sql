@@ -0,0 +1,2 @@
+ALTER TABLE people ADD COLUMN company VARCHAR(255);
+ALTER TABLE people ADD COLUMN job_title VARCHAR(255);
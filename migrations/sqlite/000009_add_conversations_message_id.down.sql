@@ -0,0 +1,2 @@
+DROP INDEX idx_conversations_person_message_id;
+ALTER TABLE conversations DROP COLUMN message_id;
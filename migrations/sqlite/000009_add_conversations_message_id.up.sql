@@ -0,0 +1,3 @@
+ALTER TABLE conversations ADD COLUMN message_id VARCHAR(998);
+
+CREATE UNIQUE INDEX idx_conversations_person_message_id ON conversations(person_id, message_id);